@@ -0,0 +1,11 @@
+#!/usr/bin/env bash
+
+set -euo pipefail
+
+# the real time kernel runs interrupt handlers in kernel threads named irq/<number>-<name>,
+# threads can exit while we move them, so the failure to move the single thread is not fatal
+for pid in $(pgrep '^irq/' || true); do
+    if ! taskset -pc "${RESERVED_CPUS}" "${pid}" > /dev/null; then
+        echo "WARNING: failed to move the IRQ thread ${pid} to CPUs ${RESERVED_CPUS}"
+    fi
+done
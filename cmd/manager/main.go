@@ -9,6 +9,7 @@ import (
 	"github.com/openshift-kni/performance-addon-operators/pkg/apis"
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller"
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/machineconfig"
 	"github.com/openshift-kni/performance-addon-operators/version"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -63,6 +64,14 @@ func main() {
 
 	printVersion()
 
+	// fail fast on broken operator image, instead of failing each profile reconcile
+	if err := machineconfig.ValidateAssets(components.AssetsDir); err != nil {
+		klog.Exit(err.Error())
+	}
+	if err := machineconfig.ValidateAssetsChecksums(components.AssetsDir); err != nil {
+		klog.Exit(err.Error())
+	}
+
 	namespace, err := k8sutil.GetWatchNamespace()
 	if err != nil {
 		klog.Exit(err.Error())
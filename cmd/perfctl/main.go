@@ -0,0 +1,203 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/machineconfig"
+	profileutil "github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/profile"
+	"github.com/openshift-kni/performance-addon-operators/pkg/utils/diagnostics"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	"github.com/ghodss/yaml"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+const (
+	// exitCodeMismatch is returned when the cluster state does not match the profile
+	exitCodeMismatch = 1
+	// exitCodeError is returned when the command fails to check the cluster state
+	exitCodeError = 2
+)
+
+const usage = `Usage: perfctl <command> [flags]
+
+Commands:
+  verify  checks that the cluster objects match the ones the performance profile generates
+  butane  prints files and systemd units the performance profile generates as the Butane config
+  bundle  prints the effective configuration of the performance profile and its nodes for support cases
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(exitCodeError)
+	}
+
+	switch os.Args[1] {
+	case "verify":
+		os.Exit(runVerify(os.Args[2:]))
+	case "butane":
+		os.Exit(runButane(os.Args[2:]))
+	case "bundle":
+		os.Exit(runBundle(os.Args[2:]))
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(exitCodeError)
+	}
+}
+
+func runVerify(args []string) int {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	profilePath := flags.String("f", "", "path to the performance profile manifest")
+	assetsDir := flags.String("assets-dir", "build/assets", "path to the operator assets directory")
+	if err := flags.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitCodeError
+	}
+
+	if *profilePath == "" {
+		fmt.Fprintln(os.Stderr, "the performance profile manifest should be specified with the -f flag")
+		return exitCodeError
+	}
+
+	profile, err := readProfile(*profilePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read the performance profile: %v\n", err)
+		return exitCodeError
+	}
+
+	c, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to the cluster: %v\n", err)
+		return exitCodeError
+	}
+
+	matches, err := verify(c, *assetsDir, profile, os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to verify the performance profile %q: %v\n", profile.Name, err)
+		return exitCodeError
+	}
+
+	if !matches {
+		return exitCodeMismatch
+	}
+	return 0
+}
+
+func runButane(args []string) int {
+	flags := flag.NewFlagSet("butane", flag.ExitOnError)
+	profilePath := flags.String("f", "", "path to the performance profile manifest")
+	assetsDir := flags.String("assets-dir", "build/assets", "path to the operator assets directory")
+	if err := flags.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitCodeError
+	}
+
+	if *profilePath == "" {
+		fmt.Fprintln(os.Stderr, "the performance profile manifest should be specified with the -f flag")
+		return exitCodeError
+	}
+
+	profile, err := readProfile(*profilePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read the performance profile: %v\n", err)
+		return exitCodeError
+	}
+
+	// the command renders profiles that are not applied yet, so the topology of nodes is unknown
+	if report := profileutil.Preflight(profile, nil); report.HasProblems() {
+		fmt.Fprintf(os.Stderr, "the performance profile %q does not produce a bootable configuration:\n%s\n", profile.Name, report)
+		return exitCodeError
+	}
+
+	butane, err := machineconfig.Butane(*assetsDir, profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to render the performance profile %q: %v\n", profile.Name, err)
+		return exitCodeError
+	}
+
+	if _, err := os.Stdout.Write(butane); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitCodeError
+	}
+	return 0
+}
+
+func runBundle(args []string) int {
+	flags := flag.NewFlagSet("bundle", flag.ExitOnError)
+	profilePath := flags.String("f", "", "path to the performance profile manifest")
+	assetsDir := flags.String("assets-dir", "build/assets", "path to the operator assets directory")
+	if err := flags.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitCodeError
+	}
+
+	if *profilePath == "" {
+		fmt.Fprintln(os.Stderr, "the performance profile manifest should be specified with the -f flag")
+		return exitCodeError
+	}
+
+	profile, err := readProfile(*profilePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read the performance profile: %v\n", err)
+		return exitCodeError
+	}
+
+	c, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to the cluster: %v\n", err)
+		return exitCodeError
+	}
+
+	bundle, err := diagnostics.SupportBundle(c, *assetsDir, profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to collect the performance profile %q support bundle: %v\n", profile.Name, err)
+		return exitCodeError
+	}
+
+	if _, err := os.Stdout.Write(bundle); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitCodeError
+	}
+	return 0
+}
+
+func readProfile(path string) (*performancev1.PerformanceProfile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &performancev1.PerformanceProfile{}
+	if err := yaml.Unmarshal(data, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+func newClient() (client.Client, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := mcov1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	return client.New(cfg, client.Options{Scheme: scheme})
+}
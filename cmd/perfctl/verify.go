@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/machineconfig"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// verify compares the machine config under the cluster with the one the profile generates now,
+// it writes the difference to the output and returns false when they do not match
+func verify(c client.Reader, assetsDir string, profile *performancev1.PerformanceProfile, out io.Writer) (bool, error) {
+	desired, err := machineconfig.New(assetsDir, profile)
+	if err != nil {
+		return false, err
+	}
+
+	existing := &mcov1.MachineConfig{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: desired.Name}, existing); err != nil {
+		if errors.IsNotFound(err) {
+			fmt.Fprintf(out, "MachineConfig %q is missing\n", desired.Name)
+			return false, nil
+		}
+		return false, err
+	}
+
+	changes, err := machineconfig.Diff(existing, desired)
+	if err != nil {
+		return false, err
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintf(out, "MachineConfig %q matches the performance profile %q\n", desired.Name, profile.Name)
+		return true, nil
+	}
+
+	fmt.Fprintf(out, "MachineConfig %q differs from the performance profile %q:\n", desired.Name, profile.Name)
+	for _, change := range changes {
+		fmt.Fprintf(out, "%s:\n", change.Path)
+		writeLines(out, "- ", change.Old)
+		writeLines(out, "+ ", change.New)
+	}
+	return false, nil
+}
+
+// writeLines writes every line of the multi-line value, like file contents, with the diff prefix
+func writeLines(out io.Writer, prefix string, value string) {
+	for _, line := range strings.Split(strings.TrimSuffix(value, "\n"), "\n") {
+		fmt.Fprintf(out, "%s%s\n", prefix, line)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/machineconfig"
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testAssetsDir = "../../build/assets"
+
+var _ = Describe("Verify", func() {
+	var profile *performancev1.PerformanceProfile
+	var scheme *runtime.Scheme
+	var out *bytes.Buffer
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+		profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(0)
+		scheme = runtime.NewScheme()
+		Expect(mcov1.AddToScheme(scheme)).To(Succeed())
+		out = &bytes.Buffer{}
+	})
+
+	It("should succeed when the machine config matches the profile", func() {
+		mc, err := machineconfig.New(testAssetsDir, profile)
+		Expect(err).ToNot(HaveOccurred())
+		c := fake.NewFakeClientWithScheme(scheme, mc)
+
+		matches, err := verify(c, testAssetsDir, profile, out)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(matches).To(BeTrue())
+		Expect(out.String()).To(ContainSubstring("matches the performance profile"))
+	})
+
+	It("should report the difference when the machine config does not match the profile", func() {
+		mc, err := machineconfig.New(testAssetsDir, profile)
+		Expect(err).ToNot(HaveOccurred())
+		c := fake.NewFakeClientWithScheme(scheme, mc)
+
+		profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+		profile.Spec.HugePages.Pages[0].Count = 8
+		matches, err := verify(c, testAssetsDir, profile, out)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(matches).To(BeFalse())
+		Expect(out.String()).To(ContainSubstring(`MachineConfig "performance-test" differs from the performance profile "test"`))
+		Expect(out.String()).To(ContainSubstring(fmt.Sprintf("spec.kernelType:\n- %s\n+ %s\n", machineconfig.MCKernelRT, machineconfig.MCKernelDefault)))
+		Expect(out.String()).To(ContainSubstring("spec.config.systemd.units[hugepages-allocation-1048576kB-NUMA0.service].contents:"))
+		Expect(out.String()).To(ContainSubstring("- Environment=HUGEPAGES_COUNT=4\n"))
+		Expect(out.String()).To(ContainSubstring("+ Environment=HUGEPAGES_COUNT=8\n"))
+	})
+
+	It("should report the missing machine config", func() {
+		c := fake.NewFakeClientWithScheme(scheme)
+
+		matches, err := verify(c, testAssetsDir, profile, out)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(matches).To(BeFalse())
+		Expect(out.String()).To(Equal("MachineConfig \"performance-test\" is missing\n"))
+	})
+})
@@ -1,3 +1,4 @@
+//go:build !unittests
 // +build !unittests
 
 package __performance_config_test
@@ -12,6 +13,7 @@ import (
 	ginkgo_reporters "kubevirt.io/qe-tools/pkg/ginkgo-reporters"
 
 	"github.com/openshift-kni/performance-addon-operators/functests/utils/junit"
+	"github.com/openshift-kni/performance-addon-operators/functests/utils/polarion"
 )
 
 func TestPerformanceConfig(t *testing.T) {
@@ -19,7 +21,7 @@ func TestPerformanceConfig(t *testing.T) {
 
 	rr := []Reporter{}
 	if ginkgo_reporters.Polarion.Run {
-		rr = append(rr, &ginkgo_reporters.Polarion)
+		rr = append(rr, &ginkgo_reporters.Polarion, polarion.NewMappingReporter("performance_config"))
 	}
 	rr = append(rr, junit.NewJUnitReporter("performance_config"))
 	RunSpecsWithDefaultAndCustomReporters(t, "Performance Addon Operator configuration", rr)
@@ -1,3 +1,4 @@
+//go:build !unittests
 // +build !unittests
 
 package __performance_update_test
@@ -19,6 +20,7 @@ import (
 	testclient "github.com/openshift-kni/performance-addon-operators/functests/utils/client"
 	"github.com/openshift-kni/performance-addon-operators/functests/utils/junit"
 	"github.com/openshift-kni/performance-addon-operators/functests/utils/namespaces"
+	"github.com/openshift-kni/performance-addon-operators/functests/utils/polarion"
 )
 
 var _ = BeforeSuite(func() {
@@ -43,7 +45,7 @@ func TestPerformanceUpdate(t *testing.T) {
 
 	rr := []Reporter{}
 	if ginkgo_reporters.Polarion.Run {
-		rr = append(rr, &ginkgo_reporters.Polarion)
+		rr = append(rr, &ginkgo_reporters.Polarion, polarion.NewMappingReporter("performance_update"))
 	}
 	rr = append(rr, junit.NewJUnitReporter("performance_update"))
 	RunSpecsWithDefaultAndCustomReporters(t, "Performance Addon Operator Update e2e tests", rr)
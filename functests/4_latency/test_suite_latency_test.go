@@ -1,3 +1,4 @@
+//go:build !unittests
 // +build !unittests
 
 package __latency_test
@@ -13,6 +14,7 @@ import (
 	testclient "github.com/openshift-kni/performance-addon-operators/functests/utils/client"
 	"github.com/openshift-kni/performance-addon-operators/functests/utils/junit"
 	"github.com/openshift-kni/performance-addon-operators/functests/utils/namespaces"
+	"github.com/openshift-kni/performance-addon-operators/functests/utils/polarion"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/klog"
@@ -42,7 +44,7 @@ func TestLatency(t *testing.T) {
 
 	rr := []Reporter{}
 	if ginkgo_reporters.Polarion.Run {
-		rr = append(rr, &ginkgo_reporters.Polarion)
+		rr = append(rr, &ginkgo_reporters.Polarion, polarion.NewMappingReporter("latency"))
 	}
 	rr = append(rr, junit.NewJUnitReporter("latency"))
 	RunSpecsWithDefaultAndCustomReporters(t, "Performance Addon Operator latency e2e tests", rr)
@@ -95,7 +95,9 @@ func ExecCommandOnMachineConfigDaemon(node *corev1.Node, command []string) ([]by
 		"--",
 	}
 	initialArgs = append(initialArgs, command...)
-	return testutils.ExecAndLogCommand("oc", initialArgs...)
+	return testutils.ExecWithRetry(func() ([]byte, error) {
+		return testutils.ExecAndLogCommand("oc", initialArgs...)
+	})
 }
 
 // ExecCommandOnNode executes given command on given node and returns the result
@@ -0,0 +1,96 @@
+package polarion
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/onsi/ginkgo/config"
+	"github.com/onsi/ginkgo/types"
+
+	"k8s.io/klog"
+)
+
+var mappingDir *string
+
+func init() {
+	mappingDir = flag.String("polarion-mapping-dir", ".", "the directory for the mapping of specs to Polarion test IDs")
+}
+
+// testIDRegex matches the Polarion test ID declared in the spec description, the Polarion reporter
+// uses the same test_id:<number> convention to fill the test case ID
+var testIDRegex = regexp.MustCompile(`\[test_id:(\d+)\]`)
+
+// WithTestID returns the spec description prefixed with the Polarion test ID, e.g. "[test_id:1234] description"
+func WithTestID(id int, description string) string {
+	return fmt.Sprintf("[test_id:%d] %s", id, description)
+}
+
+// ExtractTestID returns the Polarion test ID declared in the spec description,
+// it returns false when the description does not declare the test ID
+func ExtractTestID(description string) (string, bool) {
+	match := testIDRegex.FindStringSubmatch(description)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// MappingReporter collects Polarion test IDs of specs and writes the mapping of the spec name to the test ID
+// under the JSON file when the suite ends, specs without the test ID are mapped to an empty string
+type MappingReporter struct {
+	Filename string
+	Mapping  map[string]string
+}
+
+// NewMappingReporter returns the mapping reporter with the given name. testSuiteName must be a valid filename part
+func NewMappingReporter(testSuiteName string) *MappingReporter {
+	return &MappingReporter{
+		Filename: fmt.Sprintf("%s/%s_%s.json", *mappingDir, "polarion_mapping", testSuiteName),
+		Mapping:  map[string]string{},
+	}
+}
+
+// SpecSuiteWillBegin implements the ginkgo reporter interface
+func (reporter *MappingReporter) SpecSuiteWillBegin(config config.GinkgoConfigType, summary *types.SuiteSummary) {
+}
+
+// BeforeSuiteDidRun implements the ginkgo reporter interface
+func (reporter *MappingReporter) BeforeSuiteDidRun(setupSummary *types.SetupSummary) {
+}
+
+// SpecWillRun implements the ginkgo reporter interface
+func (reporter *MappingReporter) SpecWillRun(specSummary *types.SpecSummary) {
+}
+
+// SpecDidComplete records the test ID of the completed spec
+func (reporter *MappingReporter) SpecDidComplete(specSummary *types.SpecSummary) {
+	// the first component text is the suite description
+	if len(specSummary.ComponentTexts) < 2 {
+		return
+	}
+
+	name := strings.Join(specSummary.ComponentTexts[1:], " ")
+	testID, _ := ExtractTestID(name)
+	reporter.Mapping[name] = testID
+}
+
+// AfterSuiteDidRun implements the ginkgo reporter interface
+func (reporter *MappingReporter) AfterSuiteDidRun(setupSummary *types.SetupSummary) {
+}
+
+// SpecSuiteDidEnd writes the mapping file
+func (reporter *MappingReporter) SpecSuiteDidEnd(summary *types.SuiteSummary) {
+	data, err := json.MarshalIndent(reporter.Mapping, "", "  ")
+	if err != nil {
+		klog.Errorf("failed to generate the Polarion mapping: %v", err)
+		return
+	}
+
+	if err := ioutil.WriteFile(reporter.Filename, data, 0644); err != nil {
+		klog.Errorf("failed to write the Polarion mapping file %s: %v", reporter.Filename, err)
+	}
+}
@@ -0,0 +1,52 @@
+package polarion
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	"github.com/onsi/ginkgo/types"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Polarion", func() {
+	table.DescribeTable("should extract the test ID from the description",
+		func(description string, expectedID string, expectedFound bool) {
+			testID, found := ExtractTestID(description)
+			Expect(found).To(Equal(expectedFound))
+			Expect(testID).To(Equal(expectedID))
+		},
+		table.Entry("with the test ID prefix", "[test_id:30894] Tuned status field tied to Performance Profile", "30894", true),
+		table.Entry("with the test ID in the nested description", "Status testing [test_id:29673] Machine config pools status", "29673", true),
+		table.Entry("with the helper", WithTestID(1234, "should work"), "1234", true),
+		table.Entry("without the test ID", "should work", "", false),
+		table.Entry("with the malformed test ID", "[test_id:abc] should work", "", false),
+	)
+
+	It("should write the mapping of specs to test IDs", func() {
+		dir, err := ioutil.TempDir("", "polarion")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		reporter := &MappingReporter{
+			Filename: filepath.Join(dir, "mapping.json"),
+			Mapping:  map[string]string{},
+		}
+		reporter.SpecDidComplete(&types.SpecSummary{ComponentTexts: []string{"suite", "Status", WithTestID(1234, "should work")}})
+		reporter.SpecDidComplete(&types.SpecSummary{ComponentTexts: []string{"suite", "Status", "should not have the test ID"}})
+		reporter.SpecSuiteDidEnd(&types.SuiteSummary{})
+
+		data, err := ioutil.ReadFile(reporter.Filename)
+		Expect(err).ToNot(HaveOccurred())
+
+		mapping := map[string]string{}
+		Expect(json.Unmarshal(data, &mapping)).To(Succeed())
+		Expect(mapping).To(Equal(map[string]string{
+			"Status [test_id:1234] should work":  "1234",
+			"Status should not have the test ID": "",
+		}))
+	})
+})
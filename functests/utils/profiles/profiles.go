@@ -4,14 +4,27 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	. "github.com/onsi/gomega"
 
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
 	testclient "github.com/openshift-kni/performance-addon-operators/functests/utils/client"
 	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
 	v1 "github.com/openshift/custom-resource-status/conditions/v1"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 )
 
+// applyPollInterval is the interval between checks of the machine config generated for the applied profile
+var applyPollInterval = 5 * time.Second
+
 // GetByNodeLabels gets the performance profile that must have node selector equals to passed node labels
 func GetByNodeLabels(nodeLabels map[string]string) (*performancev1.PerformanceProfile, error) {
 	profiles, err := All()
@@ -56,3 +69,34 @@ func All() (*performancev1.PerformanceProfileList, error) {
 	}
 	return profiles, nil
 }
+
+// ApplyProfileAndWait creates the performance profile and waits until the operator generates the profile machine config,
+// the profile is deleted when the machine config does not appear during the timeout
+func ApplyProfileAndWait(c client.Client, profile *performancev1.PerformanceProfile, timeout time.Duration) (*mcov1.MachineConfig, error) {
+	if err := c.Create(context.TODO(), profile); err != nil {
+		return nil, err
+	}
+
+	key := types.NamespacedName{
+		Name:      components.GetComponentName(profile.Name, components.ComponentNamePrefix),
+		Namespace: metav1.NamespaceNone,
+	}
+	mc := &mcov1.MachineConfig{}
+	err := wait.PollImmediate(applyPollInterval, timeout, func() (bool, error) {
+		if err := c.Get(context.TODO(), key, mc); err != nil {
+			if errors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	})
+	if err == nil {
+		return mc, nil
+	}
+
+	if deleteErr := c.Delete(context.TODO(), profile); deleteErr != nil && !errors.IsNotFound(deleteErr) {
+		klog.Errorf("failed to delete the performance profile %q: %v", profile.Name, deleteErr)
+	}
+	return nil, fmt.Errorf("failed to wait for the machine config %q of the performance profile %q: %v", key.Name, profile.Name, err)
+}
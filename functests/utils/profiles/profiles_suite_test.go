@@ -0,0 +1,18 @@
+package profiles
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	// the functests runner passes junit and Polarion reporters flags to all suites
+	_ "kubevirt.io/qe-tools/pkg/ginkgo-reporters"
+
+	_ "github.com/openshift-kni/performance-addon-operators/functests/utils/junit"
+)
+
+func TestProfiles(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Profiles Suite")
+}
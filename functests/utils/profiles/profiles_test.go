@@ -0,0 +1,57 @@
+package profiles
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+)
+
+var _ = Describe("Profiles", func() {
+	var profile *performancev1.PerformanceProfile
+	var c client.Client
+
+	BeforeEach(func() {
+		applyPollInterval = 10 * time.Millisecond
+		profile = testutils.NewPerformanceProfile("test")
+	})
+
+	It("should create the profile and return the generated machine config", func() {
+		mc := &mcov1.MachineConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: components.GetComponentName(profile.Name, components.ComponentNamePrefix),
+			},
+		}
+		c = fake.NewFakeClientWithScheme(scheme.Scheme, mc)
+
+		appliedMC, err := ApplyProfileAndWait(c, profile, time.Second)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(appliedMC.Name).To(Equal(mc.Name))
+
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: profile.Name}, &performancev1.PerformanceProfile{})).To(Succeed())
+	})
+
+	It("should delete the profile when the machine config does not appear", func() {
+		c = fake.NewFakeClientWithScheme(scheme.Scheme)
+
+		_, err := ApplyProfileAndWait(c, profile, 50*time.Millisecond)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`failed to wait for the machine config "performance-test"`))
+
+		err = c.Get(context.TODO(), types.NamespacedName{Name: profile.Name}, &performancev1.PerformanceProfile{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+})
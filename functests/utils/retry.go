@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+)
+
+// ExecBackoff defines retries of exec operations that fail because of transient connection errors
+var ExecBackoff = wait.Backoff{
+	Steps:    5,
+	Duration: time.Second,
+	Factor:   2,
+	Jitter:   0.1,
+}
+
+// transientExecErrors contains parts of exec outputs that indicate transient connection errors,
+// the output of the failed command itself should not match them
+var transientExecErrors = []string{
+	"connection refused",
+	"connection reset by peer",
+	"i/o timeout",
+	"TLS handshake timeout",
+	"error dialing backend",
+	"unexpected EOF",
+	"Client.Timeout exceeded",
+}
+
+// ExecWithRetry runs the exec operation and retries it according to ExecBackoff while it fails because
+// of transient connection errors, other errors are returned immediately
+func ExecWithRetry(exec func() ([]byte, error)) ([]byte, error) {
+	var out []byte
+	var err error
+	backoffErr := wait.ExponentialBackoff(ExecBackoff, func() (bool, error) {
+		out, err = exec()
+		if err == nil || !isTransientExecError(out, err) {
+			return true, nil
+		}
+
+		klog.Warningf("exec failed with the transient error, retrying: %v", err)
+		return false, nil
+	})
+	if backoffErr != nil {
+		klog.Errorf("exec failed after %d attempts", ExecBackoff.Steps)
+	}
+	return out, err
+}
+
+func isTransientExecError(out []byte, err error) bool {
+	for _, transient := range transientExecErrors {
+		if strings.Contains(err.Error(), transient) || strings.Contains(string(out), transient) {
+			return true
+		}
+	}
+	return false
+}
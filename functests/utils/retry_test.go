@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+var _ = Describe("Exec retry", func() {
+	var attempts int
+
+	BeforeEach(func() {
+		attempts = 0
+		ExecBackoff = wait.Backoff{Steps: 3, Duration: time.Millisecond}
+	})
+
+	It("should retry transient failures until the success", func() {
+		out, err := ExecWithRetry(func() ([]byte, error) {
+			attempts++
+			if attempts < 3 {
+				return []byte("error: error dialing backend: dial tcp 10.0.0.1:10250: i/o timeout"), fmt.Errorf("exit status 1")
+			}
+			return []byte("hugepages=4"), nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(out)).To(Equal("hugepages=4"))
+		Expect(attempts).To(Equal(3))
+	})
+
+	It("should return the last transient error when retries are exhausted", func() {
+		_, err := ExecWithRetry(func() ([]byte, error) {
+			attempts++
+			return nil, fmt.Errorf("dial tcp 10.0.0.1:6443: connect: connection refused")
+		})
+		Expect(err).To(MatchError(ContainSubstring("connection refused")))
+		Expect(attempts).To(Equal(3))
+	})
+
+	It("should not retry command failures", func() {
+		_, err := ExecWithRetry(func() ([]byte, error) {
+			attempts++
+			return []byte("cat: /proc/foo: No such file or directory"), fmt.Errorf("exit status 1")
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(attempts).To(Equal(1))
+	})
+})
@@ -11,6 +11,58 @@ import (
 // objects.
 const PerformanceProfilePauseAnnotation = "performance.openshift.io/pause-reconcile"
 
+// PerformanceProfileStrictValidationAnnotation allows an admin to turn validation warnings
+// of the performance profile into errors.
+const PerformanceProfileStrictValidationAnnotation = "performance.openshift.io/strict-validation"
+
+// PerformanceProfileMaxIsolatedCPUsPercentageAnnotation allows an admin to change the maximal percentage
+// of online CPUs that the performance profile can isolate, defaults to 90.
+const PerformanceProfileMaxIsolatedCPUsPercentageAnnotation = "performance.openshift.io/max-isolated-cpus-percentage"
+
+// PerformanceProfileDriftDetectionOnlyAnnotation allows an admin to stop the operator from updating
+// performance profile owned objects, the operator only reports objects that differ from the desired state
+// under the Drifted condition.
+const PerformanceProfileDriftDetectionOnlyAnnotation = "performance.openshift.io/drift-detection-only"
+
+// PerformanceProfileForceSyncAnnotation allows an admin to force the update of the performance profile
+// machine config even when its content did not change, the value (usually the timestamp) is copied
+// to the machine config annotations and the operator removes the annotation from the profile afterwards.
+const PerformanceProfileForceSyncAnnotation = "performance.openshift.io/force-sync"
+
+// PerformanceProfileUnitDescriptionPrefixAnnotation allows an admin to prefix descriptions of systemd units
+// the performance profile generates, so monitoring can identify units of the specific profile.
+const PerformanceProfileUnitDescriptionPrefixAnnotation = "performance.openshift.io/unit-description-prefix"
+
+// PerformanceProfileNodeLabelAnnotation allows an admin to label nodes selected by the performance profile
+// with the PerformanceProfileNodeLabel, so workloads that require the tuned nodes can select them.
+const PerformanceProfileNodeLabelAnnotation = "performance.openshift.io/label-nodes"
+
+// PerformanceProfileArchitecturesAnnotation allows an admin to apply the performance profile to the mixed architecture
+// cluster, the value is the comma separated list of architectures. The operator generates the machine config with
+// architecture specific kernel arguments for each architecture, labeled with the machine config label value suffixed
+// by the architecture, so it can be targeted at the architecture specific pool.
+const PerformanceProfileArchitecturesAnnotation = "performance.openshift.io/architectures"
+
+// PerformanceProfileNodeLabel is the label of nodes tuned by the performance profile, the value is the profile name.
+const PerformanceProfileNodeLabel = "performance.openshift.io/profile"
+
+// NodeCoreSiblingsAnnotation provides the operator with hardware threads of each core of the annotated node,
+// the value is the list of cores separated by the semicolon, each core in the CPU list format, e.g. "0,4;1,5;2,6;3,7".
+// The operator validates the profile against the SMT siblings and the online CPUs of the profile nodes only when
+// the profile nodes are annotated.
+const NodeCoreSiblingsAnnotation = "performance.openshift.io/core-siblings"
+
+// NodeNUMANodesCPUsAnnotation provides the operator with CPUs of each NUMA node of the annotated node,
+// the value is the list of NUMA nodes ordered by the NUMA node ID and separated by the semicolon, each NUMA node
+// in the CPU list format, e.g. "0-3;4-7". The operator validates huge pages against the NUMA nodes of the profile
+// nodes only when the profile nodes are annotated.
+const NodeNUMANodesCPUsAnnotation = "performance.openshift.io/numa-nodes-cpus"
+
+// MachineConfigPoolCoordinatedRolloutAnnotation allows an admin to roll out changes of several performance
+// profiles at once, the operator pauses the annotated machine config pool while it updates profile objects
+// and unpauses it once no profile targeting the pool changed during the settle period.
+const MachineConfigPoolCoordinatedRolloutAnnotation = "performance.openshift.io/coordinated-rollout"
+
 // PerformanceProfileSpec defines the desired state of PerformanceProfile.
 type PerformanceProfileSpec struct {
 	// CPU defines a set of CPU related parameters.
@@ -37,12 +89,79 @@ type PerformanceProfileSpec struct {
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 	// RealTimeKernel defines a set of real time kernel related parameters. RT kernel won't be installed when not set.
 	RealTimeKernel *RealTimeKernel `json:"realTimeKernel,omitempty"`
-	// Addional kernel arguments.
+	// Addional kernel arguments. They follow kernel arguments derived from other fields and the kernel uses
+	// the last occurrence of the repeated argument, so the additional argument takes precedence,
+	// kernel arguments derived from workload hints with the same key are dropped.
 	// +optional
 	AdditionalKernelArgs []string `json:"additionalKernelArgs,omitempty"`
 	// NUMA defines options related to topology aware affinities
 	// +optional
 	NUMA *NUMA `json:"numa,omitempty"`
+	// PriorityClass defines options related to the PriorityClass created for performance sensitive workloads.
+	// PriorityClass won't be created when not set.
+	// +optional
+	PriorityClass *PriorityClass `json:"priorityClass,omitempty"`
+	// Architecture defines the CPU architecture of the nodes targeted by the performance profile,
+	// it is used to generate architecture specific kernel arguments.
+	// Supported values are "amd64", "arm64" and "ppc64le".
+	// Defaults to "amd64"
+	// +optional
+	Architecture *string `json:"architecture,omitempty"`
+	// ClockSource defines the kernel clock source, it is passed to the kernel via the clocksource boot argument.
+	// Supported values are "tsc", "hpet" and "acpi_pm".
+	// The kernel default clock source will be used when not set.
+	// +optional
+	ClockSource *string `json:"clockSource,omitempty"`
+	// TSCFrequencyKHz defines the TSC frequency in kHz, it is passed to the kernel via the tsc_early_khz boot argument
+	// together with the tsc=reliable boot argument, some virtualized real time environments can not calibrate the TSC.
+	// Should be greater than 0, the kernel calibrates the TSC frequency when not set.
+	// +optional
+	TSCFrequencyKHz *int32 `json:"tscFrequencyKHz,omitempty"`
+	// Memory defines options related to the kernel memory zones, used by memory hot-plug scenarios.
+	// +optional
+	Memory *Memory `json:"memory,omitempty"`
+	// ContainerRuntime defines options related to the container runtime tuning,
+	// the operator creates ContainerRuntimeConfig for the profile machine config pool when the pids limit is set.
+	// +optional
+	ContainerRuntime *ContainerRuntime `json:"containerRuntime,omitempty"`
+	// WorkloadHints defines the high level intent of workloads running on the nodes,
+	// the operator derives kernel arguments and the kernel type from the hints.
+	// Explicitly specified fields, like RealTimeKernel and AdditionalKernelArgs, override the derived values.
+	// +optional
+	WorkloadHints *WorkloadHints `json:"workloadHints,omitempty"`
+	// CgroupMode defines the cgroup hierarchy used by the nodes, can be "v1" or "v2".
+	// It maps to the 'systemd.unified_cgroup_hierarchy' kernel boot parameter, changing it reboots the nodes.
+	// The operating system default cgroup hierarchy will be used when not set.
+	// +optional
+	CgroupMode *CgroupMode `json:"cgroupMode,omitempty"`
+	// Mitigations defines the CPU vulnerabilities mitigations mode, can be "Auto", "Off" or "Full".
+	// It maps to the 'mitigations' kernel boot parameter, "Off" disables all mitigations and exposes the nodes
+	// to Spectre and Meltdown like attacks, "Full" additionally disables simultaneous multithreading.
+	// Defaults to "Auto", that keeps the kernel default mitigations.
+	// +optional
+	Mitigations *MitigationsMode `json:"mitigations,omitempty"`
+	// TransparentHugePages defines the transparent huge pages policy, can be "Always", "MAdvise" or "Never".
+	// It maps to the 'transparent_hugepage' kernel boot parameter and to the runtime policy the tuned applies.
+	// The kernel boot parameter is not added when not set and the tuned disables transparent huge pages.
+	// +optional
+	TransparentHugePages *TransparentHugePagesPolicy `json:"transparentHugePages,omitempty"`
+	// CrashKernelMemory defines the amount of memory reserved for the crash kernel used by kdump,
+	// it maps to the 'crashkernel' kernel boot parameter. The value should be the memory size, like 256M,
+	// or the list of memory ranges with the reserved size, like 1G-4G:160M,4G-:256M, with an optional @offset.
+	// The crash kernel memory is not reserved when not set.
+	// +optional
+	CrashKernelMemory *string `json:"crashKernelMemory,omitempty"`
+	// NUMABalancing defines the automatic NUMA balancing mode, can be "Enable" or "Disable".
+	// It maps to the 'numa_balancing' kernel boot parameter and to the runtime mode the tuned applies,
+	// RT and DPDK workloads usually disable it to avoid page migrations of pinned memory.
+	// The kernel boot parameter is not added when not set and the tuned disables automatic NUMA balancing.
+	// +optional
+	NUMABalancing *NUMABalancingMode `json:"numaBalancing,omitempty"`
+	// TuningUnits defines the service options of the systemd units the operator generates for the node tuning,
+	// like the huge pages allocation on NUMA nodes and the IRQ threads affinity.
+	// Defaults to "oneshot" units that remain active after the tuning script exits.
+	// +optional
+	TuningUnits *TuningUnits `json:"tuningUnits,omitempty"`
 }
 
 // CPUSet defines the set of CPUs(0-3,8-11).
@@ -58,8 +177,15 @@ type CPU struct {
 	// except the reserved CPUs. In order to guarantee that your workload will run on the isolated CPU:
 	//   1. The union of reserved CPUs and isolated CPUs should include all online CPUs
 	//   2. The isolated CPUs field should be the complementary to reserved CPUs field
+	// The field can be omitted only when the real time kernel is enabled, in this case all CPUs remain schedulable.
 	// +optional
 	Isolated *CPUSet `json:"isolated,omitempty"`
+	// IsolatedGroups defines named groups of CPUs dedicated to the specific purpose, like DPDK or real time threads.
+	// CPUs of all groups are isolated together with the Isolated CPUs, groups should not overlap each other
+	// and the reserved CPUs. The operator keeps groups under the RuntimeClass and the Tuned annotations,
+	// so other components can tune CPUs of each group differently.
+	// +optional
+	IsolatedGroups []IsolatedCPUGroup `json:"isolatedGroups,omitempty"`
 	// BalanceIsolated toggles whether or not the Isolated CPU set is eligible for load balancing work loads.
 	// When this option is set to "false", the Isolated CPU set will be static, meaning workloads have to
 	// explicitly assign each thread to a specific cpu in order to work across multiple CPUs.
@@ -69,9 +195,86 @@ type CPU struct {
 	// Defaults to "true"
 	// +optional
 	BalanceIsolated *bool `json:"balanceIsolated,omitempty"`
+	// IsolcpusFlags defines flags of the isolcpus kernel boot argument, that precede the isolated CPUs list.
+	// Supported flags are "domain", "managed_irq" and "nohz".
+	// When not set, the flags are derived from BalanceIsolated, "domain,managed_irq" for the static isolation
+	// and "managed_irq" otherwise.
+	// +optional
+	IsolcpusFlags []string `json:"isolcpusFlags,omitempty"`
+	// IRQExclude defines a subset of the reserved CPUs that should not handle device interrupts.
+	// The interrupts affinity will be set to the reserved CPUs without the excluded ones.
+	// +optional
+	IRQExclude *CPUSet `json:"irqExclude,omitempty"`
+	// IRQAffinity defines CPUs that handle device interrupts via the irqaffinity kernel argument, either
+	// as the CPU list, e.g. "0-1", or as the hexadecimal CPU mask with the 0x prefix, e.g. "0x3".
+	// It should be a subset of the reserved CPUs and can not be combined with IRQExclude.
+	// +optional
+	IRQAffinity *string `json:"irqAffinity,omitempty"`
+}
+
+// IsolatedCPUGroup defines the named group of isolated CPUs.
+type IsolatedCPUGroup struct {
+	// Name defines the name of the group, it should consist of lower case alphanumeric characters or '-'.
+	Name string `json:"name"`
+	// CPUs defines the set of CPUs of the group.
+	CPUs CPUSet `json:"cpus"`
 }
 
-// HugePageSize defines size of huge pages, can be 2M or 1G.
+// CgroupMode defines the cgroup hierarchy version, can be v1 or v2.
+type CgroupMode string
+
+const (
+	// CgroupModeV1 is the legacy cgroup hierarchy
+	CgroupModeV1 CgroupMode = "v1"
+	// CgroupModeV2 is the unified cgroup hierarchy
+	CgroupModeV2 CgroupMode = "v2"
+)
+
+// MitigationsMode defines the CPU vulnerabilities mitigations mode, can be Auto, Off or Full.
+type MitigationsMode string
+
+const (
+	// MitigationsAuto keeps the kernel default mitigations
+	MitigationsAuto MitigationsMode = "Auto"
+	// MitigationsOff disables all CPU vulnerabilities mitigations
+	MitigationsOff MitigationsMode = "Off"
+	// MitigationsFull enables all mitigations and disables simultaneous multithreading when needed
+	MitigationsFull MitigationsMode = "Full"
+)
+
+// TransparentHugePagesPolicy defines the transparent huge pages policy, can be Always, MAdvise or Never.
+type TransparentHugePagesPolicy string
+
+const (
+	// TransparentHugePagesAlways enables transparent huge pages for all memory regions
+	TransparentHugePagesAlways TransparentHugePagesPolicy = "Always"
+	// TransparentHugePagesMAdvise enables transparent huge pages only for memory regions marked with madvise
+	TransparentHugePagesMAdvise TransparentHugePagesPolicy = "MAdvise"
+	// TransparentHugePagesNever disables transparent huge pages
+	TransparentHugePagesNever TransparentHugePagesPolicy = "Never"
+)
+
+// NUMABalancingMode defines the automatic NUMA balancing mode, can be Enable or Disable.
+type NUMABalancingMode string
+
+const (
+	// NUMABalancingEnable enables automatic NUMA balancing
+	NUMABalancingEnable NUMABalancingMode = "Enable"
+	// NUMABalancingDisable disables automatic NUMA balancing
+	NUMABalancingDisable NUMABalancingMode = "Disable"
+)
+
+// TuningUnitType defines the systemd service type of the tuning units, can be oneshot or simple.
+type TuningUnitType string
+
+const (
+	// TuningUnitTypeOneshot delays units ordered after the tuning unit until the tuning process exits
+	TuningUnitTypeOneshot TuningUnitType = "oneshot"
+	// TuningUnitTypeSimple considers the tuning unit started once the tuning process runs
+	TuningUnitTypeSimple TuningUnitType = "simple"
+)
+
+// HugePageSize defines size of huge pages, can be 2M or 1G, or 16M or 16G on the ppc64le architecture.
 type HugePageSize string
 
 // HugePages defines a set of huge pages that we want to allocate at boot.
@@ -94,6 +297,18 @@ type HugePage struct {
 	Node *int32 `json:"node,omitempty"`
 }
 
+// Memory defines the amount of memory the kernel reserves for the movable and non-movable allocations.
+type Memory struct {
+	// KernelCore defines the amount of memory for non-movable allocations, maps to the 'kernelcore' kernel boot parameter.
+	// The value should be the memory size with an optional K, M, G, T, P or E suffix, or the percentage of the total memory.
+	// +optional
+	KernelCore *string `json:"kernelCore,omitempty"`
+	// MovableCore defines the amount of memory for movable allocations, maps to the 'movablecore' kernel boot parameter.
+	// The value should be the memory size with an optional K, M, G, T, P or E suffix, or the percentage of the total memory.
+	// +optional
+	MovableCore *string `json:"movableCore,omitempty"`
+}
+
 // NUMA defines parameters related to topology awareness and affinity.
 type NUMA struct {
 	// Name of the policy applied when TopologyManager is enabled
@@ -106,6 +321,64 @@ type NUMA struct {
 type RealTimeKernel struct {
 	// Enabled defines if the real time kernel packages should be installed. Defaults to "false"
 	Enabled *bool `json:"enabled,omitempty"`
+	// AdditionalArgs are kernel arguments that apply only to the real time kernel, they follow
+	// the additional kernel arguments and are ignored when the real time kernel is not enabled.
+	// +optional
+	AdditionalArgs []string `json:"additionalArgs,omitempty"`
+}
+
+// PriorityClass defines the set of parameters relevant for the PriorityClass created by the operator.
+type PriorityClass struct {
+	// Enabled defines if the operator should create the PriorityClass for performance sensitive workloads.
+	// Defaults to "false"
+	Enabled *bool `json:"enabled,omitempty"`
+	// Value defines the priority of pods that use the PriorityClass, it can not exceed the range
+	// reserved for system priority classes.
+	// Defaults to "1000000"
+	// +optional
+	Value *int32 `json:"value,omitempty"`
+}
+
+// ContainerRuntime defines the set of parameters relevant for the container runtime tuning.
+type ContainerRuntime struct {
+	// PidsLimit defines the maximum number of processes allowed in a container.
+	// It should be greater than or equal to 20.
+	// +optional
+	PidsLimit *int64 `json:"pidsLimit,omitempty"`
+	// AllowCPUQuotaAnnotation allows pods of the performance profile runtime class to disable the CFS quota
+	// of their containers with the cpu-quota.crio.io annotation, the operator adds the annotation to allowed
+	// annotations of the high-performance CRI-O runtime handler. Defaults to "false".
+	// +optional
+	AllowCPUQuotaAnnotation *bool `json:"allowCPUQuotaAnnotation,omitempty"`
+}
+
+// WorkloadHints defines the set of hints describing the workloads running on the nodes.
+type WorkloadHints struct {
+	// RealTime defines if workloads require the real time kernel and low latency tuning.
+	// +optional
+	RealTime *bool `json:"realTime,omitempty"`
+	// HighPowerConsumption defines if the node should trade the power consumption for the lower latency,
+	// by disabling deep CPU idle states.
+	// +optional
+	HighPowerConsumption *bool `json:"highPowerConsumption,omitempty"`
+	// PerPodPowerManagement defines if the CPU frequency scaling should be controlled per pod,
+	// it can not be used together with HighPowerConsumption.
+	// +optional
+	PerPodPowerManagement *bool `json:"perPodPowerManagement,omitempty"`
+}
+
+// TuningUnits defines the set of service options of the tuning systemd units.
+type TuningUnits struct {
+	// Type defines the systemd service type of the tuning units, can be "oneshot" or "simple".
+	// The "simple" type suits tuning processes that keep running, the kubelet starts without waiting for them.
+	// Defaults to "oneshot".
+	// +optional
+	Type *TuningUnitType `json:"type,omitempty"`
+	// RemainAfterExit defines if the tuning units stay active after the tuning process exits,
+	// it can not be enabled for the "simple" type, the unit would hide the exit of the long running process.
+	// Defaults to "true" for the "oneshot" type and to "false" for the "simple" type.
+	// +optional
+	RemainAfterExit *bool `json:"remainAfterExit,omitempty"`
 }
 
 // PerformanceProfileStatus defines the observed state of PerformanceProfile.
@@ -118,6 +391,18 @@ type PerformanceProfileStatus struct {
 	Tuned *string `json:"tuned,omitempty"`
 	// RuntimeClass contains the name of the RuntimeClass resource created by the operator.
 	RuntimeClass *string `json:"runtimeClass,omitempty"`
+	// IsolatedCPUCount contains the number of CPUs that the performance profile makes available for exclusive pinning,
+	// SMT siblings that go offline once SMT is disabled are not counted.
+	// +optional
+	IsolatedCPUCount *int32 `json:"isolatedCPUCount,omitempty"`
+	// HousekeepingCPUs contains CPUs that handle device interrupts and run system services once CPUs excluded
+	// from the interrupts handling and SMT siblings that go offline are dropped from reserved CPUs.
+	// +optional
+	HousekeepingCPUs *CPUSet `json:"housekeepingCPUs,omitempty"`
+	// RebootRequired indicates that nodes of the profile machine config pools did not apply
+	// the profile machine config yet, and should be rebooted to complete the tuning.
+	// +optional
+	RebootRequired bool `json:"rebootRequired,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
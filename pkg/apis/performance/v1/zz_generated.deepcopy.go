@@ -22,11 +22,31 @@ func (in *CPU) DeepCopyInto(out *CPU) {
 		*out = new(CPUSet)
 		**out = **in
 	}
+	if in.IsolatedGroups != nil {
+		in, out := &in.IsolatedGroups, &out.IsolatedGroups
+		*out = make([]IsolatedCPUGroup, len(*in))
+		copy(*out, *in)
+	}
 	if in.BalanceIsolated != nil {
 		in, out := &in.BalanceIsolated, &out.BalanceIsolated
 		*out = new(bool)
 		**out = **in
 	}
+	if in.IsolcpusFlags != nil {
+		in, out := &in.IsolcpusFlags, &out.IsolcpusFlags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IRQExclude != nil {
+		in, out := &in.IRQExclude, &out.IRQExclude
+		*out = new(CPUSet)
+		**out = **in
+	}
+	if in.IRQAffinity != nil {
+		in, out := &in.IRQAffinity, &out.IRQAffinity
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -40,6 +60,32 @@ func (in *CPU) DeepCopy() *CPU {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerRuntime) DeepCopyInto(out *ContainerRuntime) {
+	*out = *in
+	if in.PidsLimit != nil {
+		in, out := &in.PidsLimit, &out.PidsLimit
+		*out = new(int64)
+		**out = **in
+	}
+	if in.AllowCPUQuotaAnnotation != nil {
+		in, out := &in.AllowCPUQuotaAnnotation, &out.AllowCPUQuotaAnnotation
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerRuntime.
+func (in *ContainerRuntime) DeepCopy() *ContainerRuntime {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerRuntime)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HugePage) DeepCopyInto(out *HugePage) {
 	*out = *in
@@ -89,6 +135,48 @@ func (in *HugePages) DeepCopy() *HugePages {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IsolatedCPUGroup) DeepCopyInto(out *IsolatedCPUGroup) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IsolatedCPUGroup.
+func (in *IsolatedCPUGroup) DeepCopy() *IsolatedCPUGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(IsolatedCPUGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Memory) DeepCopyInto(out *Memory) {
+	*out = *in
+	if in.KernelCore != nil {
+		in, out := &in.KernelCore, &out.KernelCore
+		*out = new(string)
+		**out = **in
+	}
+	if in.MovableCore != nil {
+		in, out := &in.MovableCore, &out.MovableCore
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Memory.
+func (in *Memory) DeepCopy() *Memory {
+	if in == nil {
+		return nil
+	}
+	out := new(Memory)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NUMA) DeepCopyInto(out *NUMA) {
 	*out = *in
@@ -220,6 +308,71 @@ func (in *PerformanceProfileSpec) DeepCopyInto(out *PerformanceProfileSpec) {
 		*out = new(NUMA)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PriorityClass != nil {
+		in, out := &in.PriorityClass, &out.PriorityClass
+		*out = new(PriorityClass)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Architecture != nil {
+		in, out := &in.Architecture, &out.Architecture
+		*out = new(string)
+		**out = **in
+	}
+	if in.ClockSource != nil {
+		in, out := &in.ClockSource, &out.ClockSource
+		*out = new(string)
+		**out = **in
+	}
+	if in.TSCFrequencyKHz != nil {
+		in, out := &in.TSCFrequencyKHz, &out.TSCFrequencyKHz
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(Memory)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContainerRuntime != nil {
+		in, out := &in.ContainerRuntime, &out.ContainerRuntime
+		*out = new(ContainerRuntime)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkloadHints != nil {
+		in, out := &in.WorkloadHints, &out.WorkloadHints
+		*out = new(WorkloadHints)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CgroupMode != nil {
+		in, out := &in.CgroupMode, &out.CgroupMode
+		*out = new(CgroupMode)
+		**out = **in
+	}
+	if in.Mitigations != nil {
+		in, out := &in.Mitigations, &out.Mitigations
+		*out = new(MitigationsMode)
+		**out = **in
+	}
+	if in.TransparentHugePages != nil {
+		in, out := &in.TransparentHugePages, &out.TransparentHugePages
+		*out = new(TransparentHugePagesPolicy)
+		**out = **in
+	}
+	if in.CrashKernelMemory != nil {
+		in, out := &in.CrashKernelMemory, &out.CrashKernelMemory
+		*out = new(string)
+		**out = **in
+	}
+	if in.NUMABalancing != nil {
+		in, out := &in.NUMABalancing, &out.NUMABalancing
+		*out = new(NUMABalancingMode)
+		**out = **in
+	}
+	if in.TuningUnits != nil {
+		in, out := &in.TuningUnits, &out.TuningUnits
+		*out = new(TuningUnits)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -253,6 +406,16 @@ func (in *PerformanceProfileStatus) DeepCopyInto(out *PerformanceProfileStatus)
 		*out = new(string)
 		**out = **in
 	}
+	if in.IsolatedCPUCount != nil {
+		in, out := &in.IsolatedCPUCount, &out.IsolatedCPUCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.HousekeepingCPUs != nil {
+		in, out := &in.HousekeepingCPUs, &out.HousekeepingCPUs
+		*out = new(CPUSet)
+		**out = **in
+	}
 	return
 }
 
@@ -266,6 +429,32 @@ func (in *PerformanceProfileStatus) DeepCopy() *PerformanceProfileStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PriorityClass) DeepCopyInto(out *PriorityClass) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PriorityClass.
+func (in *PriorityClass) DeepCopy() *PriorityClass {
+	if in == nil {
+		return nil
+	}
+	out := new(PriorityClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RealTimeKernel) DeepCopyInto(out *RealTimeKernel) {
 	*out = *in
@@ -274,6 +463,11 @@ func (in *RealTimeKernel) DeepCopyInto(out *RealTimeKernel) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.AdditionalArgs != nil {
+		in, out := &in.AdditionalArgs, &out.AdditionalArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -286,3 +480,60 @@ func (in *RealTimeKernel) DeepCopy() *RealTimeKernel {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TuningUnits) DeepCopyInto(out *TuningUnits) {
+	*out = *in
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(TuningUnitType)
+		**out = **in
+	}
+	if in.RemainAfterExit != nil {
+		in, out := &in.RemainAfterExit, &out.RemainAfterExit
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TuningUnits.
+func (in *TuningUnits) DeepCopy() *TuningUnits {
+	if in == nil {
+		return nil
+	}
+	out := new(TuningUnits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadHints) DeepCopyInto(out *WorkloadHints) {
+	*out = *in
+	if in.RealTime != nil {
+		in, out := &in.RealTime, &out.RealTime
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HighPowerConsumption != nil {
+		in, out := &in.HighPowerConsumption, &out.HighPowerConsumption
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PerPodPowerManagement != nil {
+		in, out := &in.PerPodPowerManagement, &out.PerPodPowerManagement
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadHints.
+func (in *WorkloadHints) DeepCopy() *WorkloadHints {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadHints)
+	in.DeepCopyInto(out)
+	return out
+}
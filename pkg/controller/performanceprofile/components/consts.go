@@ -14,6 +14,25 @@ const (
 	MachineConfigRoleLabelKey = "machineconfiguration.openshift.io/role"
 )
 
+const (
+	// LabelManagedBy is the standard label of the tool that manages the object
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+	// LabelPartOf is the standard label of the application the object is part of
+	LabelPartOf = "app.kubernetes.io/part-of"
+	// ManagedByPerformanceOperator is the value of the managed-by label of objects generated by the operator
+	ManagedByPerformanceOperator = "performance-operator"
+	// PartOfPerformanceAddonOperator is the value of the part-of label of objects generated by the operator
+	PartOfPerformanceAddonOperator = "performance-addon-operator"
+)
+
+// IsolatedCPUGroupsAnnotation is the annotation of the generated RuntimeClass and Tuned that keeps isolated CPU groups
+// of the performance profile in the form of "name=cpus" pairs separated by semicolons, e.g. "dpdk=4-5;rt=6-7"
+const IsolatedCPUGroupsAnnotation = "performance.openshift.io/isolated-cpu-groups"
+
+// IsolatedCPUsAnnotation is the annotation of the generated MachineConfig and Tuned that keeps isolated CPUs
+// the object was generated for, both objects depend on isolated CPUs and should always carry the same value
+const IsolatedCPUsAnnotation = "performance.openshift.io/isolated-cpus"
+
 const (
 	// NamespaceNodeTuningOperator defines the tuned profiles namespace
 	NamespaceNodeTuningOperator = "openshift-cluster-node-tuning-operator"
@@ -26,4 +45,57 @@ const (
 	HugepagesSize2M = "2M"
 	// HugepagesSize1G contains the size of 1G hugepages
 	HugepagesSize1G = "1G"
+	// HugepagesSize16M contains the size of 16M hugepages
+	HugepagesSize16M = "16M"
+	// HugepagesSize16G contains the size of 16G hugepages
+	HugepagesSize16G = "16G"
+)
+
+const (
+	// ArchitectureAMD64 contains the name of the x86_64 architecture
+	ArchitectureAMD64 = "amd64"
+	// ArchitectureARM64 contains the name of the aarch64 architecture
+	ArchitectureARM64 = "arm64"
+	// ArchitecturePPC64LE contains the name of the little endian POWER architecture
+	ArchitecturePPC64LE = "ppc64le"
 )
+
+// IOMMUKernelArgs contains IOMMU pass-through kernel arguments per architecture,
+// the POWER architecture does not need any
+var IOMMUKernelArgs = map[string][]string{
+	ArchitectureAMD64:   {"intel_iommu=on", "iommu=pt"},
+	ArchitectureARM64:   {"iommu.passthrough=1"},
+	ArchitecturePPC64LE: {},
+}
+
+// CPUPartitioningKernelArgs contains kernel arguments the tuned profile sets by default for the CPU partitioning,
+// kernel arguments derived from the profile with the same key replace them
+var CPUPartitioningKernelArgs = []string{"intel_pstate=disable", "nosoftlockup"}
+
+// RealTimeKernelArgs contains kernel arguments the tuned profile sets by default for the real time workloads,
+// kernel arguments derived from the profile with the same key replace them
+var RealTimeKernelArgs = []string{"tsc=nowatchdog"}
+
+// HugepagesSizes contains huge pages sizes supported by the architecture
+var HugepagesSizes = map[string][]string{
+	ArchitectureAMD64:   {HugepagesSize1G, HugepagesSize2M},
+	ArchitectureARM64:   {HugepagesSize1G, HugepagesSize2M},
+	ArchitecturePPC64LE: {HugepagesSize16G, HugepagesSize16M},
+}
+
+// RealTimeKernelHugepagesSizes contains huge pages sizes supported by the real time kernel on the architecture,
+// the real time kernel is not shipped for the POWER architecture, so it does not support any size there
+var RealTimeKernelHugepagesSizes = map[string][]string{
+	ArchitectureAMD64:   {HugepagesSize1G, HugepagesSize2M},
+	ArchitectureARM64:   {HugepagesSize1G, HugepagesSize2M},
+	ArchitecturePPC64LE: {},
+}
+
+// RealTimeKernelRuntimeHugepagesSizes contains huge pages sizes the real time kernel reliably allocates once the node booted,
+// the real time kernel can fail to find contiguous memory for 1G huge pages at runtime, so they should be allocated
+// via kernel boot arguments
+var RealTimeKernelRuntimeHugepagesSizes = map[string][]string{
+	ArchitectureAMD64:   {HugepagesSize2M},
+	ArchitectureARM64:   {HugepagesSize2M},
+	ArchitecturePPC64LE: {},
+}
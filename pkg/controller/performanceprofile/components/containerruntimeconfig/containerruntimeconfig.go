@@ -0,0 +1,43 @@
+package containerruntimeconfig
+
+import (
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	profile2 "github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/profile"
+	machineconfigv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IsEnabled returns whether or not the ContainerRuntimeConfig should be created for the performance profile
+func IsEnabled(profile *performancev1.PerformanceProfile) bool {
+	return profile.Spec.ContainerRuntime != nil &&
+		profile.Spec.ContainerRuntime.PidsLimit != nil
+}
+
+// New returns new ContainerRuntimeConfig object that tunes the container runtime of the profile machine config pool
+func New(profile *performancev1.PerformanceProfile) *machineconfigv1.ContainerRuntimeConfig {
+	name := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+
+	containerRuntimeConfig := &machineconfigv1.ContainerRuntimeConfiguration{}
+	if profile.Spec.ContainerRuntime.PidsLimit != nil {
+		containerRuntimeConfig.PidsLimit = *profile.Spec.ContainerRuntime.PidsLimit
+	}
+
+	return &machineconfigv1.ContainerRuntimeConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: machineconfigv1.GroupVersion.String(),
+			Kind:       "ContainerRuntimeConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: components.GetComponentLabels(nil),
+		},
+		Spec: machineconfigv1.ContainerRuntimeConfigSpec{
+			MachineConfigPoolSelector: &metav1.LabelSelector{
+				MatchLabels: profile2.GetMachineConfigPoolSelector(profile),
+			},
+			ContainerRuntimeConfig: containerRuntimeConfig,
+		},
+	}
+}
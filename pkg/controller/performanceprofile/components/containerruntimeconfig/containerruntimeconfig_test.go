@@ -0,0 +1,51 @@
+package containerruntimeconfig
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("Container Runtime Config", func() {
+	var profile *performancev1.PerformanceProfile
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+	})
+
+	It("should be disabled when no tunables requested", func() {
+		Expect(IsEnabled(profile)).To(BeFalse())
+
+		profile.Spec.ContainerRuntime = &performancev1.ContainerRuntime{}
+		Expect(IsEnabled(profile)).To(BeFalse())
+
+		profile.Spec.ContainerRuntime.PidsLimit = pointer.Int64Ptr(8192)
+		Expect(IsEnabled(profile)).To(BeTrue())
+	})
+
+	It("should generate container runtime config with requested tunables", func() {
+		profile.Spec.ContainerRuntime = &performancev1.ContainerRuntime{
+			PidsLimit: pointer.Int64Ptr(8192),
+		}
+
+		containerRuntimeConfig := New(profile)
+		Expect(containerRuntimeConfig.Name).To(Equal(components.GetComponentName(profile.Name, components.ComponentNamePrefix)))
+		Expect(containerRuntimeConfig.Spec.ContainerRuntimeConfig.PidsLimit).To(Equal(int64(8192)))
+	})
+
+	It("should target the profile machine config pool", func() {
+		profile.Spec.ContainerRuntime = &performancev1.ContainerRuntime{
+			PidsLimit: pointer.Int64Ptr(8192),
+		}
+
+		containerRuntimeConfig := New(profile)
+		Expect(containerRuntimeConfig.Spec.MachineConfigPoolSelector.MatchLabels).To(Equal(map[string]string{
+			testutils.MachineConfigPoolLabelKey: testutils.MachineConfigPoolLabelValue,
+		}))
+	})
+})
@@ -2,6 +2,7 @@ package kubeletconfig
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
@@ -44,7 +45,11 @@ func New(profile *performancev1.PerformanceProfile) (*machineconfigv1.KubeletCon
 	}
 
 	if profile.Spec.CPU != nil && profile.Spec.CPU.Reserved != nil {
-		kubeletConfig.ReservedSystemCPUs = string(*profile.Spec.CPU.Reserved)
+		reserved, err := components.NormalizeCPUList(string(*profile.Spec.CPU.Reserved))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse reserved CPUs: %v", err)
+		}
+		kubeletConfig.ReservedSystemCPUs = reserved
 	}
 
 	if profile.Spec.NUMA != nil {
@@ -64,7 +69,8 @@ func New(profile *performancev1.PerformanceProfile) (*machineconfigv1.KubeletCon
 			Kind:       "KubeletConfig",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name: name,
+			Name:   name,
+			Labels: components.GetComponentLabels(nil),
 		},
 		Spec: machineconfigv1.KubeletConfigSpec{
 			MachineConfigPoolSelector: &metav1.LabelSelector{
@@ -7,6 +7,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
 	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
 )
@@ -28,4 +29,17 @@ var _ = Describe("Kubelet Config", func() {
 		Expect(manifest).To(ContainSubstring("topologyManagerPolicy: single-numa-node"))
 		Expect(manifest).To(ContainSubstring("cpuManagerPolicy: static"))
 	})
+
+	It("should normalize reserved CPUs", func() {
+		profile := testutils.NewPerformanceProfile("test")
+		reserved := performancev1.CPUSet("0, 1 ,2,3")
+		profile.Spec.CPU.Reserved = &reserved
+
+		kc, err := New(profile)
+		Expect(err).ToNot(HaveOccurred())
+
+		y, err := yaml.Marshal(kc)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(y)).To(ContainSubstring("reservedSystemCPUs: 0-3"))
+	})
 })
@@ -0,0 +1,100 @@
+package machineconfig
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ghodss/yaml"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+)
+
+const (
+	// butaneVariant is the Butane variant of the generated config, the Fedora CoreOS one has files and systemd
+	// units with the same fields the ignition config of the machine config has
+	butaneVariant = "fcos"
+	// butaneVersion is the Butane config version of the generated config
+	butaneVersion = "1.0.0"
+)
+
+type butaneConfig struct {
+	Variant string        `json:"variant"`
+	Version string        `json:"version"`
+	Storage butaneStorage `json:"storage,omitempty"`
+	Systemd butaneSystemd `json:"systemd,omitempty"`
+}
+
+type butaneStorage struct {
+	Files []butaneFile `json:"files,omitempty"`
+}
+
+type butaneFile struct {
+	Path     string             `json:"path"`
+	Mode     *int               `json:"mode,omitempty"`
+	Contents butaneFileContents `json:"contents"`
+}
+
+type butaneFileContents struct {
+	Inline string `json:"inline,omitempty"`
+	Source string `json:"source,omitempty"`
+}
+
+type butaneSystemd struct {
+	Units []butaneUnit `json:"units,omitempty"`
+}
+
+type butaneUnit struct {
+	Name     string `json:"name"`
+	Enabled  *bool  `json:"enabled,omitempty"`
+	Contents string `json:"contents,omitempty"`
+}
+
+// Butane returns the storage files and systemd units of the machine config as the Butane YAML config,
+// the content is generated the same way as for the machine config, so both always match,
+// file contents generated by the operator are inlined and other sources are kept as is
+func Butane(assetsDir string, profile *performancev1.PerformanceProfile) ([]byte, error) {
+	ignitionConfig, err := getIgnitionConfig(assetsDir, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &butaneConfig{
+		Variant: butaneVariant,
+		Version: butaneVersion,
+	}
+
+	for _, file := range ignitionConfig.Storage.Files {
+		if file.Filesystem != defaultFileSystem {
+			return nil, fmt.Errorf("the file %q is placed under the filesystem %q, Butane supports only the root filesystem", file.Path, file.Filesystem)
+		}
+
+		butaneFile := butaneFile{
+			Path: file.Path,
+			Mode: file.Mode,
+		}
+		if content := decodeFileContents(file.Contents.Source); content != file.Contents.Source {
+			butaneFile.Contents.Inline = content
+		} else {
+			butaneFile.Contents.Source = file.Contents.Source
+		}
+		config.Storage.Files = append(config.Storage.Files, butaneFile)
+	}
+
+	for _, unit := range ignitionConfig.Systemd.Units {
+		config.Systemd.Units = append(config.Systemd.Units, butaneUnit{
+			Name:     unit.Name,
+			Enabled:  unit.Enabled,
+			Contents: unit.Contents,
+		})
+	}
+
+	// the same profile should always give the same output, see IgnitionBytes
+	sort.SliceStable(config.Storage.Files, func(i, j int) bool {
+		return config.Storage.Files[i].Path < config.Storage.Files[j].Path
+	})
+	sort.SliceStable(config.Systemd.Units, func(i, j int) bool {
+		return config.Systemd.Units[i].Name < config.Systemd.Units[j].Name
+	})
+
+	return yaml.Marshal(config)
+}
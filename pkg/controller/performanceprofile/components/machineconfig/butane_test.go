@@ -0,0 +1,73 @@
+package machineconfig
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/utils/pointer"
+
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+)
+
+var _ = Describe("Machine Config Butane", func() {
+	It("should render files and units that round-trip to the same ignition config", func() {
+		profile := testutils.NewPerformanceProfile("test")
+		profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(0)
+
+		rawIgnition, err := IgnitionBytes(testAssetsDir, profile)
+		Expect(err).ToNot(HaveOccurred())
+		expected := &igntypes.Config{}
+		Expect(json.Unmarshal(rawIgnition, expected)).To(Succeed())
+
+		rawButane, err := Butane(testAssetsDir, profile)
+		Expect(err).ToNot(HaveOccurred())
+		config := &butaneConfig{}
+		Expect(yaml.Unmarshal(rawButane, config)).To(Succeed())
+		Expect(config.Variant).To(Equal(butaneVariant))
+		Expect(config.Version).To(Equal(butaneVersion))
+
+		ignitionConfig := butaneToIgnition(config)
+		Expect(ignitionConfig.Storage.Files).To(Equal(expected.Storage.Files))
+		Expect(ignitionConfig.Systemd.Units).To(Equal(expected.Systemd.Units))
+
+		// the hugepages allocation unit should be rendered as is
+		Expect(string(rawButane)).To(ContainSubstring("name: hugepages-allocation-1048576kB-NUMA0.service"))
+	})
+})
+
+// butaneToIgnition translates the Butane config the same way the Butane tool translates files and units
+func butaneToIgnition(config *butaneConfig) *igntypes.Config {
+	ignitionConfig := &igntypes.Config{}
+	for _, file := range config.Storage.Files {
+		source := file.Contents.Source
+		if source == "" {
+			source = fmt.Sprintf("%s,%s", defaultIgnitionContentSource, base64.StdEncoding.EncodeToString([]byte(file.Contents.Inline)))
+		}
+
+		ignitionConfig.Storage.Files = append(ignitionConfig.Storage.Files, igntypes.File{
+			Node: igntypes.Node{
+				Filesystem: defaultFileSystem,
+				Path:       file.Path,
+			},
+			FileEmbedded1: igntypes.FileEmbedded1{
+				Contents: igntypes.FileContents{Source: source},
+				Mode:     file.Mode,
+			},
+		})
+	}
+
+	for _, unit := range config.Systemd.Units {
+		ignitionConfig.Systemd.Units = append(ignitionConfig.Systemd.Units, igntypes.Unit{
+			Name:     unit.Name,
+			Enabled:  unit.Enabled,
+			Contents: unit.Contents,
+		})
+	}
+	return ignitionConfig
+}
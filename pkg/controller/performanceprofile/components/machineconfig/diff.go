@@ -0,0 +1,125 @@
+package machineconfig
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
+	profile2 "github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/profile"
+	machineconfigv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+)
+
+// Diff returns changes between the existing machine config and the desired one sorted by the field path,
+// only fields the operator sets are compared, the ignition config is compared by files and systemd units
+func Diff(existing *machineconfigv1.MachineConfig, desired *machineconfigv1.MachineConfig) ([]profile2.FieldChange, error) {
+	var changes []profile2.FieldChange
+	addChange := func(path string, oldValue string, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, profile2.FieldChange{Path: path, Old: oldValue, New: newValue})
+		}
+	}
+
+	for key, value := range desired.Labels {
+		addChange(fmt.Sprintf("metadata.labels.%s", key), existing.Labels[key], value)
+	}
+	addChange("spec.kernelType", existing.Spec.KernelType, desired.Spec.KernelType)
+	addChange("spec.kernelArguments", strings.Join(existing.Spec.KernelArguments, " "), strings.Join(desired.Spec.KernelArguments, " "))
+	addChange("spec.fips", strconv.FormatBool(existing.Spec.FIPS), strconv.FormatBool(desired.Spec.FIPS))
+	addChange("spec.osImageURL", existing.Spec.OSImageURL, desired.Spec.OSImageURL)
+
+	if !bytes.Equal(existing.Spec.Config.Raw, desired.Spec.Config.Raw) {
+		changesCount := len(changes)
+		existingFields, err := flattenIgnitionConfig(existing.Spec.Config.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the existing ignition config: %v", err)
+		}
+		desiredFields, err := flattenIgnitionConfig(desired.Spec.Config.Raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the desired ignition config: %v", err)
+		}
+
+		for path, value := range desiredFields {
+			addChange(path, existingFields[path], value)
+		}
+		for path, value := range existingFields {
+			if _, ok := desiredFields[path]; !ok {
+				addChange(path, value, "")
+			}
+		}
+
+		// the API server can reorder keys of the raw config, so only the semantic difference is reported
+		if !equalJSON(existing.Spec.Config.Raw, desired.Spec.Config.Raw) && len(changes) == changesCount {
+			addChange("spec.config", string(existing.Spec.Config.Raw), string(desired.Spec.Config.Raw))
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+// flattenIgnitionConfig returns files and systemd units of the ignition config keyed by the field path,
+// file contents are decoded, so the difference is readable
+func flattenIgnitionConfig(raw []byte) (map[string]string, error) {
+	fields := map[string]string{}
+	if len(raw) == 0 {
+		return fields, nil
+	}
+
+	config := &igntypes.Config{}
+	if err := json.Unmarshal(raw, config); err != nil {
+		return nil, err
+	}
+
+	for _, file := range config.Storage.Files {
+		mode := ""
+		if file.Mode != nil {
+			mode = fmt.Sprintf("%#o", *file.Mode)
+		}
+		fields[fmt.Sprintf("spec.config.storage.files[%s].mode", file.Path)] = mode
+		fields[fmt.Sprintf("spec.config.storage.files[%s].contents", file.Path)] = decodeFileContents(file.Contents.Source)
+	}
+
+	for _, unit := range config.Systemd.Units {
+		enabled := ""
+		if unit.Enabled != nil {
+			enabled = strconv.FormatBool(*unit.Enabled)
+		}
+		fields[fmt.Sprintf("spec.config.systemd.units[%s].enabled", unit.Name)] = enabled
+		fields[fmt.Sprintf("spec.config.systemd.units[%s].contents", unit.Name)] = unit.Contents
+	}
+	return fields, nil
+}
+
+// equalJSON returns true when both documents have the same values regardless of the keys order
+func equalJSON(a []byte, b []byte) bool {
+	var aValue, bValue interface{}
+	if err := json.Unmarshal(a, &aValue); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(b, &bValue); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(aValue, bValue)
+}
+
+// decodeFileContents returns the content of the file generated by the operator, other sources are returned as is
+func decodeFileContents(source string) string {
+	prefix := defaultIgnitionContentSource + ","
+	if !strings.HasPrefix(source, prefix) {
+		return source
+	}
+
+	content, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(source, prefix))
+	if err != nil {
+		return source
+	}
+	return string(content)
+}
@@ -0,0 +1,100 @@
+package machineconfig
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/utils/pointer"
+
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+	machineconfigv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+)
+
+var _ = Describe("Machine Config diff", func() {
+	var existing *machineconfigv1.MachineConfig
+
+	BeforeEach(func() {
+		profile := testutils.NewPerformanceProfile("test")
+		profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(0)
+
+		var err error
+		existing, err = New(testAssetsDir, profile)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should not report changes of the same machine config", func() {
+		changes, err := Diff(existing, existing.DeepCopy())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(BeEmpty())
+	})
+
+	It("should not report changes of the reordered ignition config keys", func() {
+		var config map[string]interface{}
+		Expect(json.Unmarshal(existing.Spec.Config.Raw, &config)).To(Succeed())
+		// maps are marshaled with sorted keys, that differs from the ignition types fields order
+		raw, err := json.Marshal(config)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(raw).ToNot(Equal(existing.Spec.Config.Raw))
+
+		desired := existing.DeepCopy()
+		desired.Spec.Config.Raw = raw
+		changes, err := Diff(existing, desired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(BeEmpty())
+	})
+
+	It("should report changed fields sorted by the path", func() {
+		desired := existing.DeepCopy()
+		desired.Spec.KernelType = MCKernelDefault
+		desired.Labels["custom"] = "value"
+
+		changes, err := Diff(existing, desired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(HaveLen(2))
+		Expect(changes[0].Path).To(Equal("metadata.labels.custom"))
+		Expect(changes[0].Old).To(BeEmpty())
+		Expect(changes[0].New).To(Equal("value"))
+		Expect(changes[1].Path).To(Equal("spec.kernelType"))
+		Expect(changes[1].Old).To(Equal(MCKernelRT))
+		Expect(changes[1].New).To(Equal(MCKernelDefault))
+	})
+
+	It("should report removed systemd units", func() {
+		profile := testutils.NewPerformanceProfile("test")
+		desired, err := New(testAssetsDir, profile)
+		Expect(err).ToNot(HaveOccurred())
+
+		changes, err := Diff(existing, desired)
+		Expect(err).ToNot(HaveOccurred())
+
+		paths := map[string]string{}
+		for _, change := range changes {
+			paths[change.Path] = change.New
+		}
+		Expect(paths).To(HaveKeyWithValue("spec.config.systemd.units[hugepages-allocation-1048576kB-NUMA0.service].contents", ""))
+		Expect(paths).To(HaveKeyWithValue("spec.config.systemd.units[hugepages-allocation-1048576kB-NUMA0.service].enabled", ""))
+	})
+	It("should report decoded contents of changed files", func() {
+		config := &igntypes.Config{}
+		Expect(json.Unmarshal(existing.Spec.Config.Raw, config)).To(Succeed())
+		Expect(config.Storage.Files).ToNot(BeEmpty())
+		path := config.Storage.Files[0].Path
+		config.Storage.Files[0].Contents.Source = fmt.Sprintf("%s,%s", defaultIgnitionContentSource, base64.StdEncoding.EncodeToString([]byte("#!/bin/bash\n")))
+
+		raw, err := json.Marshal(config)
+		Expect(err).ToNot(HaveOccurred())
+		desired := existing.DeepCopy()
+		desired.Spec.Config.Raw = raw
+
+		changes, err := Diff(existing, desired)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(HaveLen(1))
+		Expect(changes[0].Path).To(Equal(fmt.Sprintf("spec.config.storage.files[%s].contents", path)))
+		Expect(changes[0].New).To(Equal("#!/bin/bash\n"))
+	})
+})
@@ -1,11 +1,19 @@
 package machineconfig
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/coreos/go-systemd/unit"
 	igntypes "github.com/coreos/ignition/config/v2_2/types"
@@ -15,7 +23,13 @@ import (
 	machineconfigv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
 	"k8s.io/utils/pointer"
 )
 
@@ -23,6 +37,8 @@ const (
 	defaultIgnitionVersion       = "2.2.0"
 	defaultFileSystem            = "root"
 	defaultIgnitionContentSource = "data:text/plain;charset=utf-8;base64"
+	maxFileMode                  = 0777
+	executableFileMode           = 0111
 )
 
 const (
@@ -32,11 +48,27 @@ const (
 	MCKernelDefault = "default"
 	// HighPerformanceRuntime contains the name of the CPU load balancing runtime
 	HighPerformanceRuntime = "high-performance"
+	// ProfileGenerationAnnotation contains the generation of the performance profile that last changed the machine config,
+	// profile updates that do not change the machine config do not update the annotation
+	ProfileGenerationAnnotation = "performance.openshift.io/profile-generation"
+
+	// renderedNamePrefix is the prefix of machine configs that the machine config operator renders for pools
+	renderedNamePrefix = "rendered-"
 
 	hugepagesAllocation = "hugepages-allocation"
+	irqThreadsAffinity  = "irq-threads-affinity"
 	bashScriptsDir      = "/usr/local/bin"
 	crioConfd           = "/etc/crio/crio.conf.d"
 	crioRuntimesConfig  = "99-runtimes"
+	sysctlD             = "/etc/sysctl.d"
+	rtThrottlingSysctl  = "99-performance-rt-throttling"
+)
+
+const (
+	// crioHighPerformanceRuntimeTable is the table of the high-performance runtime handler under the CRI-O config
+	crioHighPerformanceRuntimeTable = "[crio.runtime.runtimes.high-performance]"
+	// crioCPUQuotaAnnotation is the pod annotation that disables the CFS quota of the pod containers
+	crioCPUQuotaAnnotation = "cpu-quota.crio.io"
 )
 
 const (
@@ -45,6 +77,7 @@ const (
 	systemdSectionInstall  = "Install"
 	systemdDescription     = "Description"
 	systemdBefore          = "Before"
+	systemdAfter           = "After"
 	systemdEnvironment     = "Environment"
 	systemdType            = "Type"
 	systemdRemainAfterExit = "RemainAfterExit"
@@ -53,21 +86,66 @@ const (
 )
 
 const (
-	systemdServiceKubelet     = "kubelet.service"
-	systemdServiceTypeOneshot = "oneshot"
-	systemdTargetMultiUser    = "multi-user.target"
-	systemdTrue               = "true"
+	systemdServiceKubelet  = "kubelet.service"
+	systemdTargetMultiUser = "multi-user.target"
 )
 
 const (
 	environmentHugepagesSize  = "HUGEPAGES_SIZE"
 	environmentHugepagesCount = "HUGEPAGES_COUNT"
 	environmentNUMANode       = "NUMA_NODE"
+	environmentReservedCPUs   = "RESERVED_CPUS"
 )
 
+// maxSystemdEnvironmentLength is the longest environment assignment of the systemd unit, systemd versions older
+// than v236 read unit file lines up to LINE_MAX bytes and silently drop the rest of the line
+const maxSystemdEnvironmentLength = 2048
+
+// mcoTemplateNameRegex matches names of machine configs that the machine config operator generates from templates,
+// e.g. 00-worker, 01-worker-kubelet or 99-worker-generated-registries
+var mcoTemplateNameRegex = regexp.MustCompile(`^[0-9]+-`)
+
+// reservedNamePrefixes contains prefixes of machine config names that the machine config operator owns,
+// besides numeric prefixes matched by the mcoTemplateNameRegex
+var reservedNamePrefixes = []string{renderedNamePrefix}
+
+// unitsBuilder returns systemd units that run the script on the node
+type unitsBuilder func(profile *performancev1.PerformanceProfile) ([]igntypes.Unit, error)
+
+// script describes the script copied under the node together with the builder of systemd units that run it
+// and the expected SHA-256 checksum of the script asset, update it with sha256sum once the script changes
+type script struct {
+	name     string
+	checksum string
+	units    unitsBuilder
+}
+
+// scripts contains all scripts that the machine config provides, adding a new script requires only a new entry
+var scripts = []script{
+	{
+		name:     hugepagesAllocation,
+		checksum: "0e8e1455bc12eb2c2ba4feb2bb2a67a8c19b25056fd72d4100438cab2b3109a8",
+		units:    getHugepagesAllocationUnits,
+	},
+	{
+		name:     irqThreadsAffinity,
+		checksum: "8711ff056d5178e87330950abbe842088f733210d6ba634753ba3cbbdf471cf5",
+		units:    getIRQThreadsAffinityUnits,
+	},
+}
+
 // New returns new machine configuration object for performance sensetive workflows
 func New(assetsDir string, profile *performancev1.PerformanceProfile) (*machineconfigv1.MachineConfig, error) {
 	name := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	isolated, err := profile2.GetIsolatedCPUs(profile)
+	if err != nil {
+		return nil, err
+	}
+
 	mc := &machineconfigv1.MachineConfig{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: machineconfigv1.GroupVersion.String(),
@@ -75,33 +153,231 @@ func New(assetsDir string, profile *performancev1.PerformanceProfile) (*machinec
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   name,
-			Labels: profile2.GetMachineConfigLabel(profile),
+			Labels: components.GetComponentLabels(profile2.GetMachineConfigLabel(profile)),
+			Annotations: map[string]string{
+				ProfileGenerationAnnotation:       strconv.FormatInt(profile.Generation, 10),
+				components.IsolatedCPUsAnnotation: isolated.String(),
+			},
 		},
 		Spec: machineconfigv1.MachineConfigSpec{},
 	}
 
-	ignitionConfig, err := getIgnitionConfig(assetsDir, profile)
+	// the changed annotation forces the update of the existing machine config
+	if forceSync, ok := profile.Annotations[performancev1.PerformanceProfileForceSyncAnnotation]; ok {
+		mc.Annotations[performancev1.PerformanceProfileForceSyncAnnotation] = forceSync
+	}
+
+	rawIgnition, err := IgnitionBytes(assetsDir, profile)
 	if err != nil {
 		return nil, err
 	}
+	mc.Spec.Config = runtime.RawExtension{Raw: rawIgnition}
+
+	mc.Spec.KernelType = GetKernelType(profile)
+
+	return mc, nil
+}
+
+// GetKernelType returns the kernel type of the machine config generated for the profile
+func GetKernelType(profile *performancev1.PerformanceProfile) string {
+	if profile2.IsRealTimeKernelEnabled(profile) {
+		return MCKernelRT
+	}
+	return MCKernelDefault
+}
 
-	rawIgnition, err := json.Marshal(ignitionConfig)
+// ValidateKernelTypeTransition verifies that the machine config operator can switch nodes from the existing
+// kernel type to the desired one, the empty kernel type is the default one
+func ValidateKernelTypeTransition(existing string, desired string) error {
+	if existing == "" {
+		existing = MCKernelDefault
+	}
+
+	for _, kernelType := range []string{existing, desired} {
+		if kernelType != MCKernelDefault && kernelType != MCKernelRT {
+			return fmt.Errorf("the kernel type transition from %q to %q is not supported, the kernel type should be %q or %q", existing, desired, MCKernelDefault, MCKernelRT)
+		}
+	}
+	return nil
+}
+
+// IgnitionBytes returns the serialized ignition config of the machine config, storage files and systemd units
+// are sorted by the path and the name, so the same profile always gives the same content, regardless of the order
+// of its fields, and the content can be compared independently of the machine config metadata
+func IgnitionBytes(assetsDir string, profile *performancev1.PerformanceProfile) ([]byte, error) {
+	ignitionConfig, err := getIgnitionConfig(assetsDir, profile)
 	if err != nil {
 		return nil, err
 	}
-	mc.Spec.Config = runtime.RawExtension{Raw: rawIgnition}
 
-	enableRTKernel := profile.Spec.RealTimeKernel != nil &&
-		profile.Spec.RealTimeKernel.Enabled != nil &&
-		*profile.Spec.RealTimeKernel.Enabled
+	sort.SliceStable(ignitionConfig.Storage.Files, func(i, j int) bool {
+		return ignitionConfig.Storage.Files[i].Path < ignitionConfig.Storage.Files[j].Path
+	})
+	sort.SliceStable(ignitionConfig.Systemd.Units, func(i, j int) bool {
+		return ignitionConfig.Systemd.Units[i].Name < ignitionConfig.Systemd.Units[j].Name
+	})
 
-	if enableRTKernel {
-		mc.Spec.KernelType = MCKernelRT
-	} else {
-		mc.Spec.KernelType = MCKernelDefault
+	return json.Marshal(ignitionConfig)
+}
+
+// ValidateName verifies that the machine config name is a valid DNS subdomain and does not collide
+// with names of machine configs that the machine config operator generates
+func ValidateName(name string) error {
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("the machine config name %q is invalid: %s", name, strings.Join(errs, ", "))
 	}
 
-	return mc, nil
+	for _, prefix := range reservedNamePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return fmt.Errorf("the machine config name %q is invalid: the %q prefix is reserved for machine configs generated by the machine config operator", name, prefix)
+		}
+	}
+
+	if mcoTemplateNameRegex.MatchString(name) {
+		return fmt.Errorf("the machine config name %q is invalid: numeric prefixes are reserved for machine configs generated by the machine config operator", name)
+	}
+	return nil
+}
+
+// ValidateProfileName verifies that the machine config name generated from the profile name is valid
+// and does not collide with names of machine configs that the machine config operator generates
+func ValidateProfileName(profile *performancev1.PerformanceProfile) error {
+	name := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+	if err := ValidateName(name); err != nil {
+		return fmt.Errorf("the performance profile name %q can not be used: %v", profile.Name, err)
+	}
+	return nil
+}
+
+// RenderUnits returns the content of systemd units that the machine config provides, mapped by the unit name
+func RenderUnits(profile *performancev1.PerformanceProfile) (map[string]string, error) {
+	rendered := map[string]string{}
+	for _, script := range scripts {
+		units, err := script.units(profile)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, u := range units {
+			rendered[u.Name] = u.Contents
+		}
+	}
+	return rendered, nil
+}
+
+// NewForArchitectures returns machine configs for mixed architecture clusters mapped by the architecture,
+// each machine config carries architecture specific kernel arguments and is labeled with the machine config
+// label value suffixed by the architecture, so it can be targeted at the architecture specific pool.
+// Other kernel arguments are shared by all architectures and come from the profile tuned.
+func NewForArchitectures(assetsDir string, profile *performancev1.PerformanceProfile, architectures []string) (map[string]*machineconfigv1.MachineConfig, error) {
+	mcs := map[string]*machineconfigv1.MachineConfig{}
+	for _, architecture := range architectures {
+		kernelArgs, ok := components.IOMMUKernelArgs[architecture]
+		if !ok {
+			return nil, fmt.Errorf("unsupported architecture %q", architecture)
+		}
+
+		mc, err := New(assetsDir, profile)
+		if err != nil {
+			return nil, err
+		}
+
+		mc.Name = GetArchitectureName(mc.Name, architecture)
+		if err := ValidateName(mc.Name); err != nil {
+			return nil, err
+		}
+
+		mc.Labels = components.GetComponentLabels(profile2.GetArchitectureMachineConfigLabel(profile, architecture))
+		mc.Spec.KernelArguments = append([]string{}, kernelArgs...)
+		mcs[architecture] = mc
+	}
+	return mcs, nil
+}
+
+// GetArchitectureName returns the name of the architecture specific machine config
+func GetArchitectureName(name string, architecture string) string {
+	return fmt.Sprintf("%s-%s", name, architecture)
+}
+
+// ValidateArchitecturePools verifies that every architecture specific machine config is selected by one of pools
+func ValidateArchitecturePools(mcs map[string]*machineconfigv1.MachineConfig, mcps []machineconfigv1.MachineConfigPool) error {
+	for architecture, mc := range mcs {
+		found := false
+		for _, mcp := range mcps {
+			selector, err := metav1.LabelSelectorAsSelector(mcp.Spec.MachineConfigSelector)
+			if err != nil {
+				return err
+			}
+
+			if selector.Matches(labels.Set(mc.Labels)) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("failed to find the machine config pool for the architecture %q, that selects machine configs with labels %v", architecture, mc.Labels)
+		}
+	}
+	return nil
+}
+
+// ValidateAssets verifies that every script of the machine config exists under the assets directory and can be executed,
+// and that the config files exist, so packaging errors are reported on the operator start instead of the profile reconcile
+func ValidateAssets(assetsDir string) error {
+	var errs []error
+	for _, script := range scripts {
+		path := getScriptAssetPath(assetsDir, script.name)
+		info, err := os.Stat(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("the script %q is missing: %v", path, err))
+			continue
+		}
+
+		if !info.Mode().IsRegular() {
+			errs = append(errs, fmt.Errorf("the script %q is not a regular file", path))
+			continue
+		}
+
+		if info.Mode().Perm()&executableFileMode == 0 {
+			errs = append(errs, fmt.Errorf("the script %q mode %#o is not executable", path, info.Mode().Perm()))
+		}
+	}
+
+	path := getConfigAssetPath(assetsDir, fmt.Sprintf("%s.conf", crioRuntimesConfig))
+	if _, err := os.Stat(path); err != nil {
+		errs = append(errs, fmt.Errorf("the config %q is missing: %v", path, err))
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// ValidateAssetsChecksums verifies that scripts under the assets directory match checksums built into the operator,
+// so the operator image never copies modified scripts to nodes
+func ValidateAssetsChecksums(assetsDir string) error {
+	var errs []error
+	for _, script := range scripts {
+		path := getScriptAssetPath(assetsDir, script.name)
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read the script %q: %v", path, err))
+			continue
+		}
+
+		sum := sha256.Sum256(content)
+		if checksum := hex.EncodeToString(sum[:]); checksum != script.checksum {
+			errs = append(errs, fmt.Errorf("the script %q checksum %s does not match the expected checksum %s, the script was modified", path, checksum, script.checksum))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func getScriptAssetPath(assetsDir string, scriptName string) string {
+	return filepath.Join(assetsDir, "scripts", fmt.Sprintf("%s.sh", scriptName))
+}
+
+func getConfigAssetPath(assetsDir string, configName string) string {
+	return filepath.Join(assetsDir, "configs", configName)
 }
 
 func getIgnitionConfig(assetsDir string, profile *performancev1.PerformanceProfile) (*igntypes.Config, error) {
@@ -114,63 +390,209 @@ func getIgnitionConfig(assetsDir string, profile *performancev1.PerformanceProfi
 		},
 	}
 
-	// add script files under the node /usr/local/bin directory
+	// add script files under the node /usr/local/bin directory and systemd units that run them
 	mode := 0700
-	for _, script := range []string{hugepagesAllocation} {
-		src := filepath.Join(assetsDir, "scripts", fmt.Sprintf("%s.sh", script))
-		if err := addFile(ignitionConfig, src, getBashScriptPath(script), &mode); err != nil {
+	for _, script := range scripts {
+		if err := addFile(ignitionConfig, getScriptAssetPath(assetsDir, script.name), getBashScriptPath(script.name), &mode); err != nil {
+			return nil, err
+		}
+
+		units, err := script.units(profile)
+		if err != nil {
 			return nil, err
 		}
+		ignitionConfig.Systemd.Units = append(ignitionConfig.Systemd.Units, units...)
+	}
+
+	if err := validateUnitsOrdering(ignitionConfig.Systemd.Units); err != nil {
+		return nil, err
 	}
 
 	// add crio config snippet under the node /etc/crio/crio.conf.d/ directory
 	crioConfdRuntimesMode := 0644
 	config := fmt.Sprintf("%s.conf", crioRuntimesConfig)
-	if err := addFile(
-		ignitionConfig,
-		filepath.Join(assetsDir, "configs", config),
-		filepath.Join(crioConfd, config),
-		&crioConfdRuntimesMode,
-	); err != nil {
+	crioRuntimes, err := readFileWithRetry(getConfigAssetPath(assetsDir, config))
+	if err != nil {
 		return nil, err
 	}
+	crioRuntimes, err = getCRIORuntimesConfig(crioRuntimes, profile)
+	if err != nil {
+		return nil, err
+	}
+	addContent(ignitionConfig, crioRuntimes, filepath.Join(crioConfd, config), &crioConfdRuntimesMode)
 
-	if profile.Spec.HugePages != nil {
-		for _, page := range profile.Spec.HugePages.Pages {
-			// we already allocated non NUMA specific hugepages via kernel arguments
-			if page.Node == nil {
+	// the tuned disables the RT throttling only once it starts, the drop-in disables it from the early boot,
+	// so real time tasks pinned to CPUs isolated via the isolcpus kernel argument are never throttled
+	rtThrottling, err := getRTThrottlingSysctl(profile)
+	if err != nil {
+		return nil, err
+	}
+	if rtThrottling != "" {
+		sysctlMode := 0644
+		dst := filepath.Join(sysctlD, fmt.Sprintf("%s.conf", rtThrottlingSysctl))
+		addContent(ignitionConfig, []byte(rtThrottling), dst, &sysctlMode)
+	}
+
+	if errs := validateFileModes(ignitionConfig.Storage.Files); len(errs) > 0 {
+		return nil, errs.ToAggregate()
+	}
+
+	return ignitionConfig, nil
+}
+
+// validateFileModes verifies that ignition files modes are valid permission bits and warns
+// when a script file can not be executed
+func validateFileModes(files []igntypes.File) field.ErrorList {
+	var errs field.ErrorList
+	filesPath := field.NewPath("storage", "files")
+	for i, file := range files {
+		if file.Mode == nil {
+			continue
+		}
+
+		mode := *file.Mode
+		if mode < 0 || mode > maxFileMode {
+			errs = append(errs, field.Invalid(filesPath.Index(i).Child("mode"), fmt.Sprintf("%#o", mode), "file mode should be in the range 0000-0777"))
+			continue
+		}
+
+		if strings.HasPrefix(file.Path, bashScriptsDir) && mode&executableFileMode == 0 {
+			klog.Warningf("the script file %q mode %#o is not executable", file.Path, mode)
+		}
+	}
+	return errs
+}
+
+// validateUnitsOrdering verifies that every systemd unit of the machine config is ordered before the kubelet,
+// so the node tuning completes before the kubelet starts to run workloads
+func validateUnitsOrdering(units []igntypes.Unit) error {
+	// before maps the unit name to units that should start after it
+	before := map[string][]string{}
+	for _, u := range units {
+		options, err := unit.Deserialize(strings.NewReader(u.Contents))
+		if err != nil {
+			return fmt.Errorf("failed to parse the systemd unit %q: %v", u.Name, err)
+		}
+
+		for _, option := range options {
+			if option.Section != systemdSectionUnit {
 				continue
 			}
 
-			hugepagesSize, err := GetHugepagesSizeKilobytes(page.Size)
-			if err != nil {
-				return nil, err
+			for _, other := range strings.Fields(option.Value) {
+				switch option.Name {
+				case systemdBefore:
+					before[u.Name] = append(before[u.Name], other)
+				case systemdAfter:
+					before[other] = append(before[other], u.Name)
+				}
 			}
+		}
+	}
 
-			hugepagesService, err := getSystemdContent(getHugepagesAllocationUnitOptions(
-				hugepagesSize,
-				page.Count,
-				*page.Node,
-			))
-			if err != nil {
-				return nil, err
-			}
+	for _, u := range units {
+		if !isOrderedBefore(before, u.Name, systemdServiceKubelet, map[string]bool{}) {
+			return fmt.Errorf("the systemd unit %q should be ordered before %q", u.Name, systemdServiceKubelet)
+		}
 
-			ignitionConfig.Systemd.Units = append(ignitionConfig.Systemd.Units, igntypes.Unit{
-				Contents: hugepagesService,
-				Enabled:  pointer.BoolPtr(true),
-				Name:     getSystemdService(fmt.Sprintf("%s-%skB-NUMA%d", hugepagesAllocation, hugepagesSize, *page.Node)),
-			})
+		if isOrderedBefore(before, systemdServiceKubelet, u.Name, map[string]bool{}) {
+			return fmt.Errorf("the systemd unit %q is ordered after %q", u.Name, systemdServiceKubelet)
 		}
 	}
+	return nil
+}
 
-	return ignitionConfig, nil
+// isOrderedBefore returns true when the ordering graph has a path from the first unit to the second one
+func isOrderedBefore(before map[string][]string, first string, second string, visited map[string]bool) bool {
+	if visited[first] {
+		return false
+	}
+	visited[first] = true
+
+	for _, next := range before[first] {
+		if next == second || isOrderedBefore(before, next, second, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+func getHugepagesAllocationUnits(profile *performancev1.PerformanceProfile) ([]igntypes.Unit, error) {
+	var units []igntypes.Unit
+	if profile.Spec.HugePages == nil {
+		return units, nil
+	}
+
+	for _, page := range profile.Spec.HugePages.Pages {
+		// we already allocated non NUMA specific hugepages via kernel arguments
+		if page.Node == nil {
+			continue
+		}
+
+		hugepagesSize, err := GetHugepagesSizeKilobytes(page.Size)
+		if err != nil {
+			return nil, err
+		}
+
+		hugepagesService, err := getSystemdContent(getHugepagesAllocationUnitOptions(
+			profile2.GetUnitDescriptionPrefix(profile),
+			getTuningServiceOptions(profile),
+			hugepagesSize,
+			page.Count,
+			*page.Node,
+		))
+		if err != nil {
+			return nil, err
+		}
+
+		units = append(units, igntypes.Unit{
+			Contents: hugepagesService,
+			Enabled:  pointer.BoolPtr(true),
+			Name:     getSystemdService(fmt.Sprintf("%s-%skB-NUMA%d", hugepagesAllocation, hugepagesSize, *page.Node)),
+		})
+	}
+
+	return units, nil
+}
+
+// getIRQThreadsAffinityUnits returns the unit that moves IRQ threads to reserved CPUs once the node booted,
+// the real time kernel runs interrupt handlers in threads that can be scheduled on isolated CPUs otherwise
+func getIRQThreadsAffinityUnits(profile *performancev1.PerformanceProfile) ([]igntypes.Unit, error) {
+	if !profile2.IsRealTimeKernelEnabled(profile) || profile.Spec.CPU == nil || profile.Spec.CPU.Reserved == nil {
+		return nil, nil
+	}
+
+	// the compact form keeps the environment assignment short on nodes with many CPUs
+	reserved, err := components.NormalizeCPUList(string(*profile.Spec.CPU.Reserved))
+	if err != nil {
+		return nil, err
+	}
+
+	irqThreadsService, err := getSystemdContent(getIRQThreadsAffinityUnitOptions(profile2.GetUnitDescriptionPrefix(profile), getTuningServiceOptions(profile), reserved))
+	if err != nil {
+		return nil, err
+	}
+
+	return []igntypes.Unit{
+		{
+			Contents: irqThreadsService,
+			Enabled:  pointer.BoolPtr(true),
+			Name:     getSystemdService(irqThreadsAffinity),
+		},
+	}, nil
 }
 
 func getBashScriptPath(scriptName string) string {
 	return fmt.Sprintf("%s/%s.sh", bashScriptsDir, scriptName)
 }
 
+func getUnitDescription(prefix string, description string) string {
+	if prefix == "" {
+		return description
+	}
+	return fmt.Sprintf("%s %s", prefix, description)
+}
+
 func getSystemdEnvironment(key string, value string) string {
 	return fmt.Sprintf("%s=%s", key, value)
 }
@@ -180,6 +602,10 @@ func getSystemdService(serviceName string) string {
 }
 
 func getSystemdContent(options []*unit.UnitOption) (string, error) {
+	if err := validateSystemdEnvironment(options); err != nil {
+		return "", err
+	}
+
 	outReader := unit.Serialize(options)
 	outBytes, err := ioutil.ReadAll(outReader)
 	if err != nil {
@@ -188,6 +614,22 @@ func getSystemdContent(options []*unit.UnitOption) (string, error) {
 	return string(outBytes), nil
 }
 
+// validateSystemdEnvironment verifies that environment assignments of the unit fit into the unit file line,
+// CPU lists should be passed in the compact form with ranges, see components.NormalizeCPUList
+func validateSystemdEnvironment(options []*unit.UnitOption) error {
+	for _, option := range options {
+		if option.Name != systemdEnvironment {
+			continue
+		}
+
+		if length := len(option.Name) + len("=") + len(option.Value); length > maxSystemdEnvironmentLength {
+			key := strings.SplitN(option.Value, "=", 2)[0]
+			return fmt.Errorf("the systemd unit environment variable %q is %d bytes long, it should not exceed %d bytes", key, length, maxSystemdEnvironmentLength)
+		}
+	}
+	return nil
+}
+
 // GetHugepagesSizeKilobytes retruns hugepages size in kilobytes
 func GetHugepagesSizeKilobytes(hugepagesSize performancev1.HugePageSize) (string, error) {
 	switch hugepagesSize {
@@ -195,16 +637,30 @@ func GetHugepagesSizeKilobytes(hugepagesSize performancev1.HugePageSize) (string
 		return "1048576", nil
 	case "2M":
 		return "2048", nil
+	case "16G":
+		return "16777216", nil
+	case "16M":
+		return "16384", nil
 	default:
 		return "", fmt.Errorf("can not convert size %q to kilobytes", hugepagesSize)
 	}
 }
 
-func getHugepagesAllocationUnitOptions(hugepagesSize string, hugepagesCount int32, numaNode int32) []*unit.UnitOption {
+// getTuningServiceOptions returns the service type options of the tuning units
+func getTuningServiceOptions(profile *performancev1.PerformanceProfile) []*unit.UnitOption {
 	return []*unit.UnitOption{
+		// Type
+		unit.NewUnitOption(systemdSectionService, systemdType, string(profile2.GetTuningUnitType(profile))),
+		// RemainAfterExit
+		unit.NewUnitOption(systemdSectionService, systemdRemainAfterExit, strconv.FormatBool(profile2.IsTuningUnitRemainAfterExit(profile))),
+	}
+}
+
+func getHugepagesAllocationUnitOptions(descriptionPrefix string, serviceOptions []*unit.UnitOption, hugepagesSize string, hugepagesCount int32, numaNode int32) []*unit.UnitOption {
+	options := []*unit.UnitOption{
 		// [Unit]
 		// Description
-		unit.NewUnitOption(systemdSectionUnit, systemdDescription, fmt.Sprintf("Hugepages-%skB allocation on the node %d", hugepagesSize, numaNode)),
+		unit.NewUnitOption(systemdSectionUnit, systemdDescription, getUnitDescription(descriptionPrefix, fmt.Sprintf("Hugepages-%skB allocation on the node %d", hugepagesSize, numaNode))),
 		// Before
 		unit.NewUnitOption(systemdSectionUnit, systemdBefore, systemdServiceKubelet),
 		// [Service]
@@ -212,23 +668,82 @@ func getHugepagesAllocationUnitOptions(hugepagesSize string, hugepagesCount int3
 		unit.NewUnitOption(systemdSectionService, systemdEnvironment, getSystemdEnvironment(environmentHugepagesCount, fmt.Sprint(hugepagesCount))),
 		unit.NewUnitOption(systemdSectionService, systemdEnvironment, getSystemdEnvironment(environmentHugepagesSize, hugepagesSize)),
 		unit.NewUnitOption(systemdSectionService, systemdEnvironment, getSystemdEnvironment(environmentNUMANode, fmt.Sprint(numaNode))),
-		// Type
-		unit.NewUnitOption(systemdSectionService, systemdType, systemdServiceTypeOneshot),
-		// RemainAfterExit
-		unit.NewUnitOption(systemdSectionService, systemdRemainAfterExit, systemdTrue),
+	}
+	options = append(options, serviceOptions...)
+	return append(options,
 		// ExecStart
 		unit.NewUnitOption(systemdSectionService, systemdExecStart, getBashScriptPath(hugepagesAllocation)),
 		// [Install]
 		// WantedBy
 		unit.NewUnitOption(systemdSectionInstall, systemdWantedBy, systemdTargetMultiUser),
+	)
+}
+
+func getIRQThreadsAffinityUnitOptions(descriptionPrefix string, serviceOptions []*unit.UnitOption, reservedCPUs string) []*unit.UnitOption {
+	options := []*unit.UnitOption{
+		// [Unit]
+		// Description
+		unit.NewUnitOption(systemdSectionUnit, systemdDescription, getUnitDescription(descriptionPrefix, "Move IRQ threads to the reserved CPUs")),
+		// Before
+		unit.NewUnitOption(systemdSectionUnit, systemdBefore, systemdServiceKubelet),
+		// [Service]
+		// Environment
+		unit.NewUnitOption(systemdSectionService, systemdEnvironment, getSystemdEnvironment(environmentReservedCPUs, reservedCPUs)),
+	}
+	options = append(options, serviceOptions...)
+	return append(options,
+		// ExecStart
+		unit.NewUnitOption(systemdSectionService, systemdExecStart, getBashScriptPath(irqThreadsAffinity)),
+		// [Install]
+		// WantedBy
+		unit.NewUnitOption(systemdSectionInstall, systemdWantedBy, systemdTargetMultiUser),
+	)
+}
+
+// readFile reads the asset file, it can be replaced under tests to simulate filesystem failures
+var readFile = ioutil.ReadFile
+
+// readFileBackoff defines retries of the asset file read, the read can transiently fail on slow or overlay filesystems
+var readFileBackoff = wait.Backoff{
+	Steps:    5,
+	Duration: 100 * time.Millisecond,
+	Factor:   2.0,
+}
+
+func readFileWithRetry(src string) ([]byte, error) {
+	var content []byte
+	var readErr error
+	err := wait.ExponentialBackoff(readFileBackoff, func() (bool, error) {
+		content, readErr = readFile(src)
+		if readErr == nil {
+			return true, nil
+		}
+
+		// the missing file will not appear on the next attempt
+		if os.IsNotExist(readErr) {
+			return false, readErr
+		}
+
+		klog.Warningf("failed to read the file %q, retrying: %v", src, readErr)
+		return false, nil
+	})
+
+	if err == wait.ErrWaitTimeout {
+		return nil, fmt.Errorf("failed to read the file %q after %d attempts: %v", src, readFileBackoff.Steps, readErr)
 	}
+	return content, err
 }
 
 func addFile(ignitionConfig *igntypes.Config, src string, dst string, mode *int) error {
-	content, err := ioutil.ReadFile(src)
+	content, err := readFileWithRetry(src)
 	if err != nil {
 		return err
 	}
+	addContent(ignitionConfig, content, dst, mode)
+	return nil
+}
+
+func addContent(ignitionConfig *igntypes.Config, content []byte, dst string, mode *int) {
 	contentBase64 := base64.StdEncoding.EncodeToString(content)
 	ignitionConfig.Storage.Files = append(ignitionConfig.Storage.Files, igntypes.File{
 		Node: igntypes.Node{
@@ -242,5 +757,44 @@ func addFile(ignitionConfig *igntypes.Config, src string, dst string, mode *int)
 			Mode: mode,
 		},
 	})
-	return nil
+}
+
+// getCRIORuntimesConfig returns the CRI-O runtimes config snippet, the high-performance runtime handler allows
+// the CPU quota annotation only when the profile enables it, otherwise the snippet is copied as is
+func getCRIORuntimesConfig(content []byte, profile *performancev1.PerformanceProfile) ([]byte, error) {
+	if !profile2.IsCPUQuotaAnnotationAllowed(profile) {
+		return content, nil
+	}
+
+	header := crioHighPerformanceRuntimeTable + "\n"
+	index := strings.Index(string(content), header)
+	if index < 0 {
+		return nil, fmt.Errorf("the CRI-O runtimes config does not have the %s table", crioHighPerformanceRuntimeTable)
+	}
+
+	// keys of the TOML table can go in any order, so the allowed annotations are added right after the table header
+	end := index + len(header)
+	allowedAnnotations := fmt.Sprintf("allowed_annotations = [%q]\n", crioCPUQuotaAnnotation)
+	return []byte(string(content[:end]) + allowedAnnotations + string(content[end:])), nil
+}
+
+// getRTThrottlingSysctl returns the sysctl drop-in that disables the RT throttling, the drop-in is generated
+// only for the real time kernel together with isolated CPUs, the same condition the isolcpus kernel argument has
+func getRTThrottlingSysctl(profile *performancev1.PerformanceProfile) (string, error) {
+	if !profile2.IsRealTimeKernelEnabled(profile) {
+		return "", nil
+	}
+
+	isolated, err := profile2.GetIsolatedCPUs(profile)
+	if err != nil {
+		return "", err
+	}
+	if isolated.IsEmpty() {
+		return "", nil
+	}
+
+	return fmt.Sprintf(`# Generated by the performance profile %s.
+# The real time workloads run on isolated CPUs %s, their real time tasks should not be throttled.
+kernel.sched_rt_runtime_us = -1
+`, profile.Name, isolated.String()), nil
 }
@@ -1,13 +1,37 @@
 package machineconfig
 
 import (
+	"os"
 	"testing"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/machineconfig/testhelpers"
 )
 
+// testAssetsDir contains stub assets, machine config tests do not verify the content of assets
+var testAssetsDir string
+
+// buildAssetsDir contains assets the operator image ships, tests that verify the content of assets use them
+const buildAssetsDir = "../../../../../build/assets"
+
 func TestMachineConfig(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Machine Config Suite")
 }
+
+var _ = BeforeSuite(func() {
+	var scriptNames []string
+	for _, script := range scripts {
+		scriptNames = append(scriptNames, script.name)
+	}
+
+	var err error
+	testAssetsDir, err = testhelpers.NewAssetsDir(scriptNames, []string{crioRuntimesConfig})
+	Expect(err).ToNot(HaveOccurred())
+})
+
+var _ = AfterSuite(func() {
+	Expect(os.RemoveAll(testAssetsDir)).To(Succeed())
+})
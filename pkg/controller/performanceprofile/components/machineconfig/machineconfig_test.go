@@ -2,18 +2,29 @@ package machineconfig
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/utils/pointer"
 
+	"github.com/coreos/go-systemd/unit"
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
 	"github.com/ghodss/yaml"
 	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
 	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+	machineconfigv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 )
 
-const testAssetsDir = "../../../../../build/assets"
 const hugepagesAllocationService = `
       - contents: |
           [Unit]
@@ -42,9 +53,698 @@ var _ = Describe("Machine Config", func() {
 			profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(0)
 			_, err := New(testAssetsDir, profile)
 			Expect(err).ToNot(HaveOccurred())
-			_, err = New("../../../../../build/invalid/assets", profile)
+			_, err = New(filepath.Join(testAssetsDir, "invalid"), profile)
 			Expect(err).Should(HaveOccurred(), "should fail with missing CPU")
 		})
+
+		It("should label the machine config with the standard labels and the machine config label", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			mc, err := New(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mc.Labels).To(HaveKeyWithValue("app.kubernetes.io/managed-by", "performance-operator"))
+			Expect(mc.Labels).To(HaveKeyWithValue("app.kubernetes.io/part-of", "performance-addon-operator"))
+			Expect(mc.Labels).To(HaveKeyWithValue(testutils.MachineConfigLabelKey, testutils.MachineConfigLabelValue))
+			// the profile machine config label should stay untouched
+			Expect(profile.Spec.MachineConfigLabel).To(Equal(map[string]string{testutils.MachineConfigLabelKey: testutils.MachineConfigLabelValue}))
+		})
+	})
+
+	Context("machine config name", func() {
+		table.DescribeTable("should validate the machine config name",
+			func(name string, expectedError string) {
+				err := ValidateName(name)
+				if expectedError == "" {
+					Expect(err).ToNot(HaveOccurred())
+					return
+				}
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(expectedError))
+			},
+			table.Entry("with the generated name", "performance-manual", ""),
+			table.Entry("with the architecture suffix", "performance-manual-arm64", ""),
+			table.Entry("with the dotted name", "performance-example.com", ""),
+			table.Entry("with upper case letters", "performance-Manual", "a DNS-1123 subdomain must consist of lower case alphanumeric characters"),
+			table.Entry("with the too long name", "performance-"+strings.Repeat("a", 250), "must be no more than 253 characters"),
+			table.Entry("with the rendered prefix", "rendered-worker-1234", `the "rendered-" prefix is reserved`),
+			table.Entry("with the zero prefix", "00-worker", "numeric prefixes are reserved"),
+			table.Entry("with the generated prefix", "99-worker-generated-kubelet", "numeric prefixes are reserved"),
+			table.Entry("with the numeric prefix", "01-worker-kubelet", "numeric prefixes are reserved"),
+		)
+
+		table.DescribeTable("should validate the profile name",
+			func(name string, expectedError string) {
+				err := ValidateProfileName(testutils.NewPerformanceProfile(name))
+				if expectedError == "" {
+					Expect(err).ToNot(HaveOccurred())
+					return
+				}
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(expectedError))
+			},
+			table.Entry("with the regular name", "manual", ""),
+			// the component name prefix keeps names that look reserved away from machine config operator names
+			table.Entry("with the rendered prefix", "rendered-worker", ""),
+			table.Entry("with the numeric prefix", "99-worker", ""),
+			table.Entry("with the too long name", strings.Repeat("a", 242), `the performance profile name "`+strings.Repeat("a", 242)+`" can not be used`),
+		)
+
+		It("should reject the profile with the too long name", func() {
+			profile := testutils.NewPerformanceProfile(strings.Repeat("a", 250))
+			_, err := New(testAssetsDir, profile)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("must be no more than 253 characters"))
+		})
+	})
+
+	Context("systemd unit environment", func() {
+		var wideCPUList []string
+
+		BeforeEach(func() {
+			wideCPUList = nil
+			for cpu := 0; cpu < 2048; cpu++ {
+				wideCPUList = append(wideCPUList, fmt.Sprint(cpu))
+			}
+		})
+
+		It("should reject the environment variable that does not fit into the unit file line", func() {
+			options := []*unit.UnitOption{
+				unit.NewUnitOption(systemdSectionService, systemdEnvironment, getSystemdEnvironment("NON_ISOLATED_CPUS", strings.Join(wideCPUList, ","))),
+			}
+			_, err := getSystemdContent(options)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`the systemd unit environment variable "NON_ISOLATED_CPUS" is`))
+		})
+
+		It("should accept the wide CPU list in the compact form", func() {
+			cpus, err := components.NormalizeCPUList(strings.Join(wideCPUList, ","))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cpus).To(Equal("0-2047"))
+
+			options := []*unit.UnitOption{
+				unit.NewUnitOption(systemdSectionService, systemdEnvironment, getSystemdEnvironment("NON_ISOLATED_CPUS", cpus)),
+			}
+			content, err := getSystemdContent(options)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(content).To(ContainSubstring("Environment=NON_ISOLATED_CPUS=0-2047"))
+		})
+	})
+
+	Context("machine config kernel type", func() {
+		table.DescribeTable("should validate the kernel type transition",
+			func(existing string, desired string, expectedError string) {
+				err := ValidateKernelTypeTransition(existing, desired)
+				if expectedError == "" {
+					Expect(err).ToNot(HaveOccurred())
+					return
+				}
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(expectedError))
+			},
+			table.Entry("from the default to the real time kernel", MCKernelDefault, MCKernelRT, ""),
+			table.Entry("from the real time to the default kernel", MCKernelRT, MCKernelDefault, ""),
+			table.Entry("from the unspecified to the real time kernel", "", MCKernelRT, ""),
+			table.Entry("to the unknown kernel", MCKernelRT, "64k-pages", `the kernel type transition from "realtime" to "64k-pages" is not supported`),
+			table.Entry("from the unknown kernel", "64k-pages", MCKernelDefault, `the kernel type transition from "64k-pages" to "default" is not supported`),
+		)
+
+		It("should use the real time kernel type only when the real time kernel is enabled", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			Expect(GetKernelType(profile)).To(Equal(MCKernelRT))
+
+			profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+			Expect(GetKernelType(profile)).To(Equal(MCKernelDefault))
+		})
+	})
+
+	Context("machine config ignition bytes", func() {
+		It("should return the same content regardless of the huge pages order", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.HugePages.Pages = []performancev1.HugePage{
+				{Size: "1G", Count: 4, Node: pointer.Int32Ptr(0)},
+				{Size: "2M", Count: 128, Node: pointer.Int32Ptr(1)},
+			}
+			ignitionBytes, err := IgnitionBytes(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+
+			profile.Spec.HugePages.Pages[0], profile.Spec.HugePages.Pages[1] = profile.Spec.HugePages.Pages[1], profile.Spec.HugePages.Pages[0]
+			reorderedIgnitionBytes, err := IgnitionBytes(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reorderedIgnitionBytes).To(Equal(ignitionBytes))
+		})
+
+		It("should match the machine config content", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			ignitionBytes, err := IgnitionBytes(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+
+			mc, err := New(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mc.Spec.Config.Raw).To(Equal(ignitionBytes))
+		})
+	})
+
+	Context("machine config scripts", func() {
+		It("should provide storage file and systemd unit for every script", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(0)
+
+			ignitionConfig, err := getIgnitionConfig(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, script := range scripts {
+				scriptPath := getBashScriptPath(script.name)
+
+				var files []string
+				for _, f := range ignitionConfig.Storage.Files {
+					files = append(files, f.Path)
+				}
+				Expect(files).To(ContainElement(scriptPath), "missing storage file for the script %q", script.name)
+
+				var units []string
+				for _, u := range ignitionConfig.Systemd.Units {
+					if strings.Contains(u.Contents, "ExecStart="+scriptPath) {
+						units = append(units, u.Name)
+					}
+				}
+				Expect(units).ToNot(BeEmpty(), "missing systemd unit for the script %q", script.name)
+			}
+		})
+	})
+
+	Context("machine config assets reading", func() {
+		var originalReadFile func(string) ([]byte, error)
+		var originalBackoff wait.Backoff
+
+		BeforeEach(func() {
+			originalReadFile = readFile
+			originalBackoff = readFileBackoff
+			readFileBackoff.Duration = time.Millisecond
+		})
+
+		AfterEach(func() {
+			readFile = originalReadFile
+			readFileBackoff = originalBackoff
+		})
+
+		It("should retry the transiently failed read", func() {
+			attempts := 0
+			readFile = func(filename string) ([]byte, error) {
+				attempts++
+				if attempts < 3 {
+					return nil, fmt.Errorf("transient failure")
+				}
+				return originalReadFile(filename)
+			}
+
+			_, err := New(testAssetsDir, testutils.NewPerformanceProfile("test"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(attempts).To(BeNumerically(">=", 3))
+		})
+
+		It("should fail after exhausting retries", func() {
+			attempts := 0
+			readFile = func(filename string) ([]byte, error) {
+				attempts++
+				return nil, fmt.Errorf("persistent failure")
+			}
+
+			_, err := New(testAssetsDir, testutils.NewPerformanceProfile("test"))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("after %d attempts: persistent failure", readFileBackoff.Steps)))
+			Expect(attempts).To(Equal(readFileBackoff.Steps))
+		})
+	})
+
+	Context("machine config CRI-O runtimes config", func() {
+		var crioRuntimes []byte
+
+		BeforeEach(func() {
+			var err error
+			crioRuntimes, err = ioutil.ReadFile(getConfigAssetPath(buildAssetsDir, crioRuntimesConfig+".conf"))
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should allow the CPU quota annotation matching the golden file", func() {
+			golden, err := ioutil.ReadFile(filepath.Join("testdata", "99-runtimes-cpu-quota.conf"))
+			Expect(err).ToNot(HaveOccurred())
+
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.ContainerRuntime = &performancev1.ContainerRuntime{AllowCPUQuotaAnnotation: pointer.BoolPtr(true)}
+
+			config, err := getCRIORuntimesConfig(crioRuntimes, profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(config)).To(Equal(string(golden)))
+		})
+
+		It("should copy the config as is without the profile flag", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.ContainerRuntime = &performancev1.ContainerRuntime{AllowCPUQuotaAnnotation: pointer.BoolPtr(false)}
+
+			config, err := getCRIORuntimesConfig(crioRuntimes, profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config).To(Equal(crioRuntimes))
+		})
+
+		It("should fail when the config does not have the high-performance runtime handler", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.ContainerRuntime = &performancev1.ContainerRuntime{AllowCPUQuotaAnnotation: pointer.BoolPtr(true)}
+
+			_, err := getCRIORuntimesConfig([]byte("[crio.runtime.runtimes.runc]\n"), profile)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("does not have the [crio.runtime.runtimes.high-performance] table"))
+		})
+	})
+
+	Context("machine config RT throttling sysctl", func() {
+		getSysctl := func(profile *performancev1.PerformanceProfile) (string, bool) {
+			ignitionConfig, err := getIgnitionConfig(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, file := range ignitionConfig.Storage.Files {
+				if file.Path == "/etc/sysctl.d/99-performance-rt-throttling.conf" {
+					Expect(*file.Mode).To(Equal(0644))
+					return decodeFileContents(file.Contents.Source), true
+				}
+			}
+			return "", false
+		}
+
+		It("should generate the sysctl drop-in matching the golden file", func() {
+			golden, err := ioutil.ReadFile(filepath.Join("testdata", "99-performance-rt-throttling.conf"))
+			Expect(err).ToNot(HaveOccurred())
+
+			sysctl, ok := getSysctl(testutils.NewPerformanceProfile("test"))
+			Expect(ok).To(BeTrue())
+			Expect(sysctl).To(Equal(string(golden)))
+		})
+
+		It("should follow isolated CPUs of the profile", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.CPU.IsolatedGroups = []performancev1.IsolatedCPUGroup{{Name: "dpdk", CPUs: "8-9"}}
+
+			sysctl, ok := getSysctl(profile)
+			Expect(ok).To(BeTrue())
+			Expect(sysctl).To(ContainSubstring("isolated CPUs 4-9,"))
+		})
+
+		It("should not generate the sysctl drop-in without the real time kernel", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+
+			_, ok := getSysctl(profile)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should not generate the sysctl drop-in without isolated CPUs", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.CPU.Isolated = nil
+
+			_, ok := getSysctl(profile)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("machine config units descriptions", func() {
+		It("should prefix descriptions with the profile annotation value", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(0)
+			profile.Annotations = map[string]string{
+				performancev1.PerformanceProfileUnitDescriptionPrefixAnnotation: "[perf-test]",
+			}
+
+			units, err := RenderUnits(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(units["hugepages-allocation-1048576kB-NUMA0.service"]).To(ContainSubstring("Description=[perf-test] Hugepages-1048576kB allocation on the node 0\n"))
+		})
+
+		It("should keep default descriptions without the profile annotation", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(0)
+
+			units, err := RenderUnits(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(units["hugepages-allocation-1048576kB-NUMA0.service"]).To(ContainSubstring("Description=Hugepages-1048576kB allocation on the node 0\n"))
+		})
+	})
+
+	Context("machine config assets validation", func() {
+		var assetsDir string
+
+		BeforeEach(func() {
+			var err error
+			assetsDir, err = ioutil.TempDir("", "assets")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(os.MkdirAll(filepath.Join(assetsDir, "scripts"), 0755)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(assetsDir, "configs"), 0755)).To(Succeed())
+			for _, script := range scripts {
+				scriptPath := filepath.Join(assetsDir, "scripts", fmt.Sprintf("%s.sh", script.name))
+				Expect(ioutil.WriteFile(scriptPath, []byte("#!/bin/bash\n"), 0755)).To(Succeed())
+			}
+			configPath := filepath.Join(assetsDir, "configs", fmt.Sprintf("%s.conf", crioRuntimesConfig))
+			Expect(ioutil.WriteFile(configPath, []byte{}, 0644)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(assetsDir)).To(Succeed())
+		})
+
+		It("should succeed with valid assets", func() {
+			Expect(ValidateAssets(testAssetsDir)).To(Succeed())
+			Expect(ValidateAssets(assetsDir)).To(Succeed())
+		})
+
+		It("should fail when the script is missing", func() {
+			scriptPath := filepath.Join(assetsDir, "scripts", fmt.Sprintf("%s.sh", hugepagesAllocation))
+			Expect(os.Remove(scriptPath)).To(Succeed())
+
+			err := ValidateAssets(assetsDir)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("the script %q is missing", scriptPath)))
+		})
+
+		It("should fail when the script is not executable", func() {
+			scriptPath := filepath.Join(assetsDir, "scripts", fmt.Sprintf("%s.sh", hugepagesAllocation))
+			Expect(os.Chmod(scriptPath, 0644)).To(Succeed())
+
+			err := ValidateAssets(assetsDir)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("the script %q mode 0644 is not executable", scriptPath)))
+		})
+
+		It("should fail when the config is missing", func() {
+			configPath := filepath.Join(assetsDir, "configs", fmt.Sprintf("%s.conf", crioRuntimesConfig))
+			Expect(os.Remove(configPath)).To(Succeed())
+
+			err := ValidateAssets(assetsDir)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("the config %q is missing", configPath)))
+		})
+	})
+
+	Context("machine config assets checksums validation", func() {
+		// checksums are verified against scripts the operator image ships
+		var assetsDir string
+
+		BeforeEach(func() {
+			var err error
+			assetsDir, err = ioutil.TempDir("", "assets")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(os.MkdirAll(filepath.Join(assetsDir, "scripts"), 0755)).To(Succeed())
+			for _, script := range scripts {
+				content, err := ioutil.ReadFile(getScriptAssetPath(buildAssetsDir, script.name))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ioutil.WriteFile(getScriptAssetPath(assetsDir, script.name), content, 0755)).To(Succeed())
+			}
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(assetsDir)).To(Succeed())
+		})
+
+		It("should succeed with unmodified scripts", func() {
+			Expect(ValidateAssetsChecksums(buildAssetsDir)).To(Succeed())
+			Expect(ValidateAssetsChecksums(assetsDir)).To(Succeed())
+		})
+
+		It("should fail when the script was tampered", func() {
+			scriptPath := getScriptAssetPath(assetsDir, hugepagesAllocation)
+			f, err := os.OpenFile(scriptPath, os.O_APPEND|os.O_WRONLY, 0755)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = f.WriteString("echo tampered\n")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(f.Close()).To(Succeed())
+
+			err = ValidateAssetsChecksums(assetsDir)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("the script %q checksum", scriptPath)))
+			Expect(err.Error()).To(ContainSubstring("the script was modified"))
+			Expect(err.Error()).ToNot(ContainSubstring(irqThreadsAffinity))
+		})
+
+		It("should fail when the script is missing", func() {
+			scriptPath := getScriptAssetPath(assetsDir, irqThreadsAffinity)
+			Expect(os.Remove(scriptPath)).To(Succeed())
+
+			err := ValidateAssetsChecksums(assetsDir)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("failed to read the script %q", scriptPath)))
+		})
+	})
+
+	Context("machine config units rendering", func() {
+		It("should render systemd units with expected directives", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(0)
+			// the real time kernel adds the IRQ threads affinity unit
+			profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+
+			units, err := RenderUnits(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(units).To(HaveLen(1))
+
+			unitName := "hugepages-allocation-1048576kB-NUMA0.service"
+			Expect(units).To(HaveKey(unitName))
+			Expect(units[unitName]).To(ContainSubstring("Before=kubelet.service"))
+			Expect(units[unitName]).To(ContainSubstring("Environment=HUGEPAGES_COUNT=4"))
+			Expect(units[unitName]).To(ContainSubstring("Environment=NUMA_NODE=0"))
+			Expect(units[unitName]).To(ContainSubstring("ExecStart=/usr/local/bin/hugepages-allocation.sh"))
+		})
+
+		It("should not render units when nothing should run on the node", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+
+			units, err := RenderUnits(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(units).To(BeEmpty())
+		})
+	})
+
+	Context("machine config tuning units service type", func() {
+		var profile *performancev1.PerformanceProfile
+
+		BeforeEach(func() {
+			profile = testutils.NewPerformanceProfile("test")
+			profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(0)
+		})
+
+		It("should keep oneshot units that remain after exit by default", func() {
+			units, err := RenderUnits(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(units).To(HaveLen(2))
+			for _, content := range units {
+				Expect(content).To(ContainSubstring("Type=oneshot\nRemainAfterExit=true\n"))
+			}
+		})
+
+		table.DescribeTable("should serialize the profile service type",
+			func(unitType performancev1.TuningUnitType, remainAfterExit *bool, expected string) {
+				profile.Spec.TuningUnits = &performancev1.TuningUnits{
+					Type:            &unitType,
+					RemainAfterExit: remainAfterExit,
+				}
+
+				units, err := RenderUnits(profile)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(units).To(HaveLen(2))
+				for _, content := range units {
+					Expect(content).To(ContainSubstring(expected))
+				}
+			},
+			table.Entry("simple", performancev1.TuningUnitTypeSimple, nil, "Type=simple\nRemainAfterExit=false\n"),
+			table.Entry("oneshot without remain after exit", performancev1.TuningUnitTypeOneshot, pointer.BoolPtr(false), "Type=oneshot\nRemainAfterExit=false\n"),
+		)
+	})
+
+	Context("machine config IRQ threads affinity unit", func() {
+		It("should render the unit matching the golden file", func() {
+			golden, err := ioutil.ReadFile(filepath.Join("testdata", "irq-threads-affinity.service"))
+			Expect(err).ToNot(HaveOccurred())
+
+			profile := testutils.NewPerformanceProfile("test")
+			reserved := performancev1.CPUSet("0,1,2,3")
+			profile.Spec.CPU.Reserved = &reserved
+
+			units, err := RenderUnits(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(units).To(HaveKeyWithValue("irq-threads-affinity.service", string(golden)))
+		})
+
+		It("should not render the unit without the real time kernel", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+
+			units, err := RenderUnits(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(units).ToNot(HaveKey("irq-threads-affinity.service"))
+		})
+
+		It("should copy the script under the node", func() {
+			ignitionConfig, err := getIgnitionConfig(testAssetsDir, testutils.NewPerformanceProfile("test"))
+			Expect(err).ToNot(HaveOccurred())
+
+			var paths []string
+			for _, file := range ignitionConfig.Storage.Files {
+				paths = append(paths, file.Path)
+			}
+			Expect(paths).To(ContainElement("/usr/local/bin/irq-threads-affinity.sh"))
+		})
+	})
+
+	Context("machine config files modes", func() {
+		newFile := func(path string, mode int) igntypes.File {
+			return igntypes.File{
+				Node:          igntypes.Node{Path: path},
+				FileEmbedded1: igntypes.FileEmbedded1{Mode: &mode},
+			}
+		}
+
+		It("should accept modes in the valid range", func() {
+			files := []igntypes.File{
+				newFile(getBashScriptPath(hugepagesAllocation), 0700),
+				newFile("/etc/crio/crio.conf.d/99-runtimes.conf", 0644),
+				newFile("/etc/empty", 0),
+				newFile("/etc/all", 0777),
+				// not executable script mode should only produce a warning
+				newFile(getBashScriptPath("not-executable"), 0600),
+			}
+			Expect(validateFileModes(files)).To(BeEmpty())
+		})
+
+		It("should fail on modes out of the valid range", func() {
+			files := []igntypes.File{
+				newFile("/etc/valid", 0644),
+				newFile("/etc/too-big", 01777),
+				newFile("/etc/negative", -1),
+			}
+			errs := validateFileModes(files)
+			Expect(errs).To(HaveLen(2))
+			Expect(errs[0].Field).To(Equal("storage.files[1].mode"))
+			Expect(errs[1].Field).To(Equal("storage.files[2].mode"))
+		})
+	})
+
+	Context("machine config units ordering", func() {
+		newUnit := func(name string, ordering ...string) igntypes.Unit {
+			return igntypes.Unit{
+				Name:     name,
+				Contents: "[Unit]\n" + strings.Join(ordering, "\n") + "\n",
+			}
+		}
+
+		It("should accept units ordered before the kubelet", func() {
+			units := []igntypes.Unit{
+				newUnit("first.service", "Before=second.service"),
+				newUnit("second.service", "Before=kubelet.service"),
+				newUnit("third.service", "After=first.service", "Before=crio.service kubelet.service"),
+			}
+			Expect(validateUnitsOrdering(units)).To(Succeed())
+		})
+
+		It("should reject units without the ordering before the kubelet", func() {
+			units := []igntypes.Unit{
+				newUnit("first.service", "Before=crio.service"),
+			}
+			err := validateUnitsOrdering(units)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("should be ordered before"))
+		})
+
+		It("should reject the inverted ordering", func() {
+			units := []igntypes.Unit{
+				newUnit("first.service", "Before=kubelet.service"),
+				// the second unit runs after the kubelet, but it is also required by the first unit
+				newUnit("second.service", "After=kubelet.service", "Before=first.service"),
+			}
+			err := validateUnitsOrdering(units)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("is ordered after"))
+		})
+	})
+
+	Context("machine configs for mixed architecture clusters", func() {
+		var profile *performancev1.PerformanceProfile
+
+		BeforeEach(func() {
+			profile = testutils.NewPerformanceProfile("test")
+		})
+
+		newPool := func(labelValue string) machineconfigv1.MachineConfigPool {
+			return machineconfigv1.MachineConfigPool{
+				Spec: machineconfigv1.MachineConfigPoolSpec{
+					MachineConfigSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{testutils.MachineConfigLabelKey: labelValue},
+					},
+				},
+			}
+		}
+
+		It("should generate machine config variant per architecture", func() {
+			mcs, err := NewForArchitectures(testAssetsDir, profile, []string{components.ArchitectureAMD64, components.ArchitectureARM64})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mcs).To(HaveLen(2))
+
+			amd64 := mcs[components.ArchitectureAMD64]
+			Expect(amd64.Name).To(Equal("performance-test-amd64"))
+			Expect(amd64.Labels).To(Equal(components.GetComponentLabels(map[string]string{testutils.MachineConfigLabelKey: "mcValue-amd64"})))
+			Expect(amd64.Spec.KernelArguments).To(Equal([]string{"intel_iommu=on", "iommu=pt"}))
+
+			arm64 := mcs[components.ArchitectureARM64]
+			Expect(arm64.Name).To(Equal("performance-test-arm64"))
+			Expect(arm64.Labels).To(Equal(components.GetComponentLabels(map[string]string{testutils.MachineConfigLabelKey: "mcValue-arm64"})))
+			Expect(arm64.Spec.KernelArguments).To(Equal([]string{"iommu.passthrough=1"}))
+		})
+
+		It("should fail on unsupported architecture", func() {
+			_, err := NewForArchitectures(testAssetsDir, profile, []string{"s390x"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should validate that every architecture has the pool", func() {
+			mcs, err := NewForArchitectures(testAssetsDir, profile, []string{components.ArchitectureAMD64, components.ArchitectureARM64})
+			Expect(err).ToNot(HaveOccurred())
+
+			mcps := []machineconfigv1.MachineConfigPool{newPool("mcValue-amd64"), newPool("mcValue-arm64")}
+			Expect(ValidateArchitecturePools(mcs, mcps)).To(Succeed())
+
+			err = ValidateArchitecturePools(mcs, mcps[:1])
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`architecture "arm64"`))
+		})
+	})
+
+	Context("machine config kernel type", func() {
+		It("should derive the real time kernel from the workload hints", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.RealTimeKernel = nil
+			profile.Spec.WorkloadHints = &performancev1.WorkloadHints{RealTime: pointer.BoolPtr(true)}
+
+			mc, err := New(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mc.Spec.KernelType).To(Equal(MCKernelRT))
+		})
+
+		It("should keep the real time kernel without CPU isolation", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Spec.CPU.Isolated = nil
+
+			mc, err := New(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mc.Spec.KernelType).To(Equal(MCKernelRT))
+		})
+	})
+
+	Context("machine config annotations", func() {
+		It("should contain the generation of the performance profile", func() {
+			profile := testutils.NewPerformanceProfile("test")
+			profile.Generation = 3
+
+			mc, err := New(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(mc.Annotations).To(HaveKeyWithValue(ProfileGenerationAnnotation, "3"))
+		})
 	})
 
 	Context("with hugepages with specified NUMA node", func() {
@@ -0,0 +1,43 @@
+package testhelpers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// NewAssetsDir creates the temporary assets directory with stub scripts and configs, so tests do not depend
+// on the repository layout. Scripts and configs are specified by names without extensions, the caller should
+// remove the directory once it is not needed.
+func NewAssetsDir(scripts []string, configs []string) (string, error) {
+	assetsDir, err := ioutil.TempDir("", "assets")
+	if err != nil {
+		return "", err
+	}
+
+	for _, script := range scripts {
+		content := fmt.Sprintf("#!/usr/bin/env bash\n\n# stub of the %s script\nexit 0\n", script)
+		if err := writeAsset(filepath.Join(assetsDir, "scripts", fmt.Sprintf("%s.sh", script)), content, 0700); err != nil {
+			os.RemoveAll(assetsDir)
+			return "", err
+		}
+	}
+
+	for _, config := range configs {
+		content := fmt.Sprintf("# stub of the %s config\n", config)
+		if err := writeAsset(filepath.Join(assetsDir, "configs", fmt.Sprintf("%s.conf", config)), content, 0644); err != nil {
+			os.RemoveAll(assetsDir)
+			return "", err
+		}
+	}
+
+	return assetsDir, nil
+}
+
+func writeAsset(path string, content string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(content), mode)
+}
@@ -0,0 +1,52 @@
+package machineconfigpool
+
+import (
+	"fmt"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	profile2 "github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/profile"
+	machineconfigv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// roleWorker is the role of the base machine config pool, that the dedicated pool inherits machine configs from
+const roleWorker = "worker"
+
+// New returns new MachineConfigPool object that selects the performance profile nodes and machine configs,
+// it is used only when the cluster does not have a pool for the performance profile
+func New(profile *performancev1.PerformanceProfile) (*machineconfigv1.MachineConfigPool, error) {
+	name := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+
+	// the dedicated pool should inherit worker machine configs in addition to the performance profile one
+	mcLabelKey, mcLabelValue := components.GetFirstKeyAndValue(profile2.GetMachineConfigLabel(profile))
+	if mcLabelKey != components.MachineConfigRoleLabelKey {
+		return nil, fmt.Errorf("can not create the machine config pool, the machine config label key should be equal to %q", components.MachineConfigRoleLabelKey)
+	}
+
+	return &machineconfigv1.MachineConfigPool{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: machineconfigv1.GroupVersion.String(),
+			Kind:       "MachineConfigPool",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: components.GetComponentLabels(profile2.GetMachineConfigPoolSelector(profile)),
+		},
+		Spec: machineconfigv1.MachineConfigPoolSpec{
+			MachineConfigSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      components.MachineConfigRoleLabelKey,
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   []string{roleWorker, mcLabelValue},
+					},
+				},
+			},
+			NodeSelector: &metav1.LabelSelector{
+				MatchLabels: profile.Spec.NodeSelector,
+			},
+		},
+	}, nil
+}
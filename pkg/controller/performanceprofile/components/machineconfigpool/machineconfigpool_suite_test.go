@@ -0,0 +1,13 @@
+package machineconfigpool
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestMachineConfigPool(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Machine Config Pool Suite")
+}
@@ -0,0 +1,41 @@
+package machineconfigpool
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var _ = Describe("Machine Config Pool", func() {
+	var profile *performancev1.PerformanceProfile
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+		profile.Spec.MachineConfigLabel = map[string]string{components.MachineConfigRoleLabelKey: "worker-cnf"}
+	})
+
+	It("should select the profile nodes and machine configs", func() {
+		mcp, err := New(profile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mcp.Name).To(Equal(components.GetComponentName(profile.Name, components.ComponentNamePrefix)))
+		Expect(mcp.Labels).To(Equal(components.GetComponentLabels(profile.Spec.MachineConfigPoolSelector)))
+		Expect(mcp.Spec.NodeSelector.MatchLabels).To(Equal(profile.Spec.NodeSelector))
+
+		selector, err := metav1.LabelSelectorAsSelector(mcp.Spec.MachineConfigSelector)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(selector.Matches(labels.Set(profile.Spec.MachineConfigLabel))).To(BeTrue())
+		Expect(selector.Matches(labels.Set{components.MachineConfigRoleLabelKey: roleWorker})).To(BeTrue())
+	})
+
+	It("should fail when the machine config label is not the role label", func() {
+		profile.Spec.MachineConfigLabel = map[string]string{"mcKey": "mcValue"}
+		_, err := New(profile)
+		Expect(err).To(HaveOccurred())
+	})
+})
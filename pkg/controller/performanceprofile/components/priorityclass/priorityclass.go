@@ -0,0 +1,43 @@
+package priorityclass
+
+import (
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultValue contains the default priority value of the PriorityClass created by the operator
+const DefaultValue = int32(1000000)
+
+// IsEnabled returns whether or not the PriorityClass should be created for the performance profile
+func IsEnabled(profile *performancev1.PerformanceProfile) bool {
+	return profile.Spec.PriorityClass != nil &&
+		profile.Spec.PriorityClass.Enabled != nil &&
+		*profile.Spec.PriorityClass.Enabled
+}
+
+// New returns a new PriorityClass object
+func New(profile *performancev1.PerformanceProfile) *schedulingv1.PriorityClass {
+	name := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+
+	value := DefaultValue
+	if profile.Spec.PriorityClass != nil && profile.Spec.PriorityClass.Value != nil {
+		value = *profile.Spec.PriorityClass.Value
+	}
+
+	return &schedulingv1.PriorityClass{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PriorityClass",
+			APIVersion: schedulingv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: components.GetComponentLabels(nil),
+		},
+		Value:         value,
+		GlobalDefault: false,
+		Description:   "Priority class for performance sensitive workloads running under the performance profile " + profile.Name,
+	}
+}
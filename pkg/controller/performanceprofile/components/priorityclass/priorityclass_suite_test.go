@@ -0,0 +1,13 @@
+package priorityclass
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestPriorityClass(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Priority Class Suite")
+}
@@ -0,0 +1,49 @@
+package priorityclass
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("Priority Class", func() {
+	var profile *performancev1.PerformanceProfile
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+	})
+
+	It("should be disabled when not requested", func() {
+		Expect(IsEnabled(profile)).To(BeFalse())
+
+		profile.Spec.PriorityClass = &performancev1.PriorityClass{Enabled: pointer.BoolPtr(false)}
+		Expect(IsEnabled(profile)).To(BeFalse())
+
+		profile.Spec.PriorityClass.Enabled = pointer.BoolPtr(true)
+		Expect(IsEnabled(profile)).To(BeTrue())
+	})
+
+	It("should generate priority class with the default value", func() {
+		profile.Spec.PriorityClass = &performancev1.PriorityClass{Enabled: pointer.BoolPtr(true)}
+
+		priorityClass := New(profile)
+		Expect(priorityClass.Name).To(Equal(components.GetComponentName(profile.Name, components.ComponentNamePrefix)))
+		Expect(priorityClass.Value).To(Equal(DefaultValue))
+		Expect(priorityClass.GlobalDefault).To(BeFalse())
+	})
+
+	It("should generate priority class with the requested value", func() {
+		profile.Spec.PriorityClass = &performancev1.PriorityClass{
+			Enabled: pointer.BoolPtr(true),
+			Value:   pointer.Int32Ptr(5000),
+		}
+
+		priorityClass := New(profile)
+		Expect(priorityClass.Value).To(Equal(int32(5000)))
+	})
+})
@@ -0,0 +1,69 @@
+package profile
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+)
+
+const (
+	// CPUVendorIntel is the vendor ID of Intel CPUs
+	CPUVendorIntel = "Intel"
+	// CPUVendorAMD is the vendor ID of AMD CPUs
+	CPUVendorAMD = "AMD"
+)
+
+// CPUInfo describes the CPU of the node selected by the performance profile
+type CPUInfo struct {
+	NodeName string
+	VendorID string
+}
+
+// CPUInfoProvider returns CPU information of nodes selected by the performance profile,
+// nodes with unknown CPU should be omitted
+type CPUInfoProvider interface {
+	GetCPUInfo(profile *v1.PerformanceProfile) ([]CPUInfo, error)
+}
+
+// vendorKernelArgs contains kernel arguments that have effect only on CPUs of the specific vendor
+var vendorKernelArgs = map[string]string{
+	"intel_pstate":          CPUVendorIntel,
+	"intel_iommu":           CPUVendorIntel,
+	"intel_idle.max_cstate": CPUVendorIntel,
+	"amd_pstate":            CPUVendorAMD,
+	"amd_iommu":             CPUVendorAMD,
+}
+
+// ValidateCPUFeatures cross-checks kernel arguments requested by the profile against CPUs of the profile nodes,
+// a kernel argument that does not match the node CPU vendor is reported as the validation warning
+func ValidateCPUFeatures(profile *v1.PerformanceProfile, provider CPUInfoProvider) error {
+	cpuInfos, err := provider.GetCPUInfo(profile)
+	if err != nil {
+		return err
+	}
+
+	// kernel arguments generated for all profiles are safe to ignore on CPUs of other vendors,
+	// so we check only the arguments the user requested explicitly or via workload hints
+	additionalArgs := GetAdditionalKernelArgs(profile)
+	args := append(OverrideKernelArgs(GetWorkloadHintsKernelArgs(profile), additionalArgs), additionalArgs...)
+	for _, arg := range args {
+		name := strings.SplitN(arg, "=", 2)[0]
+		vendor, ok := vendorKernelArgs[name]
+		if !ok {
+			continue
+		}
+
+		for _, cpuInfo := range cpuInfos {
+			if cpuInfo.VendorID != "" && cpuInfo.VendorID != vendor {
+				warning := fmt.Sprintf("the kernel argument %q has effect only on %s CPUs, but the node %q has %s CPU", arg, vendor, cpuInfo.NodeName, cpuInfo.VendorID)
+				if err := validationWarning(profile, warning); err != nil {
+					return err
+				}
+				// one warning per argument is enough
+				break
+			}
+		}
+	}
+	return nil
+}
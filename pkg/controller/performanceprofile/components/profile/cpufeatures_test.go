@@ -0,0 +1,79 @@
+package profile
+
+import (
+	"fmt"
+
+	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+)
+
+type fakeCPUInfoProvider struct {
+	cpuInfos []CPUInfo
+	err      error
+}
+
+func (p *fakeCPUInfoProvider) GetCPUInfo(profile *v1.PerformanceProfile) ([]CPUInfo, error) {
+	return p.cpuInfos, p.err
+}
+
+var _ = Describe("CPU features validation", func() {
+	var profile *v1.PerformanceProfile
+	var provider *fakeCPUInfoProvider
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+		profile.Spec.AdditionalKernelArgs = []string{"intel_pstate=disable"}
+		provider = &fakeCPUInfoProvider{
+			cpuInfos: []CPUInfo{
+				{NodeName: "node-intel", VendorID: CPUVendorIntel},
+				{NodeName: "node-amd", VendorID: CPUVendorAMD},
+			},
+		}
+	})
+
+	It("should only warn by default", func() {
+		Expect(ValidateCPUFeatures(profile, provider)).ShouldNot(HaveOccurred())
+	})
+
+	It("should raise the validation error for the mismatched CPU vendor under the strict validation", func() {
+		profile.Annotations = map[string]string{v1.PerformanceProfileStrictValidationAnnotation: "true"}
+		err := ValidateCPUFeatures(profile, provider)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`the kernel argument "intel_pstate=disable" has effect only on Intel CPUs, but the node "node-amd" has AMD CPU`))
+	})
+
+	It("should check kernel arguments derived from workload hints", func() {
+		profile.Annotations = map[string]string{v1.PerformanceProfileStrictValidationAnnotation: "true"}
+		profile.Spec.AdditionalKernelArgs = nil
+		profile.Spec.WorkloadHints = &v1.WorkloadHints{HighPowerConsumption: pointer.BoolPtr(true)}
+		err := ValidateCPUFeatures(profile, provider)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`"intel_idle.max_cstate=0"`))
+	})
+
+	It("should pass the strict validation when CPU vendors match", func() {
+		profile.Annotations = map[string]string{v1.PerformanceProfileStrictValidationAnnotation: "true"}
+		provider.cpuInfos = []CPUInfo{{NodeName: "node-intel", VendorID: CPUVendorIntel}}
+		Expect(ValidateCPUFeatures(profile, provider)).ShouldNot(HaveOccurred())
+
+		profile.Spec.AdditionalKernelArgs = []string{"amd_iommu=on"}
+		provider.cpuInfos = []CPUInfo{{NodeName: "node-amd", VendorID: CPUVendorAMD}}
+		Expect(ValidateCPUFeatures(profile, provider)).ShouldNot(HaveOccurred())
+	})
+
+	It("should ignore nodes with unknown CPU vendor", func() {
+		profile.Annotations = map[string]string{v1.PerformanceProfileStrictValidationAnnotation: "true"}
+		provider.cpuInfos = []CPUInfo{{NodeName: "node-unknown"}}
+		Expect(ValidateCPUFeatures(profile, provider)).ShouldNot(HaveOccurred())
+	})
+
+	It("should return the provider error", func() {
+		provider.err = fmt.Errorf("failed to list nodes")
+		Expect(ValidateCPUFeatures(profile, provider)).To(MatchError("failed to list nodes"))
+	})
+})
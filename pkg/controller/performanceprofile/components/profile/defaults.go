@@ -0,0 +1,61 @@
+package profile
+
+import (
+	"fmt"
+
+	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpuset"
+)
+
+// defaultNodeSelectorKey is the node role label of nodes selected by the default performance profile
+const defaultNodeSelectorKey = "node-role.kubernetes.io/worker-cnf"
+
+// DefaultProfile returns the minimal performance profile for nodes with the given number of online CPUs,
+// the first reservedCount CPUs are reserved for the system and the rest of CPUs are isolated.
+// The returned profile selects worker-cnf nodes and passes the validation.
+func DefaultProfile(name string, onlineCPUs int, reservedCount int) (*v1.PerformanceProfile, error) {
+	if reservedCount <= 0 || reservedCount >= onlineCPUs {
+		return nil, fmt.Errorf("the reserved CPUs count %d should be in the range 1-%d for %d online CPUs", reservedCount, onlineCPUs-1, onlineCPUs)
+	}
+
+	var reservedCPUs, isolatedCPUs []int
+	for cpu := 0; cpu < onlineCPUs; cpu++ {
+		if cpu < reservedCount {
+			reservedCPUs = append(reservedCPUs, cpu)
+		} else {
+			isolatedCPUs = append(isolatedCPUs, cpu)
+		}
+	}
+	reserved := v1.CPUSet(cpuset.NewCPUSet(reservedCPUs...).String())
+	isolated := v1.CPUSet(cpuset.NewCPUSet(isolatedCPUs...).String())
+
+	profile := &v1.PerformanceProfile{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1.SchemeGroupVersion.String(),
+			Kind:       "PerformanceProfile",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: v1.PerformanceProfileSpec{
+			CPU: &v1.CPU{
+				Reserved: &reserved,
+				Isolated: &isolated,
+			},
+			NodeSelector: map[string]string{
+				defaultNodeSelectorKey: "",
+			},
+		},
+	}
+
+	if err := ValidateParameters(profile); err != nil {
+		return nil, err
+	}
+
+	if err := validateIsolatedCPUsPercentage(profile, onlineCPUs); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
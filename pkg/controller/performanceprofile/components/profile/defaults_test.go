@@ -0,0 +1,37 @@
+package profile
+
+import (
+	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Default profile", func() {
+	table.DescribeTable("should split online CPUs between reserved and isolated CPUs",
+		func(onlineCPUs int, reservedCount int, expectedReserved v1.CPUSet, expectedIsolated v1.CPUSet) {
+			profile, err := DefaultProfile("default", onlineCPUs, reservedCount)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(profile.Name).To(Equal("default"))
+			Expect(*profile.Spec.CPU.Reserved).To(Equal(expectedReserved))
+			Expect(*profile.Spec.CPU.Isolated).To(Equal(expectedIsolated))
+			Expect(profile.Spec.NodeSelector).To(HaveKeyWithValue(defaultNodeSelectorKey, ""))
+		},
+		table.Entry("with 4 online CPUs", 4, 1, v1.CPUSet("0"), v1.CPUSet("1-3")),
+		table.Entry("with 16 online CPUs", 16, 2, v1.CPUSet("0-1"), v1.CPUSet("2-15")),
+		table.Entry("with 64 online CPUs", 64, 8, v1.CPUSet("0-7"), v1.CPUSet("8-63")),
+	)
+
+	table.DescribeTable("should reject the invalid reserved CPUs count",
+		func(onlineCPUs int, reservedCount int, expectedError string) {
+			_, err := DefaultProfile("default", onlineCPUs, reservedCount)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(expectedError))
+		},
+		table.Entry("with all CPUs reserved", 4, 4, "the reserved CPUs count 4 should be in the range 1-3 for 4 online CPUs"),
+		table.Entry("without reserved CPUs", 4, 0, "the reserved CPUs count 0 should be in the range 1-3 for 4 online CPUs"),
+		table.Entry("with too many isolated CPUs", 16, 1, "that exceeds 90% of online CPUs"),
+	)
+})
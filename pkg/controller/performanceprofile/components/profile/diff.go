@@ -0,0 +1,197 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+)
+
+// hugepagesPagesPath is the path of huge pages entries, that are compared by the size and the NUMA node
+// instead of the position under the list
+const hugepagesPagesPath = "spec.hugepages.pages"
+
+// FieldChange describes the change of the single performance profile field, the old value is empty
+// for added fields and the new value is empty for removed fields
+type FieldChange struct {
+	Path string
+	Old  string
+	New  string
+}
+
+// DiffProfiles returns the list of changed spec fields between two performance profiles sorted by the field path
+func DiffProfiles(oldProfile *v1.PerformanceProfile, newProfile *v1.PerformanceProfile) ([]FieldChange, error) {
+	oldFields, err := flattenSpec(&oldProfile.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	newFields, err := flattenSpec(&newProfile.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []FieldChange
+	for path, oldValue := range oldFields {
+		if newValue := newFields[path]; newValue != oldValue {
+			changes = append(changes, FieldChange{Path: path, Old: oldValue, New: newValue})
+		}
+	}
+
+	for path, newValue := range newFields {
+		if _, ok := oldFields[path]; !ok {
+			changes = append(changes, FieldChange{Path: path, New: newValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+// flattenSpec returns the spec fields values mapped by the field path
+func flattenSpec(spec *v1.PerformanceProfileSpec) (map[string]string, error) {
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+
+	fields := map[string]string{}
+	if err := flatten("spec", data, fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func flatten(path string, value interface{}, fields map[string]string) error {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range typed {
+			if err := flatten(path+"."+key, nested, fields); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		if path == hugepagesPagesPath {
+			return flattenHugepages(typed, fields)
+		}
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	fields[path] = string(raw)
+	return nil
+}
+
+// flattenHugepages keys huge pages entries by the size and the NUMA node, so reordering of entries
+// does not produce changes and added or removed entries are reported as such
+func flattenHugepages(pages []interface{}, fields map[string]string) error {
+	for _, page := range pages {
+		entry, ok := page.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unexpected huge pages entry %v", page)
+		}
+
+		key := fmt.Sprintf("%v", entry["size"])
+		if node, ok := entry["node"]; ok {
+			key = fmt.Sprintf("%s,node=%v", key, node)
+		}
+
+		raw, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		fields[fmt.Sprintf("%s[%s]", hugepagesPagesPath, key)] = string(raw)
+	}
+	return nil
+}
+
+// HugePageChange describes the change of huge pages reservation of the single size on the single NUMA node,
+// the node is nil for pages allocated equally between NUMA nodes, the old count is zero for added reservations
+// and the new count is zero for removed reservations
+type HugePageChange struct {
+	Size     v1.HugePageSize
+	Node     *int32
+	OldCount int32
+	NewCount int32
+}
+
+// MemoryKilobytes returns the amount of memory the change reserves, the value is negative when the change
+// releases the memory and zero for unsupported huge pages sizes
+func (c HugePageChange) MemoryKilobytes() int64 {
+	return int64(c.NewCount-c.OldCount) * hugepagesSizeKilobytes[c.Size]
+}
+
+type hugePageKey struct {
+	size v1.HugePageSize
+	node int32
+	// numa is false for pages allocated equally between NUMA nodes
+	numa bool
+}
+
+// HugePageDelta returns added, removed and changed huge pages reservations between two performance profiles
+// sorted by the page size and the NUMA node
+func HugePageDelta(oldProfile *v1.PerformanceProfile, newProfile *v1.PerformanceProfile) []HugePageChange {
+	changes := map[hugePageKey]*HugePageChange{}
+	getChange := func(page v1.HugePage) *HugePageChange {
+		key := hugePageKey{size: v1.HugePageSize(strings.ToUpper(string(page.Size)))}
+		if page.Node != nil {
+			key.node = *page.Node
+			key.numa = true
+		}
+
+		change, ok := changes[key]
+		if !ok {
+			change = &HugePageChange{Size: key.size}
+			if key.numa {
+				node := key.node
+				change.Node = &node
+			}
+			changes[key] = change
+		}
+		return change
+	}
+
+	if oldProfile.Spec.HugePages != nil {
+		for _, page := range oldProfile.Spec.HugePages.Pages {
+			getChange(page).OldCount += page.Count
+		}
+	}
+
+	if newProfile.Spec.HugePages != nil {
+		for _, page := range newProfile.Spec.HugePages.Pages {
+			getChange(page).NewCount += page.Count
+		}
+	}
+
+	var delta []HugePageChange
+	for _, change := range changes {
+		if change.OldCount != change.NewCount {
+			delta = append(delta, *change)
+		}
+	}
+
+	sort.Slice(delta, func(i, j int) bool {
+		left, right := delta[i], delta[j]
+		if left.Size != right.Size {
+			return hugepagesSizeKilobytes[left.Size] < hugepagesSizeKilobytes[right.Size]
+		}
+		// pages without the NUMA node go first
+		if left.Node == nil || right.Node == nil {
+			return left.Node == nil && right.Node != nil
+		}
+		return *left.Node < *right.Node
+	})
+	return delta
+}
@@ -0,0 +1,136 @@
+package profile
+
+import (
+	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+)
+
+var _ = Describe("PerformanceProfile diff", func() {
+	var oldProfile *v1.PerformanceProfile
+	var newProfile *v1.PerformanceProfile
+
+	BeforeEach(func() {
+		oldProfile = testutils.NewPerformanceProfile("test")
+		newProfile = oldProfile.DeepCopy()
+	})
+
+	It("should not report changes for equal profiles", func() {
+		changes, err := DiffProfiles(oldProfile, newProfile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(BeEmpty())
+	})
+
+	It("should report changed fields with old and new values", func() {
+		isolated := v1.CPUSet("2-7")
+		newProfile.Spec.CPU.Isolated = &isolated
+		newProfile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+
+		changes, err := DiffProfiles(oldProfile, newProfile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(Equal([]FieldChange{
+			{Path: "spec.cpu.isolated", Old: `"4-7"`, New: `"2-7"`},
+			{Path: "spec.realTimeKernel.enabled", Old: "true", New: "false"},
+		}))
+	})
+
+	It("should report added and removed fields", func() {
+		newProfile.Spec.ClockSource = pointer.StringPtr("tsc")
+		newProfile.Spec.NUMA = nil
+
+		changes, err := DiffProfiles(oldProfile, newProfile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(Equal([]FieldChange{
+			{Path: "spec.clockSource", New: `"tsc"`},
+			{Path: "spec.numa.topologyPolicy", Old: `"single-numa-node"`},
+		}))
+	})
+
+	It("should report added and removed huge pages entries", func() {
+		oldProfile.Spec.HugePages.Pages = []v1.HugePage{
+			{Size: hugepagesSize1G, Count: 4},
+			{Size: hugepagesSize2M, Count: 128, Node: pointer.Int32Ptr(0)},
+		}
+		newProfile.Spec.HugePages.Pages = []v1.HugePage{
+			{Size: hugepagesSize2M, Count: 128, Node: pointer.Int32Ptr(1)},
+			{Size: hugepagesSize1G, Count: 8},
+		}
+
+		changes, err := DiffProfiles(oldProfile, newProfile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(Equal([]FieldChange{
+			{Path: "spec.hugepages.pages[1G]", Old: `{"count":4,"size":"1G"}`, New: `{"count":8,"size":"1G"}`},
+			{Path: "spec.hugepages.pages[2M,node=0]", Old: `{"count":128,"node":0,"size":"2M"}`},
+			{Path: "spec.hugepages.pages[2M,node=1]", New: `{"count":128,"node":1,"size":"2M"}`},
+		}))
+	})
+
+	It("should ignore reordering of huge pages entries", func() {
+		newProfile.Spec.HugePages.Pages = append(newProfile.Spec.HugePages.Pages, v1.HugePage{Size: hugepagesSize2M, Count: 128})
+		oldProfile.Spec.HugePages.Pages = append([]v1.HugePage{{Size: hugepagesSize2M, Count: 128}}, oldProfile.Spec.HugePages.Pages...)
+
+		changes, err := DiffProfiles(oldProfile, newProfile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changes).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Huge pages delta", func() {
+	var oldProfile *v1.PerformanceProfile
+	var newProfile *v1.PerformanceProfile
+
+	BeforeEach(func() {
+		oldProfile = testutils.NewPerformanceProfile("test")
+		oldProfile.Spec.HugePages.Pages = []v1.HugePage{
+			{Size: hugepagesSize1G, Count: 4},
+			{Size: hugepagesSize2M, Count: 128, Node: pointer.Int32Ptr(0)},
+		}
+		newProfile = oldProfile.DeepCopy()
+	})
+
+	It("should not report changes for equal huge pages", func() {
+		newProfile.Spec.HugePages.Pages = []v1.HugePage{
+			{Size: hugepagesSize2M, Count: 128, Node: pointer.Int32Ptr(0)},
+			{Size: "1g", Count: 4},
+		}
+		Expect(HugePageDelta(oldProfile, newProfile)).To(BeEmpty())
+	})
+
+	It("should report added, removed and changed reservations", func() {
+		newProfile.Spec.HugePages.Pages = []v1.HugePage{
+			{Size: hugepagesSize2M, Count: 128, Node: pointer.Int32Ptr(1)},
+			{Size: hugepagesSize1G, Count: 8},
+		}
+
+		delta := HugePageDelta(oldProfile, newProfile)
+		Expect(delta).To(Equal([]HugePageChange{
+			{Size: hugepagesSize2M, Node: pointer.Int32Ptr(0), OldCount: 128},
+			{Size: hugepagesSize2M, Node: pointer.Int32Ptr(1), NewCount: 128},
+			{Size: hugepagesSize1G, OldCount: 4, NewCount: 8},
+		}))
+		Expect(delta[0].MemoryKilobytes()).To(Equal(int64(-128 * 2048)))
+		Expect(delta[1].MemoryKilobytes()).To(Equal(int64(128 * 2048)))
+		Expect(delta[2].MemoryKilobytes()).To(Equal(int64(4 * 1048576)))
+	})
+
+	It("should keep pages without NUMA node apart from per node pages", func() {
+		newProfile.Spec.HugePages.Pages = append(newProfile.Spec.HugePages.Pages, v1.HugePage{Size: hugepagesSize1G, Count: 2, Node: pointer.Int32Ptr(0)})
+
+		Expect(HugePageDelta(oldProfile, newProfile)).To(Equal([]HugePageChange{
+			{Size: hugepagesSize1G, Node: pointer.Int32Ptr(0), NewCount: 2},
+		}))
+	})
+
+	It("should report all reservations when huge pages are removed", func() {
+		newProfile.Spec.HugePages = nil
+
+		Expect(HugePageDelta(oldProfile, newProfile)).To(Equal([]HugePageChange{
+			{Size: hugepagesSize2M, Node: pointer.Int32Ptr(0), OldCount: 128},
+			{Size: hugepagesSize1G, OldCount: 4},
+		}))
+	})
+})
@@ -0,0 +1,112 @@
+package profile
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpuset"
+)
+
+// hasIsolatedCPUs returns true when the profile isolates CPUs either via the isolated CPUs or via isolated groups
+func hasIsolatedCPUs(profile *v1.PerformanceProfile) bool {
+	return profile.Spec.CPU != nil && (profile.Spec.CPU.Isolated != nil || len(profile.Spec.CPU.IsolatedGroups) > 0)
+}
+
+// GetIsolatedCPUs returns the union of the isolated CPUs and CPUs of all isolated groups,
+// the set is empty when the profile does not isolate CPUs
+func GetIsolatedCPUs(profile *v1.PerformanceProfile) (cpuset.CPUSet, error) {
+	isolated := cpuset.NewCPUSet()
+	if profile.Spec.CPU == nil {
+		return isolated, nil
+	}
+
+	if profile.Spec.CPU.Isolated != nil {
+		cpus, err := components.ParseCPUList(string(*profile.Spec.CPU.Isolated))
+		if err != nil {
+			return isolated, fmt.Errorf("failed to parse isolated CPUs: %v", err)
+		}
+		isolated = isolated.Union(cpus)
+	}
+
+	for _, group := range profile.Spec.CPU.IsolatedGroups {
+		cpus, err := components.ParseCPUList(string(group.CPUs))
+		if err != nil {
+			return isolated, fmt.Errorf("failed to parse CPUs of the isolated group %q: %v", group.Name, err)
+		}
+		isolated = isolated.Union(cpus)
+	}
+	return isolated, nil
+}
+
+// FormatIsolatedCPUGroups returns isolated groups in the form of "name=cpus" pairs separated by semicolons,
+// e.g. "dpdk=4-5;rt=6-7", the value is empty when the profile does not have isolated groups
+func FormatIsolatedCPUGroups(profile *v1.PerformanceProfile) string {
+	if profile.Spec.CPU == nil {
+		return ""
+	}
+
+	var groups []string
+	for _, group := range profile.Spec.CPU.IsolatedGroups {
+		cpus := string(group.CPUs)
+		// malformed CPUs are rejected by the validation, keep the original value in such case
+		if normalized, err := components.NormalizeCPUList(cpus); err == nil {
+			cpus = normalized
+		}
+		groups = append(groups, fmt.Sprintf("%s=%s", group.Name, cpus))
+	}
+	return strings.Join(groups, ";")
+}
+
+// validateIsolatedGroups verifies that isolated groups have unique names and do not overlap each other and the reserved CPUs
+func validateIsolatedGroups(cpu *v1.CPU) error {
+	reserved := cpuset.NewCPUSet()
+	if cpu.Reserved != nil {
+		var err error
+		reserved, err = components.ParseCPUList(string(*cpu.Reserved))
+		if err != nil {
+			return validationError(fmt.Sprintf("failed to parse reserved CPUs: %v", err))
+		}
+	}
+
+	groupCPUs := map[string]cpuset.CPUSet{}
+	for _, group := range cpu.IsolatedGroups {
+		if errs := validation.IsDNS1123Label(group.Name); len(errs) > 0 {
+			return validationError(fmt.Sprintf("the isolated group name %q is invalid: %s", group.Name, strings.Join(errs, ", ")))
+		}
+
+		if _, ok := groupCPUs[group.Name]; ok {
+			return validationError(fmt.Sprintf("the isolated group %q is duplicated", group.Name))
+		}
+
+		cpus, err := components.ParseCPUList(string(group.CPUs))
+		if err != nil {
+			return validationError(fmt.Sprintf("failed to parse CPUs of the isolated group %q: %v", group.Name, err))
+		}
+
+		if cpus.IsEmpty() {
+			return validationError(fmt.Sprintf("the isolated group %q should have at least one CPU", group.Name))
+		}
+
+		if overlap := cpus.Intersection(reserved); !overlap.IsEmpty() {
+			return validationError(fmt.Sprintf("the isolated group %q overlaps the reserved CPUs %q", group.Name, overlap))
+		}
+
+		// iterate over groups in the spec order, so the error does not depend on the map order
+		for _, other := range cpu.IsolatedGroups {
+			otherCPUs, ok := groupCPUs[other.Name]
+			if !ok {
+				continue
+			}
+			if overlap := cpus.Intersection(otherCPUs); !overlap.IsEmpty() {
+				return validationError(fmt.Sprintf("the isolated groups %q and %q overlap on CPUs %q", other.Name, group.Name, overlap))
+			}
+		}
+
+		groupCPUs[group.Name] = cpus
+	}
+	return nil
+}
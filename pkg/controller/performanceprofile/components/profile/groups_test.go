@@ -0,0 +1,88 @@
+package profile
+
+import (
+	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+)
+
+var _ = Describe("Isolated CPU groups", func() {
+	var profile *v1.PerformanceProfile
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+		reserved := v1.CPUSet("0-3")
+		isolated := v1.CPUSet("4-5")
+		profile.Spec.CPU.Reserved = &reserved
+		profile.Spec.CPU.Isolated = &isolated
+		profile.Spec.CPU.IsolatedGroups = []v1.IsolatedCPUGroup{
+			{Name: "dpdk", CPUs: "6,7"},
+			{Name: "rt", CPUs: "8-9"},
+		}
+	})
+
+	It("should union isolated CPUs with CPUs of all groups", func() {
+		Expect(ValidateParameters(profile)).ToNot(HaveOccurred())
+
+		isolated, err := GetIsolatedCPUs(profile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(isolated.String()).To(Equal("4-9"))
+
+		count, err := IsolatedCount(profile, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(count).To(Equal(6))
+	})
+
+	It("should isolate CPUs of groups without the isolated CPUs", func() {
+		profile.Spec.CPU.Isolated = nil
+		profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+		Expect(ValidateParameters(profile)).ToNot(HaveOccurred())
+
+		isolated, err := GetIsolatedCPUs(profile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(isolated.String()).To(Equal("6-9"))
+	})
+
+	It("should keep group CPUs that overlap the isolated CPUs", func() {
+		isolated := v1.CPUSet("4-9")
+		profile.Spec.CPU.Isolated = &isolated
+		Expect(ValidateParameters(profile)).ToNot(HaveOccurred())
+
+		cpus, err := GetIsolatedCPUs(profile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cpus.String()).To(Equal("4-9"))
+	})
+
+	It("should format groups with normalized CPUs", func() {
+		Expect(FormatIsolatedCPUGroups(profile)).To(Equal("dpdk=6-7;rt=8-9"))
+
+		profile.Spec.CPU.IsolatedGroups = nil
+		Expect(FormatIsolatedCPUGroups(profile)).To(BeEmpty())
+	})
+
+	table.DescribeTable("should reject invalid groups",
+		func(groups []v1.IsolatedCPUGroup, expected string) {
+			profile.Spec.CPU.IsolatedGroups = groups
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(expected))
+		},
+		table.Entry("overlapping groups", []v1.IsolatedCPUGroup{{Name: "dpdk", CPUs: "6-8"}, {Name: "rt", CPUs: "8-9"}},
+			`the isolated groups "dpdk" and "rt" overlap on CPUs "8"`),
+		table.Entry("group overlapping reserved CPUs", []v1.IsolatedCPUGroup{{Name: "dpdk", CPUs: "3-4"}},
+			`the isolated group "dpdk" overlaps the reserved CPUs "3"`),
+		table.Entry("duplicated group", []v1.IsolatedCPUGroup{{Name: "dpdk", CPUs: "6"}, {Name: "dpdk", CPUs: "7"}},
+			`the isolated group "dpdk" is duplicated`),
+		table.Entry("invalid name", []v1.IsolatedCPUGroup{{Name: "DPDK_cores", CPUs: "6"}},
+			`the isolated group name "DPDK_cores" is invalid`),
+		table.Entry("empty CPUs", []v1.IsolatedCPUGroup{{Name: "dpdk", CPUs: ""}},
+			`the isolated group "dpdk" should have at least one CPU`),
+		table.Entry("malformed CPUs", []v1.IsolatedCPUGroup{{Name: "dpdk", CPUs: "6-a"}},
+			`failed to parse CPUs of the isolated group "dpdk"`),
+	)
+})
@@ -0,0 +1,79 @@
+package profile
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodLister returns pods running on nodes selected by the performance profile
+type PodLister interface {
+	ListPods(profile *v1.PerformanceProfile) ([]corev1.Pod, error)
+}
+
+// ValidateIsolationReduction verifies that isolated CPUs are not removed from the profile while pods with exclusive CPUs
+// run on the profile nodes, the kubelet pinned these pods to CPUs that can stop being isolated.
+// The previous isolated CPUs are the ones currently applied to the profile nodes, the check is skipped when they are empty.
+func ValidateIsolationReduction(profile *v1.PerformanceProfile, previousIsolated string, lister PodLister) error {
+	if previousIsolated == "" {
+		return nil
+	}
+
+	previous, err := components.ParseCPUList(previousIsolated)
+	if err != nil {
+		return err
+	}
+
+	isolated, err := GetIsolatedCPUs(profile)
+	if err != nil {
+		return err
+	}
+
+	removed := previous.Difference(isolated)
+	if removed.IsEmpty() {
+		return nil
+	}
+
+	pods, err := lister.ListPods(profile)
+	if err != nil {
+		return err
+	}
+
+	var pinned []string
+	for i := range pods {
+		if hasExclusiveCPUs(&pods[i]) {
+			pinned = append(pinned, fmt.Sprintf("%s/%s", pods[i].Namespace, pods[i].Name))
+		}
+	}
+
+	if len(pinned) == 0 {
+		return nil
+	}
+
+	warning := fmt.Sprintf("the isolated CPUs %q are removed while pods with exclusive CPUs run on the profile nodes: %s", removed.String(), strings.Join(pinned, ", "))
+	return validationWarning(profile, warning)
+}
+
+// hasExclusiveCPUs returns true for the running Guaranteed pod with integer CPU requests,
+// the kubelet static CPU manager policy pins containers of such pods to exclusive CPUs
+func hasExclusiveCPUs(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return false
+	}
+
+	if pod.Status.QOSClass != corev1.PodQOSGuaranteed {
+		return false
+	}
+
+	for _, container := range pod.Spec.Containers {
+		cpu, ok := container.Resources.Requests[corev1.ResourceCPU]
+		if ok && cpu.MilliValue()%1000 == 0 && cpu.Value() > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package profile
+
+import (
+	"fmt"
+
+	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+)
+
+type fakePodLister struct {
+	pods []corev1.Pod
+	err  error
+}
+
+func (l *fakePodLister) ListPods(profile *v1.PerformanceProfile) ([]corev1.Pod, error) {
+	return l.pods, l.err
+}
+
+func newPod(name string, qosClass corev1.PodQOSClass, cpu string) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test",
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "test",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+						Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:    corev1.PodRunning,
+			QOSClass: qosClass,
+		},
+	}
+}
+
+var _ = Describe("Isolation reduction validation", func() {
+	var profile *v1.PerformanceProfile
+	var lister *fakePodLister
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+		profile.Annotations = map[string]string{v1.PerformanceProfileStrictValidationAnnotation: "true"}
+		isolated := v1.CPUSet("4-5")
+		profile.Spec.CPU.Isolated = &isolated
+		lister = &fakePodLister{
+			pods: []corev1.Pod{newPod("pinned", corev1.PodQOSGuaranteed, "2")},
+		}
+	})
+
+	It("should only warn by default", func() {
+		profile.Annotations = nil
+		Expect(ValidateIsolationReduction(profile, "4-7", lister)).ShouldNot(HaveOccurred())
+	})
+
+	It("should raise the validation error when isolated CPUs are removed under the strict validation", func() {
+		err := ValidateIsolationReduction(profile, "4-7", lister)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`the isolated CPUs "6-7" are removed while pods with exclusive CPUs run on the profile nodes: test/pinned`))
+	})
+
+	It("should raise the validation error when the isolation is removed completely", func() {
+		profile.Spec.CPU.Isolated = nil
+		err := ValidateIsolationReduction(profile, "4-7", lister)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`"4-7"`))
+	})
+
+	It("should pass when isolated CPUs are extended", func() {
+		Expect(ValidateIsolationReduction(profile, "4", lister)).ShouldNot(HaveOccurred())
+	})
+
+	It("should pass without pods with exclusive CPUs", func() {
+		finished := newPod("finished", corev1.PodQOSGuaranteed, "2")
+		finished.Status.Phase = corev1.PodSucceeded
+		lister.pods = []corev1.Pod{
+			newPod("burstable", corev1.PodQOSBurstable, "2"),
+			newPod("shared", corev1.PodQOSGuaranteed, "1500m"),
+			finished,
+		}
+		Expect(ValidateIsolationReduction(profile, "4-7", lister)).ShouldNot(HaveOccurred())
+	})
+
+	It("should skip the validation without previous isolated CPUs", func() {
+		lister.err = fmt.Errorf("failed to list pods")
+		Expect(ValidateIsolationReduction(profile, "", lister)).ShouldNot(HaveOccurred())
+	})
+
+	It("should return the lister error", func() {
+		lister.err = fmt.Errorf("failed to list pods")
+		Expect(ValidateIsolationReduction(profile, "4-7", lister)).To(MatchError("failed to list pods"))
+	})
+})
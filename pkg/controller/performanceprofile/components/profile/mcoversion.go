@@ -0,0 +1,55 @@
+package profile
+
+import (
+	"fmt"
+
+	"github.com/blang/semver"
+
+	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+)
+
+// MCOVersionProvider returns the version of the machine config operator installed on the cluster
+type MCOVersionProvider interface {
+	// GetMCOVersion returns the machine config operator version, empty string means that the version is unknown
+	GetMCOVersion() (string, error)
+}
+
+// mcoFeatures contains profile features that the machine config operator supports starting from the minimal version
+var mcoFeatures = []struct {
+	name       string
+	minVersion semver.Version
+	isUsed     func(profile *v1.PerformanceProfile) bool
+}{
+	{
+		name:       "the real time kernel",
+		minVersion: semver.MustParse("4.4.0"),
+		isUsed:     IsRealTimeKernelEnabled,
+	},
+}
+
+// ValidateMCOVersion verifies that the installed machine config operator supports all features the profile uses,
+// pre-release and build suffixes of the version, like "4.6.0-0.nightly-2020-09-01", are ignored
+func ValidateMCOVersion(profile *v1.PerformanceProfile, provider MCOVersionProvider) error {
+	value, err := provider.GetMCOVersion()
+	if err != nil {
+		return err
+	}
+
+	// we can not validate features without the version
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := semver.ParseTolerant(value)
+	if err != nil {
+		return fmt.Errorf("failed to parse the machine config operator version %q: %v", value, err)
+	}
+	version := semver.Version{Major: parsed.Major, Minor: parsed.Minor, Patch: parsed.Patch}
+
+	for _, feature := range mcoFeatures {
+		if feature.isUsed(profile) && version.LT(feature.minVersion) {
+			return validationError(fmt.Sprintf("%s requires the machine config operator version %s or newer, the installed version is %s", feature.name, feature.minVersion, value))
+		}
+	}
+	return nil
+}
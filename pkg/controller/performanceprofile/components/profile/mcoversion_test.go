@@ -0,0 +1,59 @@
+package profile
+
+import (
+	"fmt"
+
+	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+)
+
+type fakeMCOVersionProvider struct {
+	version string
+	err     error
+}
+
+func (p *fakeMCOVersionProvider) GetMCOVersion() (string, error) {
+	return p.version, p.err
+}
+
+var _ = Describe("Machine config operator version validation", func() {
+	var profile *v1.PerformanceProfile
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+	})
+
+	table.DescribeTable("should validate the real time kernel against the machine config operator version",
+		func(version string, expectedError string) {
+			err := ValidateMCOVersion(profile, &fakeMCOVersionProvider{version: version})
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(expectedError))
+		},
+		table.Entry("with the minimal version", "4.4.0", ""),
+		table.Entry("with the newer version", "4.6.1", ""),
+		table.Entry("with the nightly build of the minimal version", "4.4.0-0.nightly-2020-03-01-215047", ""),
+		table.Entry("with the unknown version", "", ""),
+		table.Entry("with the older version", "4.3.5", "the real time kernel requires the machine config operator version 4.4.0 or newer, the installed version is 4.3.5"),
+		table.Entry("with the malformed version", "four", `failed to parse the machine config operator version "four"`),
+	)
+
+	It("should accept the older version without the real time kernel", func() {
+		profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+		Expect(ValidateMCOVersion(profile, &fakeMCOVersionProvider{version: "4.3.5"})).To(Succeed())
+	})
+
+	It("should return the provider error", func() {
+		err := ValidateMCOVersion(profile, &fakeMCOVersionProvider{err: fmt.Errorf("failed")})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,115 @@
+package profile
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+)
+
+// PreflightCategory defines the part of the profile configuration the preflight problem belongs to
+type PreflightCategory string
+
+const (
+	// PreflightCategoryValidation contains problems found by the profile parameters validation
+	PreflightCategoryValidation PreflightCategory = "Validation"
+	// PreflightCategoryCPU contains problems of the reserved and isolated CPUs
+	PreflightCategoryCPU PreflightCategory = "CPU"
+	// PreflightCategoryHugepages contains problems of the huge pages reservation
+	PreflightCategoryHugepages PreflightCategory = "HugePages"
+)
+
+// PreflightProblem describes the single reason why the profile configuration can not be applied
+type PreflightProblem struct {
+	Category PreflightCategory
+	Message  string
+}
+
+// PreflightReport contains all problems the preflight found, the report without problems means
+// that the profile produces the bootable configuration
+type PreflightReport struct {
+	Problems []PreflightProblem
+}
+
+// HasProblems returns true when the preflight found at least one problem
+func (r *PreflightReport) HasProblems() bool {
+	return len(r.Problems) > 0
+}
+
+// String returns problems of the report one per line, prefixed with the problem category
+func (r *PreflightReport) String() string {
+	lines := make([]string, 0, len(r.Problems))
+	for _, problem := range r.Problems {
+		lines = append(lines, fmt.Sprintf("[%s] %s", problem.Category, problem.Message))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r *PreflightReport) add(category PreflightCategory, err error) {
+	if err == nil {
+		return
+	}
+	r.Problems = append(r.Problems, PreflightProblem{Category: category, Message: err.Error()})
+}
+
+// Preflight runs the profile validation together with sanity checks of the configuration nodes boot with:
+// the reserved CPUs are not empty, CPUs stay online once SMT is disabled and huge pages fit into the node memory.
+// Unlike the validation, it does not stop on the first problem, so the report has all problems at once.
+// The nil provider or the unknown topology skip checks that need the topology of the profile nodes.
+func Preflight(profile *v1.PerformanceProfile, provider TopologyProvider) *PreflightReport {
+	report := &PreflightReport{}
+	report.add(PreflightCategoryValidation, ValidateParameters(profile))
+	report.add(PreflightCategoryCPU, validateReservedCPUsNotEmpty(profile))
+	report.add(PreflightCategoryHugepages, validateHugepagesMemory(profile, provider))
+
+	if provider != nil {
+		report.add(PreflightCategoryCPU, ValidateSMTSiblings(profile, provider))
+		report.add(PreflightCategoryCPU, ValidateIsolatedCPUsPercentage(profile, provider))
+		report.add(PreflightCategoryHugepages, ValidateHugepagesNUMANodes(profile, provider))
+	}
+	return report
+}
+
+// validateReservedCPUsNotEmpty verifies that the system services have at least one reserved CPU to run on
+func validateReservedCPUsNotEmpty(profile *v1.PerformanceProfile) error {
+	if profile.Spec.CPU == nil || profile.Spec.CPU.Reserved == nil {
+		return validationError("the reserved CPUs are not set, the system services need at least one CPU")
+	}
+
+	reserved, err := components.ParseCPUList(string(*profile.Spec.CPU.Reserved))
+	if err != nil {
+		return err
+	}
+	if reserved.IsEmpty() {
+		return validationError("the reserved CPUs are empty, the system services need at least one CPU")
+	}
+	return nil
+}
+
+// validateHugepagesMemory verifies that huge pages of all sizes fit into the memory of the profile nodes,
+// the kernel can not boot once huge pages reserved at boot take all the node memory
+func validateHugepagesMemory(profile *v1.PerformanceProfile, provider TopologyProvider) error {
+	if profile.Spec.HugePages == nil || provider == nil {
+		return nil
+	}
+
+	memory, err := provider.GetMemoryKilobytes(profile)
+	if err != nil {
+		return err
+	}
+
+	// we can not validate huge pages without the topology
+	if memory == 0 {
+		return nil
+	}
+
+	var total int64
+	for _, page := range profile.Spec.HugePages.Pages {
+		total += int64(page.Count) * hugepagesSizeKilobytes[v1.HugePageSize(strings.ToUpper(string(page.Size)))]
+	}
+	if total >= memory {
+		return validationError(fmt.Sprintf("the huge pages take %d kB, but the profile nodes have only %d kB of memory", total, memory))
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package profile
+
+import (
+	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpuset"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+)
+
+var _ = Describe("Preflight", func() {
+	var profile *v1.PerformanceProfile
+	var provider *fakeTopologyProvider
+
+	getCategories := func(report *PreflightReport) []PreflightCategory {
+		var categories []PreflightCategory
+		for _, problem := range report.Problems {
+			categories = append(categories, problem.Category)
+		}
+		return categories
+	}
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+		// cores with hardware threads 0,4 1,5 2,6 and 3,7 on a single NUMA node with 16 GiB of memory
+		provider = &fakeTopologyProvider{
+			numaNodes: 1,
+			siblings: []cpuset.CPUSet{
+				cpuset.NewCPUSet(0, 4),
+				cpuset.NewCPUSet(1, 5),
+				cpuset.NewCPUSet(2, 6),
+				cpuset.NewCPUSet(3, 7),
+			},
+			memory: 16 * 1024 * 1024,
+		}
+	})
+
+	It("should not report problems for the bootable configuration", func() {
+		report := Preflight(profile, provider)
+		Expect(report.HasProblems()).To(BeFalse(), report.String())
+	})
+
+	It("should skip topology checks without the provider", func() {
+		profile.Spec.HugePages.Pages[0].Count = 64
+		Expect(Preflight(profile, nil).HasProblems()).To(BeFalse())
+	})
+
+	It("should report problems of all categories at once", func() {
+		// the parameters validation rejects the unknown huge pages default size
+		defaultSize := v1.HugePageSize("3M")
+		profile.Spec.HugePages.DefaultHugePagesSize = &defaultSize
+		// isolated CPUs 4-7 are SMT siblings that go offline
+		profile.Spec.AdditionalKernelArgs = []string{"nosmt"}
+		// 16 huge pages of 1G take all the node memory and the NUMA node 1 does not exist
+		profile.Spec.HugePages.Pages = []v1.HugePage{{Size: hugepagesSize1G, Count: 16, Node: pointer.Int32Ptr(1)}}
+
+		report := Preflight(profile, provider)
+		Expect(report.HasProblems()).To(BeTrue())
+		Expect(getCategories(report)).To(Equal([]PreflightCategory{
+			PreflightCategoryValidation,
+			PreflightCategoryHugepages,
+			PreflightCategoryCPU,
+			PreflightCategoryHugepages,
+		}))
+
+		Expect(report.String()).To(ContainSubstring("[Validation] validation error: hugepages default size should be equal to"))
+		Expect(report.String()).To(ContainSubstring("[HugePages] validation error: the huge pages take 16777216 kB, but the profile nodes have only 16777216 kB of memory"))
+		Expect(report.String()).To(ContainSubstring(`[CPU] validation error: the isolated CPUs "4-7" are SMT siblings that go offline once SMT is disabled`))
+		Expect(report.String()).To(ContainSubstring(`[HugePages] validation error: the huge pages "1G" can not be allocated on the NUMA node 1, the profile nodes have only 1 NUMA nodes`))
+	})
+
+	It("should report the empty reserved CPUs together with the validation problem", func() {
+		reserved := v1.CPUSet("")
+		profile.Spec.CPU.Reserved = &reserved
+
+		report := Preflight(profile, nil)
+		Expect(getCategories(report)).To(ContainElement(PreflightCategoryCPU))
+		Expect(report.String()).To(ContainSubstring("[CPU] validation error: the reserved CPUs are empty, the system services need at least one CPU"))
+	})
+
+	It("should report the missing reserved CPUs", func() {
+		profile.Spec.CPU.Reserved = nil
+
+		report := Preflight(profile, nil)
+		Expect(report.String()).To(ContainSubstring("[CPU] validation error: the reserved CPUs are not set"))
+	})
+})
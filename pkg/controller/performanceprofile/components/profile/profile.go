@@ -2,10 +2,18 @@ package profile
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
 
 	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog"
 	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpuset"
 )
 
 const (
@@ -13,17 +21,222 @@ const (
 	hugepagesSize1G = "1G"
 )
 
+// hugepagesSizeKilobytes contains the size of supported huge pages in kilobytes
+var hugepagesSizeKilobytes = map[v1.HugePageSize]int64{
+	hugepagesSize2M:             2048,
+	hugepagesSize1G:             1048576,
+	components.HugepagesSize16M: 16384,
+	components.HugepagesSize16G: 16777216,
+}
+
+// maxHugepagesKilobytes is the upper bound of memory that huge pages of the single size can take, 16TiB
+const maxHugepagesKilobytes = int64(16) * 1024 * 1024 * 1024
+
+// kernelArgNoSMT is the kernel argument that disables simultaneous multithreading
+const kernelArgNoSMT = "nosmt"
+
+// maxUserDefinablePriority is the highest priority value a user defined PriorityClass can have,
+// higher values are reserved for the system priority classes
+const maxUserDefinablePriority = int32(1000000000)
+
+// dedicatedKernelArgs contains kernel arguments that the operator generates from the dedicated profile fields,
+// providing the same argument via additional kernel arguments contradicts the value of the field
+var dedicatedKernelArgs = []struct {
+	arg   string
+	field string
+	isSet func(profile *v1.PerformanceProfile) bool
+}{
+	{
+		arg:   "isolcpus",
+		field: "spec.cpu.isolated",
+		isSet: hasIsolatedCPUs,
+	},
+	{
+		arg:   "irqaffinity",
+		field: "spec.cpu.irqExclude",
+		isSet: func(profile *v1.PerformanceProfile) bool { return profile.Spec.CPU.IRQExclude != nil },
+	},
+	{
+		arg:   "irqaffinity",
+		field: "spec.cpu.irqAffinity",
+		isSet: func(profile *v1.PerformanceProfile) bool { return profile.Spec.CPU.IRQAffinity != nil },
+	},
+	{
+		arg:   "default_hugepagesz",
+		field: "spec.hugepages.defaultHugepagesSize",
+		isSet: func(profile *v1.PerformanceProfile) bool {
+			return profile.Spec.HugePages != nil && profile.Spec.HugePages.DefaultHugePagesSize != nil
+		},
+	},
+	{
+		arg:   "systemd.unified_cgroup_hierarchy",
+		field: "spec.cgroupMode",
+		isSet: func(profile *v1.PerformanceProfile) bool { return profile.Spec.CgroupMode != nil },
+	},
+	{
+		arg:   "clocksource",
+		field: "spec.clockSource",
+		isSet: func(profile *v1.PerformanceProfile) bool { return profile.Spec.ClockSource != nil },
+	},
+	{
+		arg:   "mitigations",
+		field: "spec.mitigations",
+		isSet: func(profile *v1.PerformanceProfile) bool { return profile.Spec.Mitigations != nil },
+	},
+	{
+		arg:   "transparent_hugepage",
+		field: "spec.transparentHugePages",
+		isSet: func(profile *v1.PerformanceProfile) bool { return profile.Spec.TransparentHugePages != nil },
+	},
+	{
+		arg:   "kernelcore",
+		field: "spec.memory.kernelCore",
+		isSet: func(profile *v1.PerformanceProfile) bool {
+			return profile.Spec.Memory != nil && profile.Spec.Memory.KernelCore != nil
+		},
+	},
+	{
+		arg:   "movablecore",
+		field: "spec.memory.movableCore",
+		isSet: func(profile *v1.PerformanceProfile) bool {
+			return profile.Spec.Memory != nil && profile.Spec.Memory.MovableCore != nil
+		},
+	},
+	{
+		arg:   "tsc_early_khz",
+		field: "spec.tscFrequencyKHz",
+		isSet: func(profile *v1.PerformanceProfile) bool { return profile.Spec.TSCFrequencyKHz != nil },
+	},
+	{
+		arg:   "crashkernel",
+		field: "spec.crashKernelMemory",
+		isSet: func(profile *v1.PerformanceProfile) bool { return profile.Spec.CrashKernelMemory != nil },
+	},
+	{
+		arg:   "numa_balancing",
+		field: "spec.numaBalancing",
+		isSet: func(profile *v1.PerformanceProfile) bool { return profile.Spec.NUMABalancing != nil },
+	},
+}
+
+// defaultMaxIsolatedCPUsPercentage is the default maximal percentage of online CPUs the profile can isolate,
+// too few housekeeping CPUs destabilize the node
+const defaultMaxIsolatedCPUsPercentage = 90
+
+// minPidsLimit is the minimal pids limit the container runtime accepts
+const minPidsLimit = int64(20)
+
+// supportedCgroupModes contains cgroup modes and the matching systemd.unified_cgroup_hierarchy kernel argument values
+var supportedCgroupModes = map[v1.CgroupMode]string{
+	v1.CgroupModeV1: "0",
+	v1.CgroupModeV2: "1",
+}
+
+// supportedMitigations contains mitigations modes and the matching mitigations kernel argument values,
+// the auto mode keeps the kernel default and does not need the argument
+var supportedMitigations = map[v1.MitigationsMode]string{
+	v1.MitigationsAuto: "",
+	v1.MitigationsOff:  "off",
+	v1.MitigationsFull: "auto,nosmt",
+}
+
+// supportedTransparentHugePages contains transparent huge pages policies and the matching kernel argument values
+var supportedTransparentHugePages = map[v1.TransparentHugePagesPolicy]string{
+	v1.TransparentHugePagesAlways:  "always",
+	v1.TransparentHugePagesMAdvise: "madvise",
+	v1.TransparentHugePagesNever:   "never",
+}
+
+// supportedNUMABalancing contains automatic NUMA balancing modes and the matching kernel argument values
+var supportedNUMABalancing = map[v1.NUMABalancingMode]string{
+	v1.NUMABalancingEnable:  "enable",
+	v1.NUMABalancingDisable: "disable",
+}
+
+const (
+	isolcpusFlagDomain     = "domain"
+	isolcpusFlagManagedIRQ = "managed_irq"
+	isolcpusFlagNoHZ       = "nohz"
+)
+
+// supportedIsolcpusFlags contains flags of the isolcpus kernel argument
+var supportedIsolcpusFlags = []string{isolcpusFlagDomain, isolcpusFlagManagedIRQ, isolcpusFlagNoHZ}
+
+// kernelMemorySizeRegex matches memory sizes the kernel can parse from boot arguments, either the size
+// with an optional suffix or the percentage of the total memory
+var kernelMemorySizeRegex = regexp.MustCompile(`^([0-9]+[KMGTPE]?|[0-9]{1,2}%|100%)$`)
+
+// crashKernelMemoryRegex matches values of the crashkernel kernel argument, either the memory size
+// or the list of memory ranges with the reserved size, like 1G-4G:160M,4G-:256M, both with an optional offset
+var crashKernelMemoryRegex = regexp.MustCompile(`^([0-9]+[KMGTPE]?|[0-9]+[KMGTPE]?-([0-9]+[KMGTPE]?)?:[0-9]+[KMGTPE]?(,[0-9]+[KMGTPE]?-([0-9]+[KMGTPE]?)?:[0-9]+[KMGTPE]?)*)(@[0-9]+[KMGTPE]?)?$`)
+
+// supportedClockSources contains clock sources that can be passed via the clocksource kernel argument
+var supportedClockSources = []string{"tsc", "hpet", "acpi_pm"}
+
 func validationError(err string) error {
 	return fmt.Errorf("validation error: %s", err)
 }
 
+// validationWarning returns the validation error under the strict validation mode,
+// otherwise it only logs the warning
+func validationWarning(profile *v1.PerformanceProfile, warning string) error {
+	if IsStrictValidation(profile) {
+		return validationError(warning)
+	}
+
+	klog.Warningf("performance profile %q validation warning: %s", profile.Name, warning)
+	return nil
+}
+
 // ValidateParameters validates parameters of the given profile
 func ValidateParameters(profile *v1.PerformanceProfile) error {
 
 	if profile.Spec.CPU == nil {
 		return validationError("you should provide CPU section")
-	} else if profile.Spec.CPU.Isolated == nil {
-		return validationError("you should provide CPU.Isolated section")
+	}
+
+	if profile.Spec.CPU.Reserved != nil {
+		if _, err := components.ParseCPUList(string(*profile.Spec.CPU.Reserved)); err != nil {
+			return validationError(fmt.Sprintf("failed to parse reserved CPUs: %v", err))
+		}
+	}
+
+	if len(profile.Spec.CPU.IsolatedGroups) > 0 {
+		if err := validateIsolatedGroups(profile.Spec.CPU); err != nil {
+			return err
+		}
+	}
+
+	// the real time kernel can be used without CPU isolation, all CPUs remain schedulable in this case
+	if !hasIsolatedCPUs(profile) {
+		if !IsRealTimeKernelEnabled(profile) {
+			return validationError("you should provide CPU.Isolated section")
+		}
+
+		if len(profile.Spec.CPU.IsolcpusFlags) > 0 {
+			return validationError("the isolcpus flags can not be provided without CPU.Isolated section")
+		}
+	} else {
+		// the percentage itself depends on online CPUs of the profile nodes and is validated against the topology
+		if _, err := getMaxIsolatedCPUsPercentage(profile); err != nil {
+			return err
+		}
+
+		if err := validateIsolatedCPU0(profile); err != nil {
+			return err
+		}
+
+		if len(profile.Spec.CPU.IsolcpusFlags) > 0 {
+			if err := validateIsolcpusFlags(profile.Spec.CPU); err != nil {
+				return err
+			}
+		}
+	}
+
+	if profile.Spec.CPU.IRQExclude != nil || profile.Spec.CPU.IRQAffinity != nil {
+		if _, err := GetIRQAffinity(profile); err != nil {
+			return err
+		}
 	}
 
 	if profile.Spec.MachineConfigLabel != nil && len(profile.Spec.MachineConfigLabel) > 1 {
@@ -50,8 +263,38 @@ func ValidateParameters(profile *v1.PerformanceProfile) error {
 		}
 	}
 
+	// huge pages sizes depend on the architecture, so the architecture should be validated first
+	if profile.Spec.Architecture != nil {
+		if err := validateArchitecture(*profile.Spec.Architecture); err != nil {
+			return err
+		}
+	}
+
+	if architectures := GetArchitectures(profile); len(architectures) > 0 {
+		if profile.Spec.Architecture != nil {
+			return validationError(fmt.Sprintf("the architecture can not be provided together with the %s annotation", v1.PerformanceProfileArchitecturesAnnotation))
+		}
+
+		for _, architecture := range architectures {
+			if err := validateArchitecture(architecture); err != nil {
+				return err
+			}
+		}
+	}
+
 	if profile.Spec.HugePages != nil {
-		if err := validateHugepages(profile.Spec.HugePages); err != nil {
+		// the mixed architecture profile applies the same huge pages on nodes of each architecture
+		for _, architecture := range getHugepagesArchitectures(profile) {
+			if err := validateHugepages(profile.Spec.HugePages, architecture); err != nil {
+				return err
+			}
+		}
+
+		if err := validateKernelHugepagesSizes(profile); err != nil {
+			return err
+		}
+
+		if err := validateDefaultHugepagesReservation(profile); err != nil {
 			return err
 		}
 	}
@@ -62,6 +305,94 @@ func ValidateParameters(profile *v1.PerformanceProfile) error {
 		}
 	}
 
+	if profile.Spec.ClockSource != nil {
+		if err := validateClockSource(*profile.Spec.ClockSource); err != nil {
+			return err
+		}
+	}
+
+	if profile.Spec.TSCFrequencyKHz != nil && *profile.Spec.TSCFrequencyKHz <= 0 {
+		return validationError(fmt.Sprintf("the TSC frequency should be greater than 0, got %d kHz", *profile.Spec.TSCFrequencyKHz))
+	}
+
+	if profile.Spec.Memory != nil {
+		if err := validateMemory(profile.Spec.Memory); err != nil {
+			return err
+		}
+	}
+
+	if profile.Spec.CrashKernelMemory != nil && !crashKernelMemoryRegex.MatchString(*profile.Spec.CrashKernelMemory) {
+		return validationError(fmt.Sprintf("the crash kernel memory %q should be the memory size, like 256M, or the list of memory ranges, like 1G-4G:160M,4G-:256M", *profile.Spec.CrashKernelMemory))
+	}
+
+	if profile.Spec.CgroupMode != nil {
+		if err := validateCgroupMode(*profile.Spec.CgroupMode); err != nil {
+			return err
+		}
+	}
+
+	if profile.Spec.Mitigations != nil {
+		if err := validateMitigations(profile); err != nil {
+			return err
+		}
+	}
+
+	if profile.Spec.TransparentHugePages != nil {
+		if err := validateTransparentHugePages(*profile.Spec.TransparentHugePages); err != nil {
+			return err
+		}
+
+		if err := validateHugepagesTransparentHugePages(profile); err != nil {
+			return err
+		}
+	}
+
+	if err := validateUnitDescriptionPrefix(profile); err != nil {
+		return err
+	}
+
+	if err := validateNodeLabel(profile); err != nil {
+		return err
+	}
+
+	if profile.Spec.NUMABalancing != nil {
+		if err := validateNUMABalancing(*profile.Spec.NUMABalancing); err != nil {
+			return err
+		}
+	}
+
+	if profile.Spec.WorkloadHints != nil {
+		if err := validateWorkloadHints(profile.Spec.WorkloadHints); err != nil {
+			return err
+		}
+	}
+
+	if profile.Spec.ContainerRuntime != nil {
+		if err := validateContainerRuntime(profile.Spec.ContainerRuntime); err != nil {
+			return err
+		}
+	}
+
+	if profile.Spec.TuningUnits != nil {
+		if err := validateTuningUnits(profile.Spec.TuningUnits); err != nil {
+			return err
+		}
+	}
+
+	if err := validateAdditionalKernelArgs(profile); err != nil {
+		return err
+	}
+
+	if err := validateIRQAffinityReserved(profile); err != nil {
+		return err
+	}
+
+	if profile.Spec.PriorityClass != nil {
+		if err := validatePriorityClass(profile.Spec.PriorityClass); err != nil {
+			return err
+		}
+	}
+
 	// TODO add validation for MachineConfigLabels and MachineConfigPoolSelector if they are not set
 	// by checking if a MCP with our default values exists
 
@@ -97,79 +428,1092 @@ func getDefaultLabel(profile *v1.PerformanceProfile) map[string]string {
 	return labels
 }
 
-// IsPaused returns whether or not a performance profile's reconcile loop is paused
-func IsPaused(profile *v1.PerformanceProfile) bool {
+// GetArchitectures returns architectures of the mixed architecture cluster the profile targets,
+// it returns nil when the profile does not have the architectures annotation
+func GetArchitectures(profile *v1.PerformanceProfile) []string {
+	var architectures []string
+	for _, architecture := range strings.Split(profile.Annotations[v1.PerformanceProfileArchitecturesAnnotation], ",") {
+		if architecture = strings.TrimSpace(architecture); architecture != "" {
+			architectures = append(architectures, architecture)
+		}
+	}
+	return architectures
+}
 
-	if profile.Annotations == nil {
-		return false
+// GetArchitectureMachineConfigLabel returns the machine config label of the architecture specific machine config
+func GetArchitectureMachineConfigLabel(profile *v1.PerformanceProfile, architecture string) map[string]string {
+	key, value := components.GetFirstKeyAndValue(GetMachineConfigLabel(profile))
+	return map[string]string{key: fmt.Sprintf("%s-%s", value, architecture)}
+}
+
+// getHugepagesArchitectures returns architectures of nodes the profile huge pages are allocated on,
+// either all architectures of the mixed architecture cluster or the single profile architecture
+func getHugepagesArchitectures(profile *v1.PerformanceProfile) []string {
+	if architectures := GetArchitectures(profile); len(architectures) > 0 {
+		return architectures
 	}
+	return []string{GetArchitecture(profile)}
+}
 
-	isPaused, ok := profile.Annotations[v1.PerformanceProfilePauseAnnotation]
-	if ok && isPaused == "true" {
-		return true
+// GetArchitecture returns the architecture from the CR or the default one
+func GetArchitecture(profile *v1.PerformanceProfile) string {
+	if profile.Spec.Architecture != nil {
+		return *profile.Spec.Architecture
 	}
 
-	return false
+	return components.ArchitectureAMD64
 }
 
-func validatePageDuplication(page *v1.HugePage, pages []v1.HugePage) error {
-	for _, p := range pages {
-		if page.Size != p.Size {
-			continue
+// IsolatedCount returns the number of CPUs the profile makes available for exclusive pinning without SMT siblings
+// that go offline once SMT is disabled, the nil provider or the unknown topology count all isolated CPUs
+func IsolatedCount(profile *v1.PerformanceProfile, provider TopologyProvider) (int, error) {
+	isolated, err := GetIsolatedCPUs(profile)
+	if err != nil {
+		return 0, err
+	}
+
+	if provider != nil && isSMTDisabled(profile) {
+		siblings, err := provider.GetCoreSiblings(profile)
+		if err != nil {
+			return 0, err
 		}
+		isolated = isolated.Difference(getSMTOfflineCPUs(siblings))
+	}
+	return isolated.Size(), nil
+}
 
-		if page.Node != nil && p.Node == nil {
-			continue
+// IsRealTimeKernelEnabled returns whether or not the real time kernel should be installed,
+// the explicit RealTimeKernel field overrides the RealTime workload hint
+func IsRealTimeKernelEnabled(profile *v1.PerformanceProfile) bool {
+	if profile.Spec.RealTimeKernel != nil && profile.Spec.RealTimeKernel.Enabled != nil {
+		return *profile.Spec.RealTimeKernel.Enabled
+	}
+
+	return profile.Spec.WorkloadHints != nil &&
+		profile.Spec.WorkloadHints.RealTime != nil &&
+		*profile.Spec.WorkloadHints.RealTime
+}
+
+// IsCPUQuotaAnnotationAllowed returns whether or not pods of the profile runtime class can disable the CFS quota
+// with the CRI-O annotation
+func IsCPUQuotaAnnotationAllowed(profile *v1.PerformanceProfile) bool {
+	return profile.Spec.ContainerRuntime != nil &&
+		profile.Spec.ContainerRuntime.AllowCPUQuotaAnnotation != nil &&
+		*profile.Spec.ContainerRuntime.AllowCPUQuotaAnnotation
+}
+
+// GetWorkloadHintsKernelArgs returns kernel arguments derived from the profile workload hints
+func GetWorkloadHintsKernelArgs(profile *v1.PerformanceProfile) []string {
+	hints := profile.Spec.WorkloadHints
+	if hints == nil {
+		return nil
+	}
+
+	realTime := hints.RealTime != nil && *hints.RealTime
+	highPowerConsumption := hints.HighPowerConsumption != nil && *hints.HighPowerConsumption
+	perPodPowerManagement := hints.PerPodPowerManagement != nil && *hints.PerPodPowerManagement
+
+	var args []string
+	if realTime {
+		args = append(args, "skew_tick=1")
+		if hasIsolatedCPUs(profile) {
+			// malformed isolated CPUs are rejected by the validation, keep the original value in such case
+			isolated := ""
+			if profile.Spec.CPU.Isolated != nil {
+				isolated = string(*profile.Spec.CPU.Isolated)
+			}
+			if cpus, err := GetIsolatedCPUs(profile); err == nil {
+				isolated = cpus.String()
+			}
+			args = append(args, fmt.Sprintf("nohz_full=%s", isolated))
 		}
+	}
 
-		if page.Node == nil && p.Node != nil {
-			continue
+	if highPowerConsumption {
+		args = append(args, "processor.max_cstate=1", "intel_idle.max_cstate=0")
+		// polling gives the lowest wake up latency for the real time workloads
+		if realTime {
+			args = append(args, "idle=poll")
 		}
+	}
 
-		if page.Node == nil && p.Node == nil {
-			return validationError(fmt.Sprintf("the page with the size %q and without the specified NUMA node, has duplication", page.Size))
+	if perPodPowerManagement {
+		args = append(args, "intel_pstate=passive")
+	}
+	return args
+}
+
+// GetCPUPartitioningKernelArgs returns the default CPU partitioning kernel arguments without ones the profile replaces,
+// e.g. the per pod power management hint runs the intel_pstate driver in the passive mode instead of disabling it
+func GetCPUPartitioningKernelArgs(profile *v1.PerformanceProfile) []string {
+	return OverrideKernelArgs(components.CPUPartitioningKernelArgs, getDefaultKernelArgsOverrides(profile))
+}
+
+// GetRealTimeKernelArgs returns the default real time kernel arguments without ones the profile replaces,
+// e.g. the TSC frequency marks the TSC reliable instead of disabling its watchdog
+func GetRealTimeKernelArgs(profile *v1.PerformanceProfile) []string {
+	return OverrideKernelArgs(components.RealTimeKernelArgs, getDefaultKernelArgsOverrides(profile))
+}
+
+// GetTSCKernelArgs returns kernel arguments that set the TSC frequency,
+// it returns nil when the TSC frequency is not specified
+func GetTSCKernelArgs(profile *v1.PerformanceProfile) []string {
+	if profile.Spec.TSCFrequencyKHz == nil {
+		return nil
+	}
+	return []string{"tsc=reliable", fmt.Sprintf("tsc_early_khz=%d", *profile.Spec.TSCFrequencyKHz)}
+}
+
+// getDefaultKernelArgsOverrides returns kernel arguments derived from the profile that the tuned profile puts
+// next to its default kernel arguments, the default kernel arguments with the same key are dropped, so the kernel
+// command line carries only the profile value, the same way the additional kernel arguments replace the workload hints ones
+func getDefaultKernelArgsOverrides(profile *v1.PerformanceProfile) []string {
+	overrides := GetTSCKernelArgs(profile)
+	overrides = append(overrides, GetWorkloadHintsKernelArgs(profile)...)
+	return append(overrides, GetAdditionalKernelArgs(profile)...)
+}
+
+// GetAdditionalKernelArgs returns the user specified kernel arguments, the real time kernel additional arguments
+// follow the additional kernel arguments when the real time kernel is enabled
+func GetAdditionalKernelArgs(profile *v1.PerformanceProfile) []string {
+	args := append([]string{}, profile.Spec.AdditionalKernelArgs...)
+	if IsRealTimeKernelEnabled(profile) && profile.Spec.RealTimeKernel != nil {
+		args = append(args, profile.Spec.RealTimeKernel.AdditionalArgs...)
+	}
+	return args
+}
+
+// OverrideKernelArgs returns base kernel arguments without ones that have the same key as one of the overriding
+// kernel arguments, the key is the part of the argument before the first '='
+func OverrideKernelArgs(base []string, overrides []string) []string {
+	keys := map[string]bool{}
+	for _, arg := range overrides {
+		keys[getKernelArgKey(arg)] = true
+	}
+
+	var args []string
+	for _, arg := range base {
+		if !keys[getKernelArgKey(arg)] {
+			args = append(args, arg)
 		}
+	}
+	return args
+}
 
-		if *page.Node == *p.Node {
-			return validationError(fmt.Sprintf("the page with the size %q and with specified NUMA node %d, has duplication", page.Size, *page.Node))
+// RemovedKernelArgs returns old kernel arguments whose key none of the new kernel arguments has,
+// arguments that only change the value are not reported, the new value replaces the old one
+func RemovedKernelArgs(old []string, new []string) []string {
+	keys := map[string]bool{}
+	for _, arg := range new {
+		keys[getKernelArgKey(arg)] = true
+	}
+
+	var removed []string
+	for _, arg := range old {
+		if !keys[getKernelArgKey(arg)] {
+			removed = append(removed, arg)
 		}
 	}
+	return removed
+}
 
-	return nil
+func getKernelArgKey(arg string) string {
+	return strings.SplitN(arg, "=", 2)[0]
 }
 
-func validateHugepages(hugepages *v1.HugePages) error {
-	// validate that default hugepages size has correct value, currently we support only 2M and 1G(x86_64 architecture)
-	if hugepages.DefaultHugePagesSize != nil {
-		defaultSize := *hugepages.DefaultHugePagesSize
-		if defaultSize != hugepagesSize1G && defaultSize != hugepagesSize2M {
-			return validationError(fmt.Sprintf("hugepages default size should be equal to %q or %q", hugepagesSize1G, hugepagesSize2M))
-		}
+// GetCgroupKernelArg returns the kernel argument that selects the profile cgroup mode,
+// it returns an empty string when the cgroup mode is not specified
+func GetCgroupKernelArg(profile *v1.PerformanceProfile) string {
+	if profile.Spec.CgroupMode == nil {
+		return ""
 	}
 
-	for i, page := range hugepages.Pages {
-		if page.Size != hugepagesSize1G && page.Size != hugepagesSize2M {
-			return validationError(fmt.Sprintf("the page size should be equal to %q or %q", hugepagesSize1G, hugepagesSize2M))
+	value, ok := supportedCgroupModes[*profile.Spec.CgroupMode]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("systemd.unified_cgroup_hierarchy=%s", value)
+}
+
+// GetMitigationsKernelArg returns the kernel argument that selects the profile mitigations mode,
+// it returns an empty string when the kernel default mitigations should be used
+func GetMitigationsKernelArg(profile *v1.PerformanceProfile) string {
+	if profile.Spec.Mitigations == nil {
+		return ""
+	}
+
+	value := supportedMitigations[*profile.Spec.Mitigations]
+	if value == "" {
+		return ""
+	}
+	return fmt.Sprintf("mitigations=%s", value)
+}
+
+// GetTransparentHugePagesPolicy returns the transparent huge pages policy value used by the kernel,
+// it returns an empty string when the profile does not select the policy
+func GetTransparentHugePagesPolicy(profile *v1.PerformanceProfile) string {
+	if profile.Spec.TransparentHugePages == nil {
+		return ""
+	}
+	return supportedTransparentHugePages[*profile.Spec.TransparentHugePages]
+}
+
+// GetTransparentHugePagesKernelArg returns the kernel argument that selects the profile transparent huge pages policy,
+// it returns an empty string when the profile does not select the policy
+func GetTransparentHugePagesKernelArg(profile *v1.PerformanceProfile) string {
+	policy := GetTransparentHugePagesPolicy(profile)
+	if policy == "" {
+		return ""
+	}
+	return fmt.Sprintf("transparent_hugepage=%s", policy)
+}
+
+// GetNUMABalancingKernelArg returns the numa_balancing kernel argument,
+// it returns an empty string when the profile does not select the automatic NUMA balancing mode
+func GetNUMABalancingKernelArg(profile *v1.PerformanceProfile) string {
+	if profile.Spec.NUMABalancing == nil {
+		return ""
+	}
+	return fmt.Sprintf("numa_balancing=%s", supportedNUMABalancing[*profile.Spec.NUMABalancing])
+}
+
+// GetCrashKernelKernelArg returns the crashkernel kernel argument, the argument is empty when the crash kernel memory is not set
+func GetCrashKernelKernelArg(profile *v1.PerformanceProfile) string {
+	if profile.Spec.CrashKernelMemory == nil {
+		return ""
+	}
+	return fmt.Sprintf("crashkernel=%s", *profile.Spec.CrashKernelMemory)
+}
+
+// GetMemoryKernelArgs returns the kernelcore and movablecore kernel arguments
+func GetMemoryKernelArgs(profile *v1.PerformanceProfile) []string {
+	if profile.Spec.Memory == nil {
+		return nil
+	}
+
+	var args []string
+	if profile.Spec.Memory.KernelCore != nil {
+		args = append(args, fmt.Sprintf("kernelcore=%s", *profile.Spec.Memory.KernelCore))
+	}
+	if profile.Spec.Memory.MovableCore != nil {
+		args = append(args, fmt.Sprintf("movablecore=%s", *profile.Spec.Memory.MovableCore))
+	}
+	return args
+}
+
+// GetIsolcpusFlags returns flags of the isolcpus kernel argument, the explicit IsolcpusFlags field
+// overrides flags derived from the BalanceIsolated field
+func GetIsolcpusFlags(profile *v1.PerformanceProfile) []string {
+	if len(profile.Spec.CPU.IsolcpusFlags) > 0 {
+		return profile.Spec.CPU.IsolcpusFlags
+	}
+
+	if profile.Spec.CPU.BalanceIsolated != nil && !*profile.Spec.CPU.BalanceIsolated {
+		return []string{isolcpusFlagDomain, isolcpusFlagManagedIRQ}
+	}
+	return []string{isolcpusFlagManagedIRQ}
+}
+
+// GetIRQAffinity returns the list of reserved CPUs that should handle device interrupts, either the explicit
+// IRQ affinity or reserved CPUs without the excluded ones, it returns an empty string when the profile
+// does not set the IRQ affinity
+func GetIRQAffinity(profile *v1.PerformanceProfile) (string, error) {
+	if profile.Spec.CPU == nil || (profile.Spec.CPU.IRQExclude == nil && profile.Spec.CPU.IRQAffinity == nil) {
+		return "", nil
+	}
+
+	if profile.Spec.CPU.IRQExclude != nil && profile.Spec.CPU.IRQAffinity != nil {
+		return "", validationError("you should provide only one of CPU.IRQExclude and CPU.IRQAffinity")
+	}
+
+	if profile.Spec.CPU.Reserved == nil {
+		return "", validationError("you should provide CPU.Reserved section when CPU.IRQExclude or CPU.IRQAffinity is set")
+	}
+
+	reserved, err := components.ParseCPUList(string(*profile.Spec.CPU.Reserved))
+	if err != nil {
+		return "", validationError(fmt.Sprintf("failed to parse reserved CPUs: %v", err))
+	}
+
+	var irqAffinity cpuset.CPUSet
+	if profile.Spec.CPU.IRQAffinity != nil {
+		irqAffinity, err = parseIRQAffinity(*profile.Spec.CPU.IRQAffinity)
+		if err != nil {
+			return "", validationError(fmt.Sprintf("failed to parse IRQ affinity CPUs: %v", err))
 		}
 
-		if err := validatePageDuplication(&page, hugepages.Pages[i+1:]); err != nil {
-			return err
+		if !irqAffinity.IsSubsetOf(reserved) {
+			return "", validationError(fmt.Sprintf("IRQ affinity CPUs %q should be a subset of reserved CPUs %q", irqAffinity, reserved))
+		}
+	} else {
+		excluded, err := components.ParseCPUList(string(*profile.Spec.CPU.IRQExclude))
+		if err != nil {
+			return "", validationError(fmt.Sprintf("failed to parse IRQ excluded CPUs: %v", err))
+		}
+
+		if !excluded.IsSubsetOf(reserved) {
+			return "", validationError(fmt.Sprintf("IRQ excluded CPUs %q should be a subset of reserved CPUs %q", excluded, reserved))
 		}
+		irqAffinity = reserved.Difference(excluded)
 	}
 
-	return nil
+	if irqAffinity.IsEmpty() {
+		return "", validationError("at least one reserved CPU should handle device interrupts")
+	}
+	return irqAffinity.String(), nil
 }
 
-func validateNUMA(numa *v1.NUMA) error {
-	// validate NUMA topology policy matches allowed values
-	if numa.TopologyPolicy != nil {
-		policy := *numa.TopologyPolicy
-		if policy != kubeletconfigv1beta1.NoneTopologyManagerPolicy &&
-			policy != kubeletconfigv1beta1.BestEffortTopologyManagerPolicy &&
-			policy != kubeletconfigv1beta1.RestrictedTopologyManagerPolicy &&
-			policy != kubeletconfigv1beta1.SingleNumaNodeTopologyManager {
-			return validationError("unrecognized value for topologyPolicy")
+// parseIRQAffinity parses the IRQ affinity given either as the CPU list or as the CPU mask with the 0x prefix
+func parseIRQAffinity(irqAffinity string) (cpuset.CPUSet, error) {
+	if strings.HasPrefix(strings.TrimSpace(irqAffinity), "0x") {
+		return components.ParseCPUMask(irqAffinity)
+	}
+	return components.ParseCPUList(irqAffinity)
+}
+
+// Canonicalize returns a copy of the profile in the canonical form, CPU lists are normalized,
+// huge pages sizes are upper-cased and huge pages are sorted by the size and the NUMA node,
+// so semantically equal profiles have identical canonical forms
+func Canonicalize(profile *v1.PerformanceProfile) (*v1.PerformanceProfile, error) {
+	canonical := profile.DeepCopy()
+
+	if cpu := canonical.Spec.CPU; cpu != nil {
+		for _, cpus := range []*v1.CPUSet{cpu.Reserved, cpu.Isolated, cpu.IRQExclude} {
+			if cpus == nil {
+				continue
+			}
+
+			set, err := components.ParseCPUList(string(*cpus))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse CPU list %q: %v", *cpus, err)
+			}
+			*cpus = v1.CPUSet(set.String())
+		}
+
+		// the IRQ affinity mask and the list of the same CPUs are equal
+		if cpu.IRQAffinity != nil {
+			set, err := parseIRQAffinity(*cpu.IRQAffinity)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse IRQ affinity %q: %v", *cpu.IRQAffinity, err)
+			}
+			*cpu.IRQAffinity = set.String()
+		}
+
+		for i := range cpu.IsolatedGroups {
+			set, err := components.ParseCPUList(string(cpu.IsolatedGroups[i].CPUs))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse CPU list %q: %v", cpu.IsolatedGroups[i].CPUs, err)
+			}
+			cpu.IsolatedGroups[i].CPUs = v1.CPUSet(set.String())
 		}
 	}
-	return nil
+
+	if hugepages := canonical.Spec.HugePages; hugepages != nil {
+		if hugepages.DefaultHugePagesSize != nil {
+			defaultSize := v1.HugePageSize(strings.ToUpper(string(*hugepages.DefaultHugePagesSize)))
+			hugepages.DefaultHugePagesSize = &defaultSize
+		}
+
+		for i := range hugepages.Pages {
+			hugepages.Pages[i].Size = v1.HugePageSize(strings.ToUpper(string(hugepages.Pages[i].Size)))
+		}
+
+		sort.SliceStable(hugepages.Pages, func(i, j int) bool {
+			left, right := hugepages.Pages[i], hugepages.Pages[j]
+			if left.Size != right.Size {
+				return hugepagesSizeKilobytes[left.Size] < hugepagesSizeKilobytes[right.Size]
+			}
+			// pages without the NUMA node go first
+			if left.Node == nil || right.Node == nil {
+				return left.Node == nil && right.Node != nil
+			}
+			return *left.Node < *right.Node
+		})
+	}
+
+	return canonical, nil
+}
+
+// Summarize returns the concise human readable description of the performance profile,
+// e.g. "4 isolated CPUs (4-7), 4 reserved CPUs (0-3), 4x1G hugepages, RT kernel"
+func Summarize(profile *v1.PerformanceProfile) string {
+	var parts []string
+
+	if profile.Spec.CPU != nil {
+		if len(profile.Spec.CPU.IsolatedGroups) > 0 {
+			if isolated, err := GetIsolatedCPUs(profile); err == nil {
+				parts = append(parts, summarizeCPUs(v1.CPUSet(isolated.String()), "isolated"))
+			}
+		} else if profile.Spec.CPU.Isolated != nil {
+			parts = append(parts, summarizeCPUs(*profile.Spec.CPU.Isolated, "isolated"))
+		}
+		if profile.Spec.CPU.Reserved != nil {
+			parts = append(parts, summarizeCPUs(*profile.Spec.CPU.Reserved, "reserved"))
+		}
+	}
+
+	if profile.Spec.HugePages != nil {
+		for _, page := range profile.Spec.HugePages.Pages {
+			hugepages := fmt.Sprintf("%dx%s hugepages", page.Count, page.Size)
+			if page.Node != nil {
+				hugepages += fmt.Sprintf(" on NUMA node %d", *page.Node)
+			}
+			parts = append(parts, hugepages)
+		}
+	}
+
+	if IsRealTimeKernelEnabled(profile) {
+		parts = append(parts, "RT kernel")
+	}
+
+	if len(parts) == 0 {
+		return "no performance tuning"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func summarizeCPUs(cpus v1.CPUSet, kind string) string {
+	set, err := components.ParseCPUList(string(cpus))
+	if err != nil {
+		return fmt.Sprintf("%s CPUs (%s)", kind, cpus)
+	}
+	return fmt.Sprintf("%d %s CPUs (%s)", set.Size(), kind, cpus)
+}
+
+func isSMTDisabled(profile *v1.PerformanceProfile) bool {
+	// the full mitigations mode disables SMT on CPUs affected by SMT related vulnerabilities,
+	// we assume the worst case
+	if profile.Spec.Mitigations != nil && *profile.Spec.Mitigations == v1.MitigationsFull {
+		return true
+	}
+
+	for _, arg := range GetAdditionalKernelArgs(profile) {
+		if arg == kernelArgNoSMT {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPaused returns whether or not a performance profile's reconcile loop is paused
+func IsPaused(profile *v1.PerformanceProfile) bool {
+
+	if profile.Annotations == nil {
+		return false
+	}
+
+	isPaused, ok := profile.Annotations[v1.PerformanceProfilePauseAnnotation]
+	if ok && isPaused == "true" {
+		return true
+	}
+
+	return false
+}
+
+// IsStrictValidation returns whether or not validation warnings of the performance profile should be treated as errors
+func IsStrictValidation(profile *v1.PerformanceProfile) bool {
+
+	if profile.Annotations == nil {
+		return false
+	}
+
+	isStrict, ok := profile.Annotations[v1.PerformanceProfileStrictValidationAnnotation]
+	if ok && isStrict == "true" {
+		return true
+	}
+
+	return false
+}
+
+// GetUnitDescriptionPrefix returns the prefix of systemd units descriptions specified via the profile annotation,
+// it returns an empty string when the annotation is not set
+func GetUnitDescriptionPrefix(profile *v1.PerformanceProfile) string {
+	return strings.TrimSpace(profile.Annotations[v1.PerformanceProfileUnitDescriptionPrefixAnnotation])
+}
+
+// GetTuningUnitType returns the systemd service type of the tuning units, "oneshot" when not specified
+func GetTuningUnitType(profile *v1.PerformanceProfile) v1.TuningUnitType {
+	if profile.Spec.TuningUnits == nil || profile.Spec.TuningUnits.Type == nil {
+		return v1.TuningUnitTypeOneshot
+	}
+	return *profile.Spec.TuningUnits.Type
+}
+
+// IsTuningUnitRemainAfterExit returns whether or not the tuning units stay active after the tuning process exits,
+// by default only "oneshot" units do
+func IsTuningUnitRemainAfterExit(profile *v1.PerformanceProfile) bool {
+	if profile.Spec.TuningUnits != nil && profile.Spec.TuningUnits.RemainAfterExit != nil {
+		return *profile.Spec.TuningUnits.RemainAfterExit
+	}
+	return GetTuningUnitType(profile) == v1.TuningUnitTypeOneshot
+}
+
+// IsNodeLabelEnabled returns whether or not the operator should label the profile nodes with the profile name
+func IsNodeLabelEnabled(profile *v1.PerformanceProfile) bool {
+	return profile.Annotations[v1.PerformanceProfileNodeLabelAnnotation] == "true"
+}
+
+// IsDriftDetectionOnly returns whether or not the operator should only report drifted performance profile objects
+// instead of updating them
+func IsDriftDetectionOnly(profile *v1.PerformanceProfile) bool {
+
+	if profile.Annotations == nil {
+		return false
+	}
+
+	isDriftDetectionOnly, ok := profile.Annotations[v1.PerformanceProfileDriftDetectionOnlyAnnotation]
+	if ok && isDriftDetectionOnly == "true" {
+		return true
+	}
+
+	return false
+}
+
+func validatePageDuplication(page *v1.HugePage, pages []v1.HugePage) error {
+	for _, p := range pages {
+		if page.Size != p.Size {
+			continue
+		}
+
+		if page.Node != nil && p.Node == nil {
+			continue
+		}
+
+		if page.Node == nil && p.Node != nil {
+			continue
+		}
+
+		if page.Node == nil && p.Node == nil {
+			return validationError(fmt.Sprintf("the page with the size %q and without the specified NUMA node, has duplication", page.Size))
+		}
+
+		if *page.Node == *p.Node {
+			return validationError(fmt.Sprintf("the page with the size %q and with specified NUMA node %d, has duplication", page.Size, *page.Node))
+		}
+	}
+
+	return nil
+}
+
+func validatePageCount(page *v1.HugePage) error {
+	if page.Count <= 0 {
+		return validationError(fmt.Sprintf("the page with the size %q should have positive count, got %d", page.Size, page.Count))
+	}
+
+	maxCount := maxHugepagesKilobytes / hugepagesSizeKilobytes[page.Size]
+	if int64(page.Count) > maxCount {
+		return validationError(fmt.Sprintf("the page with the size %q count %d exceeds the maximum count %d", page.Size, page.Count, maxCount))
+	}
+
+	return nil
+}
+
+// ParseHugepagesCountLimits parses the comma separated list of the huge page size and the maximum count pairs,
+// e.g. "2M=1000000,1G=256", the empty value does not limit huge pages
+func ParseHugepagesCountLimits(value string) (map[v1.HugePageSize]int32, error) {
+	limits := map[v1.HugePageSize]int32{}
+	if strings.TrimSpace(value) == "" {
+		return limits, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("the huge pages count limit %q should have the <size>=<count> format", pair)
+		}
+
+		size := v1.HugePageSize(parts[0])
+		if _, ok := hugepagesSizeKilobytes[size]; !ok {
+			return nil, fmt.Errorf("the huge pages count limit %q has the unsupported size %q", pair, size)
+		}
+
+		count, err := strconv.ParseInt(parts[1], 10, 32)
+		if err != nil || count <= 0 {
+			return nil, fmt.Errorf("the huge pages count limit %q should have the positive count", pair)
+		}
+		limits[size] = int32(count)
+	}
+	return limits, nil
+}
+
+// ValidateHugepagesCountLimits verifies that the profile does not request more huge pages of each size
+// than the configured limit, huge pages take the memory from the node, so too many of them cause the node OOM,
+// NUMA specific pages of the same size are limited by their total
+func ValidateHugepagesCountLimits(profile *v1.PerformanceProfile, limits map[v1.HugePageSize]int32) error {
+	if profile.Spec.HugePages == nil || len(limits) == 0 {
+		return nil
+	}
+
+	totals := map[v1.HugePageSize]int64{}
+	for _, page := range profile.Spec.HugePages.Pages {
+		totals[page.Size] += int64(page.Count)
+	}
+
+	for _, page := range profile.Spec.HugePages.Pages {
+		limit, ok := limits[page.Size]
+		if !ok {
+			continue
+		}
+
+		if totals[page.Size] > int64(limit) {
+			return validationError(fmt.Sprintf("the profile requests %d huge pages with the size %q, that exceeds the configured maximum count %d", totals[page.Size], page.Size, limit))
+		}
+	}
+	return nil
+}
+
+func validateHugepages(hugepages *v1.HugePages, architecture string) error {
+	// validate that default hugepages size has correct value, the supported sizes depend on the architecture
+	sizes := components.HugepagesSizes[architecture]
+	if hugepages.DefaultHugePagesSize != nil {
+		if !isHugepagesSizeSupported(*hugepages.DefaultHugePagesSize, sizes) {
+			return validationError(fmt.Sprintf("hugepages default size should be equal to %s on the %s architecture", quoteHugepagesSizes(sizes), architecture))
+		}
+	}
+
+	for i, page := range hugepages.Pages {
+		if !isHugepagesSizeSupported(page.Size, sizes) {
+			return validationError(fmt.Sprintf("the page size should be equal to %s on the %s architecture", quoteHugepagesSizes(sizes), architecture))
+		}
+
+		if err := validatePageCount(&page); err != nil {
+			return err
+		}
+
+		if err := validatePageDuplication(&page, hugepages.Pages[i+1:]); err != nil {
+			return err
+		}
+	}
+
+	if err := validatePageReservationPath(hugepages.Pages); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validatePageReservationPath validates that each page size is reserved either at boot via kernel arguments
+// or at runtime on specific NUMA nodes, the kernel boot reservation is spread over NUMA nodes,
+// so the runtime reservation of the same size makes the total of the size on each node hard to predict
+func validatePageReservationPath(pages []v1.HugePage) error {
+	bootSizes := map[v1.HugePageSize]bool{}
+	for _, page := range pages {
+		if page.Node == nil {
+			bootSizes[page.Size] = true
+		}
+	}
+
+	for _, page := range pages {
+		if page.Node != nil && bootSizes[page.Size] {
+			return validationError(fmt.Sprintf("the page size %q is reserved both at boot and at runtime on the NUMA node %d, specify the NUMA node for all pages of the size or for none of them", page.Size, *page.Node))
+		}
+	}
+	return nil
+}
+
+func isHugepagesSizeSupported(size v1.HugePageSize, sizes []string) bool {
+	for _, supported := range sizes {
+		if string(size) == supported {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteHugepagesSizes(sizes []string) string {
+	quoted := make([]string, 0, len(sizes))
+	for _, size := range sizes {
+		quoted = append(quoted, strconv.Quote(size))
+	}
+	return strings.Join(quoted, " or ")
+}
+
+// validateKernelHugepagesSizes validates that the selected kernel supports all declared huge pages sizes,
+// the real time kernel supports fewer sizes than the default kernel that validateHugepages checks
+func validateKernelHugepagesSizes(profile *v1.PerformanceProfile) error {
+	if !IsRealTimeKernelEnabled(profile) {
+		return nil
+	}
+
+	for _, architecture := range getHugepagesArchitectures(profile) {
+		if err := validateArchitectureKernelHugepagesSizes(profile, architecture); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateArchitectureKernelHugepagesSizes(profile *v1.PerformanceProfile, architecture string) error {
+	sizes := components.RealTimeKernelHugepagesSizes[architecture]
+	if profile.Spec.HugePages.DefaultHugePagesSize != nil && !isHugepagesSizeSupported(*profile.Spec.HugePages.DefaultHugePagesSize, sizes) {
+		return validationError(fmt.Sprintf("the real time kernel does not support the default huge pages size %q on the %s architecture", *profile.Spec.HugePages.DefaultHugePagesSize, architecture))
+	}
+
+	// huge pages on the specified NUMA node are allocated once the node booted, the allocation of other sizes
+	// is not reliable, but it can succeed, so the strict validation only rejects it
+	runtimeSizes := components.RealTimeKernelRuntimeHugepagesSizes[architecture]
+	for _, page := range profile.Spec.HugePages.Pages {
+		if !isHugepagesSizeSupported(page.Size, sizes) {
+			return validationError(fmt.Sprintf("the real time kernel does not support the huge pages size %q on the %s architecture", page.Size, architecture))
+		}
+
+		if page.Node != nil && !isHugepagesSizeSupported(page.Size, runtimeSizes) {
+			warning := fmt.Sprintf("the allocation of %q huge pages on the specified NUMA node %d is not reliable with the real time kernel on the %s architecture, remove the node field to allocate huge pages via kernel boot arguments", page.Size, *page.Node, architecture)
+			if err := validationWarning(profile, warning); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateDefaultHugepagesReservation warns when the default huge pages size is set without any huge pages,
+// the default size alone does not reserve huge pages at boot
+func validateDefaultHugepagesReservation(profile *v1.PerformanceProfile) error {
+	if profile.Spec.HugePages.DefaultHugePagesSize == nil || len(profile.Spec.HugePages.Pages) > 0 {
+		return nil
+	}
+
+	return validationWarning(profile, fmt.Sprintf("the default huge pages size %q is set, but no huge pages are reserved at boot, declare at least one huge page to reserve huge pages", *profile.Spec.HugePages.DefaultHugePagesSize))
+}
+
+func validateNUMA(numa *v1.NUMA) error {
+	// validate NUMA topology policy matches allowed values
+	if numa.TopologyPolicy != nil {
+		policy := *numa.TopologyPolicy
+		if policy != kubeletconfigv1beta1.NoneTopologyManagerPolicy &&
+			policy != kubeletconfigv1beta1.BestEffortTopologyManagerPolicy &&
+			policy != kubeletconfigv1beta1.RestrictedTopologyManagerPolicy &&
+			policy != kubeletconfigv1beta1.SingleNumaNodeTopologyManager {
+			return validationError("unrecognized value for topologyPolicy")
+		}
+	}
+	return nil
+}
+
+func validatePriorityClass(priorityClass *v1.PriorityClass) error {
+	// validate that the priority value does not collide with system priority classes
+	if priorityClass.Value != nil && *priorityClass.Value > maxUserDefinablePriority {
+		return validationError(fmt.Sprintf("priority class value should be less than or equal to %d, higher values are reserved for system priority classes", maxUserDefinablePriority))
+	}
+	return nil
+}
+
+func validateArchitecture(architecture string) error {
+	if _, ok := components.HugepagesSizes[architecture]; !ok {
+		return validationError(fmt.Sprintf("the architecture should be equal to %q, %q or %q", components.ArchitectureAMD64, components.ArchitectureARM64, components.ArchitecturePPC64LE))
+	}
+	return nil
+}
+
+// validateIsolatedCPUsPercentage verifies that the profile does not isolate more than the allowed percentage
+// of the given number of online CPUs
+func validateIsolatedCPUsPercentage(profile *v1.PerformanceProfile, onlineCount int) error {
+	maxPercentage, err := getMaxIsolatedCPUsPercentage(profile)
+	if err != nil {
+		return err
+	}
+
+	isolated, err := GetIsolatedCPUs(profile)
+	if err != nil {
+		return validationError(err.Error())
+	}
+
+	if isolated.Size()*100 > onlineCount*maxPercentage {
+		return validationError(fmt.Sprintf("the profile isolates %d out of %d online CPUs, that exceeds %d%% of online CPUs", isolated.Size(), onlineCount, maxPercentage))
+	}
+	return nil
+}
+
+// getMaxIsolatedCPUsPercentage returns the maximal percentage of online CPUs the profile can isolate
+func getMaxIsolatedCPUsPercentage(profile *v1.PerformanceProfile) (int, error) {
+	value, ok := profile.Annotations[v1.PerformanceProfileMaxIsolatedCPUsPercentageAnnotation]
+	if !ok {
+		return defaultMaxIsolatedCPUsPercentage, nil
+	}
+
+	percentage, err := strconv.Atoi(value)
+	if err != nil || percentage <= 0 || percentage > 100 {
+		return 0, validationError(fmt.Sprintf("the %s annotation value %q should be an integer in the range 1-100", v1.PerformanceProfileMaxIsolatedCPUsPercentageAnnotation, value))
+	}
+	return percentage, nil
+}
+
+// validateIsolatedCPU0 warns about the isolated CPU0, because many kernel tasks are pinned to it
+func validateIsolatedCPU0(profile *v1.PerformanceProfile) error {
+	isolated, err := GetIsolatedCPUs(profile)
+	if err != nil {
+		return validationError(err.Error())
+	}
+
+	if isolated.Contains(0) {
+		return validationWarning(profile, "the isolated CPUs include CPU0, that runs many pinned kernel tasks and can destabilize the node, move CPU0 to the reserved CPUs")
+	}
+	return nil
+}
+
+func validateIsolcpusFlags(cpu *v1.CPU) error {
+	seen := map[string]bool{}
+	for _, flag := range cpu.IsolcpusFlags {
+		if !isIsolcpusFlagSupported(flag) {
+			return validationError(fmt.Sprintf("the isolcpus flag %q is not supported, supported flags are %v", flag, supportedIsolcpusFlags))
+		}
+		if seen[flag] {
+			return validationError(fmt.Sprintf("the isolcpus flag %q is specified more than once", flag))
+		}
+		seen[flag] = true
+	}
+
+	// the domain flag removes isolated CPUs from the scheduler load balancing
+	if cpu.BalanceIsolated != nil && *cpu.BalanceIsolated != !seen[isolcpusFlagDomain] {
+		return validationError(fmt.Sprintf("the isolcpus flags %v contradict the balanceIsolated field value %t", cpu.IsolcpusFlags, *cpu.BalanceIsolated))
+	}
+	return nil
+}
+
+func isIsolcpusFlagSupported(flag string) bool {
+	for _, supported := range supportedIsolcpusFlags {
+		if flag == supported {
+			return true
+		}
+	}
+	return false
+}
+
+func validateMemory(memory *v1.Memory) error {
+	if memory.KernelCore != nil && !kernelMemorySizeRegex.MatchString(*memory.KernelCore) {
+		return validationError(fmt.Sprintf("the kernel core memory %q should be the memory size, like 4G, or the percentage of the total memory, like 10%%", *memory.KernelCore))
+	}
+	if memory.MovableCore != nil && !kernelMemorySizeRegex.MatchString(*memory.MovableCore) {
+		return validationError(fmt.Sprintf("the movable core memory %q should be the memory size, like 4G, or the percentage of the total memory, like 10%%", *memory.MovableCore))
+	}
+	return nil
+}
+
+func validateWorkloadHints(hints *v1.WorkloadHints) error {
+	if hints.HighPowerConsumption != nil && *hints.HighPowerConsumption &&
+		hints.PerPodPowerManagement != nil && *hints.PerPodPowerManagement {
+		return validationError("the HighPowerConsumption and the PerPodPowerManagement workload hints can not be enabled together")
+	}
+	return nil
+}
+
+func validateContainerRuntime(containerRuntime *v1.ContainerRuntime) error {
+	if containerRuntime.PidsLimit != nil && *containerRuntime.PidsLimit < minPidsLimit {
+		return validationError(fmt.Sprintf("the container runtime pids limit should be greater than or equal to %d", minPidsLimit))
+	}
+	return nil
+}
+
+// conflictingKernelArgs contains pairs of kernel arguments that configure the same kernel subsystem,
+// the argument without the value matches all values of the argument. Contradicting arguments request opposite
+// behaviour and the kernel applies only the last one, redundant arguments have no effect next to the other one.
+var conflictingKernelArgs = []struct {
+	arg       string
+	conflicts []string
+	redundant bool
+	reason    string
+}{
+	{
+		arg:       "intel_pstate=disable",
+		conflicts: []string{"intel_pstate=passive"},
+		reason:    "the intel_pstate driver can not run in the passive mode once it is disabled",
+	},
+	{
+		arg:       "idle=poll",
+		conflicts: []string{"processor.max_cstate", "cpuidle.off"},
+		redundant: true,
+		reason:    "the polling replaces the idle states the argument configures",
+	},
+}
+
+func validateTuningUnits(tuningUnits *v1.TuningUnits) error {
+	if tuningUnits.Type != nil && *tuningUnits.Type != v1.TuningUnitTypeOneshot && *tuningUnits.Type != v1.TuningUnitTypeSimple {
+		return validationError(fmt.Sprintf("the tuning units type should be equal to %q or %q", v1.TuningUnitTypeOneshot, v1.TuningUnitTypeSimple))
+	}
+
+	if tuningUnits.Type != nil && *tuningUnits.Type == v1.TuningUnitTypeSimple &&
+		tuningUnits.RemainAfterExit != nil && *tuningUnits.RemainAfterExit {
+		return validationError(fmt.Sprintf("the tuning units of the %q type can not remain after exit, the unit would stay active after the tuning process fails", v1.TuningUnitTypeSimple))
+	}
+	return nil
+}
+
+func validateAdditionalKernelArgs(profile *v1.PerformanceProfile) error {
+	for _, arg := range GetAdditionalKernelArgs(profile) {
+		name := strings.SplitN(arg, "=", 2)[0]
+		for _, dedicated := range dedicatedKernelArgs {
+			if name == dedicated.arg && dedicated.isSet(profile) {
+				return validationError(fmt.Sprintf("the additional kernel argument %q contradicts the %s field, remove one of them", arg, dedicated.field))
+			}
+		}
+	}
+	return validateContradictingKernelArgs(profile)
+}
+
+// validateContradictingKernelArgs verifies that the kernel command line the operator generates does not carry
+// contradicting kernel arguments and warns about the additional kernel arguments that are redundant,
+// the operator puts redundant arguments on purpose, e.g. the high power consumption hint limits idle states
+// next to the polling
+func validateContradictingKernelArgs(profile *v1.PerformanceProfile) error {
+	additionalArgs := GetAdditionalKernelArgs(profile)
+	args := append([]string{}, additionalArgs...)
+	args = append(args, GetCPUPartitioningKernelArgs(profile)...)
+	args = append(args, GetRealTimeKernelArgs(profile)...)
+	args = append(args, OverrideKernelArgs(GetWorkloadHintsKernelArgs(profile), additionalArgs)...)
+
+	for i, arg := range args {
+		for _, other := range args[i+1:] {
+			for _, conflicting := range conflictingKernelArgs {
+				if !(matchesKernelArg(arg, conflicting.arg) && matchesAnyKernelArg(other, conflicting.conflicts)) &&
+					!(matchesKernelArg(other, conflicting.arg) && matchesAnyKernelArg(arg, conflicting.conflicts)) {
+					continue
+				}
+
+				if !conflicting.redundant {
+					return validationError(fmt.Sprintf("the kernel arguments %q and %q contradict each other, %s, remove one of them", arg, other, conflicting.reason))
+				}
+
+				// additional kernel arguments precede the generated ones
+				if i < len(additionalArgs) {
+					warning := fmt.Sprintf("the kernel arguments %q and %q are redundant, %s", arg, other, conflicting.reason)
+					if err := validationWarning(profile, warning); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// matchesKernelArg returns true when the kernel argument is equal to the pattern,
+// the pattern without the value matches the argument with any value
+func matchesKernelArg(arg string, pattern string) bool {
+	if strings.Contains(pattern, "=") {
+		return arg == pattern
+	}
+	return getKernelArgKey(arg) == pattern
+}
+
+func matchesAnyKernelArg(arg string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matchesKernelArg(arg, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateIRQAffinityReserved verifies that the irqaffinity additional kernel argument targets only
+// CPUs reserved for the kubelet and the system, otherwise device interrupts are handled by isolated CPUs.
+// The IRQ affinity derived from the IRQExclude and IRQAffinity fields is always the subset of reserved CPUs.
+func validateIRQAffinityReserved(profile *v1.PerformanceProfile) error {
+	if profile.Spec.CPU.Reserved == nil {
+		return nil
+	}
+
+	var irqAffinity string
+	for _, arg := range GetAdditionalKernelArgs(profile) {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) == 2 && parts[0] == "irqaffinity" {
+			// the kernel uses the last value of the repeated argument
+			irqAffinity = parts[1]
+		}
+	}
+	if irqAffinity == "" {
+		return nil
+	}
+
+	irqCPUs, err := components.ParseCPUList(irqAffinity)
+	if err != nil {
+		return validationError(fmt.Sprintf("failed to parse the irqaffinity kernel argument CPUs: %v", err))
+	}
+
+	reserved, err := components.ParseCPUList(string(*profile.Spec.CPU.Reserved))
+	if err != nil {
+		return validationError(fmt.Sprintf("failed to parse reserved CPUs: %v", err))
+	}
+
+	if !irqCPUs.IsSubsetOf(reserved) {
+		return validationError(fmt.Sprintf("the irqaffinity kernel argument CPUs %q should be a subset of reserved CPUs %q, otherwise device interrupts are handled by CPUs %q that are not reserved", irqCPUs, reserved, irqCPUs.Difference(reserved)))
+	}
+	return nil
+}
+
+func validateCgroupMode(cgroupMode v1.CgroupMode) error {
+	if _, ok := supportedCgroupModes[cgroupMode]; !ok {
+		return validationError(fmt.Sprintf("the cgroup mode %q is not supported, supported cgroup modes are %q and %q", cgroupMode, v1.CgroupModeV1, v1.CgroupModeV2))
+	}
+	return nil
+}
+
+func validateMitigations(profile *v1.PerformanceProfile) error {
+	mitigations := *profile.Spec.Mitigations
+	if _, ok := supportedMitigations[mitigations]; !ok {
+		return validationError(fmt.Sprintf("the mitigations mode %q is not supported, supported modes are %q, %q and %q", mitigations, v1.MitigationsAuto, v1.MitigationsOff, v1.MitigationsFull))
+	}
+
+	if mitigations == v1.MitigationsOff {
+		return validationWarning(profile, "the mitigations mode \"Off\" disables all CPU vulnerabilities mitigations, workloads on the nodes can read the memory of other workloads and of the kernel via Spectre and Meltdown like attacks, use it only on isolated nodes that run trusted workloads")
+	}
+	return nil
+}
+
+func validateTransparentHugePages(policy v1.TransparentHugePagesPolicy) error {
+	if _, ok := supportedTransparentHugePages[policy]; !ok {
+		return validationError(fmt.Sprintf("the transparent huge pages policy %q is not supported, supported policies are %q, %q and %q", policy, v1.TransparentHugePagesAlways, v1.TransparentHugePagesMAdvise, v1.TransparentHugePagesNever))
+	}
+	return nil
+}
+
+// validateUnitDescriptionPrefix verifies that the prefix fits into the single line of the systemd unit
+func validateUnitDescriptionPrefix(profile *v1.PerformanceProfile) error {
+	prefix := GetUnitDescriptionPrefix(profile)
+	for _, r := range prefix {
+		if unicode.IsControl(r) {
+			return validationError(fmt.Sprintf("the %s annotation value %q should not contain control characters", v1.PerformanceProfileUnitDescriptionPrefixAnnotation, prefix))
+		}
+	}
+	return nil
+}
+
+// validateNodeLabel verifies that the profile name can be used as the node label value
+func validateNodeLabel(profile *v1.PerformanceProfile) error {
+	if !IsNodeLabelEnabled(profile) {
+		return nil
+	}
+
+	if errs := validation.IsValidLabelValue(profile.Name); len(errs) > 0 {
+		return validationError(fmt.Sprintf("the profile name %q can not be used as the %s node label value requested via the %s annotation: %s", profile.Name, v1.PerformanceProfileNodeLabel, v1.PerformanceProfileNodeLabelAnnotation, strings.Join(errs, ", ")))
+	}
+	return nil
+}
+
+func validateNUMABalancing(mode v1.NUMABalancingMode) error {
+	if _, ok := supportedNUMABalancing[mode]; !ok {
+		return validationError(fmt.Sprintf("the NUMA balancing mode %q is not supported, supported modes are %q and %q", mode, v1.NUMABalancingEnable, v1.NUMABalancingDisable))
+	}
+	return nil
+}
+
+// validateHugepagesTransparentHugePages warns about explicit huge pages together with transparent huge pages
+// enabled for all memory regions, the kernel can not use memory reserved for huge pages to back transparent
+// huge pages, so the rest of the memory is under the higher pressure
+func validateHugepagesTransparentHugePages(profile *v1.PerformanceProfile) error {
+	if *profile.Spec.TransparentHugePages != v1.TransparentHugePagesAlways ||
+		profile.Spec.HugePages == nil || len(profile.Spec.HugePages.Pages) == 0 {
+		return nil
+	}
+
+	return validationWarning(profile, fmt.Sprintf("the profile allocates huge pages and enables transparent huge pages for all memory regions, that can cause the unexpected memory pressure, set the transparent huge pages policy to %q or %q", v1.TransparentHugePagesMAdvise, v1.TransparentHugePagesNever))
+}
+
+func validateClockSource(clockSource string) error {
+	for _, supported := range supportedClockSources {
+		if clockSource == supported {
+			return nil
+		}
+	}
+	return validationError(fmt.Sprintf("the clock source %q is not supported, supported clock sources are %v", clockSource, supportedClockSources))
 }
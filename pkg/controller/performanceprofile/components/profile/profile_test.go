@@ -2,12 +2,15 @@ package profile
 
 import (
 	"fmt"
+	"strings"
 
 	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpuset"
 	"k8s.io/utils/pointer"
 
 	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 
 	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
@@ -30,11 +33,20 @@ var _ = Describe("PerformanceProfile", func() {
 		It("should have CPU fields populated", func() {
 			Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred(), "should pass with populated CPU fields")
 			profile.Spec.CPU.Isolated = nil
+			profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
 			Expect(ValidateParameters(profile)).Should(HaveOccurred(), "should fail with missing CPU Isolated field")
 			profile.Spec.CPU = nil
 			Expect(ValidateParameters(profile)).Should(HaveOccurred(), "should fail with missing CPU")
 		})
 
+		It("should allow the real time kernel without CPU isolation", func() {
+			profile.Spec.CPU.Isolated = nil
+			Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred(), "should pass with the real time kernel and missing CPU Isolated field")
+
+			profile.Spec.CPU.IsolcpusFlags = []string{"managed_irq"}
+			Expect(ValidateParameters(profile)).Should(HaveOccurred(), "should fail with isolcpus flags and missing CPU Isolated field")
+		})
+
 		It("should have 0 or 1 MachineConfigLabels", func() {
 			Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred(), "should pass with 1 MachineConfigLabel")
 
@@ -88,6 +100,771 @@ var _ = Describe("PerformanceProfile", func() {
 			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("the page size should be equal to %q or %q", hugepagesSize1G, hugepagesSize2M)))
 		})
 
+		table.DescribeTable("should validate the default hugepages size according to the architecture",
+			func(architecture string, size v1.HugePageSize, valid bool) {
+				// the real time kernel supports fewer sizes, it is validated separately
+				profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+				profile.Spec.Architecture = pointer.StringPtr(architecture)
+				profile.Spec.HugePages.DefaultHugePagesSize = &size
+				profile.Spec.HugePages.Pages = []v1.HugePage{{Size: size, Count: 4}}
+
+				err := ValidateParameters(profile)
+				if valid {
+					Expect(err).ToNot(HaveOccurred())
+					return
+				}
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("hugepages default size should be equal to %s on the %s architecture", quoteHugepagesSizes(components.HugepagesSizes[architecture]), architecture)))
+			},
+			table.Entry("amd64 with 1G", components.ArchitectureAMD64, v1.HugePageSize(hugepagesSize1G), true),
+			table.Entry("amd64 with 2M", components.ArchitectureAMD64, v1.HugePageSize(hugepagesSize2M), true),
+			table.Entry("amd64 with 16M", components.ArchitectureAMD64, v1.HugePageSize(components.HugepagesSize16M), false),
+			table.Entry("arm64 with 1G", components.ArchitectureARM64, v1.HugePageSize(hugepagesSize1G), true),
+			table.Entry("arm64 with 16G", components.ArchitectureARM64, v1.HugePageSize(components.HugepagesSize16G), false),
+			table.Entry("ppc64le with 16M", components.ArchitecturePPC64LE, v1.HugePageSize(components.HugepagesSize16M), true),
+			table.Entry("ppc64le with 16G", components.ArchitecturePPC64LE, v1.HugePageSize(components.HugepagesSize16G), true),
+			table.Entry("ppc64le with 1G", components.ArchitecturePPC64LE, v1.HugePageSize(hugepagesSize1G), false),
+			table.Entry("ppc64le with 2M", components.ArchitecturePPC64LE, v1.HugePageSize(hugepagesSize2M), false),
+		)
+
+		It("should validate architectures of the mixed architecture cluster", func() {
+			profile.Annotations = map[string]string{v1.PerformanceProfileArchitecturesAnnotation: "amd64, arm64"}
+			Expect(GetArchitectures(profile)).To(Equal([]string{components.ArchitectureAMD64, components.ArchitectureARM64}))
+			Expect(ValidateParameters(profile)).To(Succeed())
+
+			profile.Spec.Architecture = pointer.StringPtr(components.ArchitectureAMD64)
+			err := ValidateParameters(profile)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the architecture can not be provided together with the"))
+
+			profile.Spec.Architecture = nil
+			profile.Annotations[v1.PerformanceProfileArchitecturesAnnotation] = "amd64,s390x"
+			err = ValidateParameters(profile)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the architecture should be equal to"))
+		})
+
+		It("should validate huge pages sizes against each architecture of the mixed architecture cluster", func() {
+			profile.Annotations = map[string]string{v1.PerformanceProfileArchitecturesAnnotation: "amd64,arm64"}
+			Expect(ValidateParameters(profile)).To(Succeed())
+
+			// the POWER nodes do not support 1G huge pages
+			profile.Annotations[v1.PerformanceProfileArchitecturesAnnotation] = "amd64,ppc64le"
+			err := ValidateParameters(profile)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("on the %s architecture", components.ArchitecturePPC64LE)))
+
+			// the x86 nodes do not support the POWER default huge pages size
+			profile.Spec.HugePages.Pages = nil
+			defaultSize := v1.HugePageSize(components.HugepagesSize16M)
+			profile.Spec.HugePages.DefaultHugePagesSize = &defaultSize
+			err = ValidateParameters(profile)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("hugepages default size should be equal to %q or %q on the amd64 architecture", hugepagesSize1G, hugepagesSize2M)))
+		})
+
+		It("should reject hugepages allocation with the page size of another architecture", func() {
+			profile.Spec.Architecture = pointer.StringPtr(components.ArchitecturePPC64LE)
+			defaultSize := v1.HugePageSize(components.HugepagesSize16G)
+			profile.Spec.HugePages.DefaultHugePagesSize = &defaultSize
+
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("the page size should be equal to %q or %q on the ppc64le architecture", components.HugepagesSize16G, components.HugepagesSize16M)))
+		})
+
+		table.DescribeTable("should validate the huge pages sizes according to the kernel",
+			func(architecture string, realTimeKernel bool, size v1.HugePageSize, node *int32, expectedError string) {
+				profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(realTimeKernel)
+				profile.Spec.Architecture = pointer.StringPtr(architecture)
+				profile.Spec.HugePages.DefaultHugePagesSize = nil
+				profile.Spec.HugePages.Pages = []v1.HugePage{{Size: size, Count: 4, Node: node}}
+
+				err := ValidateParameters(profile)
+				if expectedError == "" {
+					Expect(err).ToNot(HaveOccurred())
+					return
+				}
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(expectedError))
+			},
+			table.Entry("amd64 real time kernel with 1G", components.ArchitectureAMD64, true, v1.HugePageSize(hugepagesSize1G), nil, ""),
+			table.Entry("amd64 real time kernel with 2M", components.ArchitectureAMD64, true, v1.HugePageSize(hugepagesSize2M), nil, ""),
+			table.Entry("amd64 real time kernel with 1G on the NUMA node", components.ArchitectureAMD64, true, v1.HugePageSize(hugepagesSize1G), pointer.Int32Ptr(0), ""),
+			table.Entry("amd64 real time kernel with 2M on the NUMA node", components.ArchitectureAMD64, true, v1.HugePageSize(hugepagesSize2M), pointer.Int32Ptr(0), ""),
+			table.Entry("amd64 default kernel with 1G on the NUMA node", components.ArchitectureAMD64, false, v1.HugePageSize(hugepagesSize1G), pointer.Int32Ptr(0), ""),
+			table.Entry("ppc64le default kernel with 16M", components.ArchitecturePPC64LE, false, v1.HugePageSize(components.HugepagesSize16M), nil, ""),
+			table.Entry("ppc64le real time kernel with 16M", components.ArchitecturePPC64LE, true, v1.HugePageSize(components.HugepagesSize16M), nil, `the real time kernel does not support the huge pages size "16M" on the ppc64le architecture`),
+			table.Entry("ppc64le real time kernel with 16G", components.ArchitecturePPC64LE, true, v1.HugePageSize(components.HugepagesSize16G), nil, `the real time kernel does not support the huge pages size "16G" on the ppc64le architecture`),
+		)
+
+		It("should reject the default huge pages size unsupported by the real time kernel", func() {
+			profile.Spec.Architecture = pointer.StringPtr(components.ArchitecturePPC64LE)
+			defaultSize := v1.HugePageSize(components.HugepagesSize16M)
+			profile.Spec.HugePages.DefaultHugePagesSize = &defaultSize
+			profile.Spec.HugePages.Pages = nil
+
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`the real time kernel does not support the default huge pages size "16M" on the ppc64le architecture`))
+		})
+
+		It("should limit the count of 16G huge pages", func() {
+			profile.Spec.Architecture = pointer.StringPtr(components.ArchitecturePPC64LE)
+			profile.Spec.HugePages.DefaultHugePagesSize = nil
+			profile.Spec.HugePages.Pages = []v1.HugePage{{Size: components.HugepagesSize16G, Count: 1025}}
+
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("count 1025 exceeds the maximum count 1024"))
+		})
+
+		table.DescribeTable("should reject hugepages allocation with invalid count",
+			func(size v1.HugePageSize, count int32, expected string) {
+				profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, v1.HugePage{
+					Count: count,
+					Node:  pointer.Int32Ptr(0),
+					Size:  size,
+				})
+				err := ValidateParameters(profile)
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(expected))
+			},
+			table.Entry("zero", v1.HugePageSize(hugepagesSize2M), int32(0), "should have positive count, got 0"),
+			table.Entry("negative", v1.HugePageSize(hugepagesSize2M), int32(-4), "should have positive count, got -4"),
+			table.Entry("excessive 1G", v1.HugePageSize(hugepagesSize1G), int32(16385), "count 16385 exceeds the maximum count 16384"),
+			table.Entry("excessive 2M", v1.HugePageSize(hugepagesSize2M), int32(8388609), "count 8388609 exceeds the maximum count 8388608"),
+		)
+
+		It("should reject priority class value reserved for system priority classes", func() {
+			profile.Spec.PriorityClass = &v1.PriorityClass{
+				Enabled: pointer.BoolPtr(true),
+				Value:   pointer.Int32Ptr(maxUserDefinablePriority),
+			}
+			Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred(), "should pass with the highest user definable value")
+
+			profile.Spec.PriorityClass.Value = pointer.Int32Ptr(maxUserDefinablePriority + 1)
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("reserved for system priority classes"))
+		})
+
+		It("should reject unknown architecture", func() {
+			profile.Spec.Architecture = pointer.StringPtr(components.ArchitectureARM64)
+			Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred(), "should pass with arm64 architecture")
+
+			profile.Spec.Architecture = pointer.StringPtr("s390x")
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the architecture should be equal to"))
+		})
+
+		It("should reject the invalid maximal isolated CPUs percentage", func() {
+			profile.Annotations = map[string]string{v1.PerformanceProfileMaxIsolatedCPUsPercentageAnnotation: "101"}
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("should be an integer in the range 1-100"))
+		})
+
+		table.DescribeTable("should drop base kernel arguments overridden by the user",
+			func(base []string, overrides []string, expected []string) {
+				Expect(OverrideKernelArgs(base, overrides)).To(Equal(expected))
+			},
+			table.Entry("with the same key and different value", []string{"processor.max_cstate=1", "intel_idle.max_cstate=0"}, []string{"processor.max_cstate=0"}, []string{"intel_idle.max_cstate=0"}),
+			table.Entry("with the flag argument", []string{"nosmt", "skew_tick=1"}, []string{"nosmt"}, []string{"skew_tick=1"}),
+			table.Entry("with the value overriding the flag argument", []string{"idle=poll"}, []string{"idle"}, nil),
+			table.Entry("without overrides", []string{"skew_tick=1"}, nil, []string{"skew_tick=1"}),
+			table.Entry("with unrelated overrides", []string{"skew_tick=1"}, []string{"skew=1"}, []string{"skew_tick=1"}),
+		)
+
+		table.DescribeTable("should report old kernel arguments missing from the new kernel arguments",
+			func(old []string, new []string, expected []string) {
+				Expect(RemovedKernelArgs(old, new)).To(Equal(expected))
+			},
+			table.Entry("with the same arguments", []string{"nohz=on", "nosoftlockup"}, []string{"nosoftlockup", "nohz=on"}, nil),
+			table.Entry("with the changed value", []string{"nohz_full=4-7"}, []string{"nohz_full=5-7"}, nil),
+			table.Entry("with removed arguments", []string{"nohz=on", "nosoftlockup", "skew_tick=1"}, []string{"nohz=on"}, []string{"nosoftlockup", "skew_tick=1"}),
+			table.Entry("with the flag replaced by the value", []string{"idle"}, []string{"idle=poll"}, nil),
+			table.Entry("with added arguments only", []string{"nohz=on"}, []string{"nohz=on", "nosmt"}, nil),
+			table.Entry("with all arguments removed", []string{"nohz=on"}, nil, []string{"nohz=on"}),
+		)
+
+		It("should reject the unit description prefix with control characters", func() {
+			profile.Annotations = map[string]string{v1.PerformanceProfileUnitDescriptionPrefixAnnotation: " [perf-test] "}
+			Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred())
+			Expect(GetUnitDescriptionPrefix(profile)).To(Equal("[perf-test]"))
+
+			profile.Annotations[v1.PerformanceProfileUnitDescriptionPrefixAnnotation] = "perf\ntest"
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("should not contain control characters"))
+		})
+
+		It("should reject the profile name that is not a valid node label value when node labeling is enabled", func() {
+			profile.Annotations = map[string]string{v1.PerformanceProfileNodeLabelAnnotation: "true"}
+			Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred())
+
+			profile.Name = strings.Repeat("a", 64)
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("can not be used as the performance.openshift.io/profile node label value"))
+		})
+
+		It("should reject IRQ excluded CPUs that are not reserved", func() {
+			irqExclude := v1.CPUSet("3-4")
+			profile.Spec.CPU.IRQExclude = &irqExclude
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("should be a subset of reserved CPUs"))
+
+			irqExclude = v1.CPUSet("0-3")
+			err = ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("at least one reserved CPU should handle device interrupts"))
+		})
+
+		table.DescribeTable("should validate the IRQ affinity against reserved CPUs",
+			func(irqAffinity string, expectedError string) {
+				profile.Spec.CPU.IRQAffinity = &irqAffinity
+				err := ValidateParameters(profile)
+				if expectedError == "" {
+					Expect(err).ToNot(HaveOccurred())
+					return
+				}
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(expectedError))
+			},
+			table.Entry("with the reserved CPUs list", "0-1", ""),
+			table.Entry("with the reserved CPUs mask", "0x3", ""),
+			table.Entry("with the isolated CPUs list", "3-4", `IRQ affinity CPUs "3-4" should be a subset of reserved CPUs "0-3"`),
+			table.Entry("with the isolated CPUs mask", "0x18", `IRQ affinity CPUs "3-4" should be a subset of reserved CPUs "0-3"`),
+			table.Entry("with the malformed CPUs list", "a-b", "failed to parse IRQ affinity CPUs"),
+			table.Entry("with the malformed CPUs mask", "0xzz", "failed to parse IRQ affinity CPUs"),
+			table.Entry("with the empty CPUs mask", "0x0", "at least one reserved CPU should handle device interrupts"),
+		)
+
+		It("should reject the IRQ affinity together with IRQ excluded CPUs", func() {
+			irqAffinity := "0-1"
+			irqExclude := v1.CPUSet("3")
+			profile.Spec.CPU.IRQAffinity = &irqAffinity
+			profile.Spec.CPU.IRQExclude = &irqExclude
+			err := ValidateParameters(profile)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("you should provide only one of CPU.IRQExclude and CPU.IRQAffinity"))
+		})
+
+		It("should reject the irqaffinity additional kernel argument together with the IRQ affinity", func() {
+			irqAffinity := "0-1"
+			profile.Spec.CPU.IRQAffinity = &irqAffinity
+			profile.Spec.AdditionalKernelArgs = []string{"irqaffinity=0"}
+			err := ValidateParameters(profile)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`the additional kernel argument "irqaffinity=0" contradicts the spec.cpu.irqAffinity field`))
+		})
+
+		table.DescribeTable("should validate the irqaffinity kernel argument against reserved CPUs",
+			func(args []string, expectedError string) {
+				profile.Spec.AdditionalKernelArgs = args
+				err := ValidateParameters(profile)
+				if expectedError == "" {
+					Expect(err).ShouldNot(HaveOccurred())
+					return
+				}
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(expectedError))
+			},
+			table.Entry("with reserved CPUs", []string{"irqaffinity=0-2"}, ""),
+			table.Entry("with all reserved CPUs", []string{"irqaffinity=0-3"}, ""),
+			table.Entry("with isolated CPUs", []string{"irqaffinity=2-5"}, `the irqaffinity kernel argument CPUs "2-5" should be a subset of reserved CPUs "0-3", otherwise device interrupts are handled by CPUs "4-5" that are not reserved`),
+			table.Entry("with the last repeated argument on isolated CPUs", []string{"irqaffinity=0", "irqaffinity=7"}, `CPUs "7" that are not reserved`),
+			table.Entry("with invalid CPUs", []string{"irqaffinity=a-b"}, "failed to parse the irqaffinity kernel argument CPUs"),
+		)
+
+		table.DescribeTable("should reject additional kernel arguments that contradict dedicated fields",
+			func(arg string, field string, setField func(profile *v1.PerformanceProfile)) {
+				profile.Spec.AdditionalKernelArgs = []string{"nmi_watchdog=0", arg}
+				profile.Spec.HugePages.DefaultHugePagesSize = nil
+				Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred(), "should pass when the %s field is not set", field)
+
+				setField(profile)
+				err := ValidateParameters(profile)
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("the additional kernel argument %q contradicts the %s field", arg, field)))
+			},
+			table.Entry("irqaffinity", "irqaffinity=0", "spec.cpu.irqExclude", func(profile *v1.PerformanceProfile) {
+				irqExclude := v1.CPUSet("1")
+				profile.Spec.CPU.IRQExclude = &irqExclude
+			}),
+			table.Entry("default_hugepagesz", "default_hugepagesz=2M", "spec.hugepages.defaultHugepagesSize", func(profile *v1.PerformanceProfile) {
+				size := v1.HugePageSize(hugepagesSize1G)
+				profile.Spec.HugePages.DefaultHugePagesSize = &size
+			}),
+			table.Entry("clocksource", "clocksource=hpet", "spec.clockSource", func(profile *v1.PerformanceProfile) {
+				profile.Spec.ClockSource = pointer.StringPtr("tsc")
+			}),
+			table.Entry("mitigations", "mitigations=off", "spec.mitigations", func(profile *v1.PerformanceProfile) {
+				mitigations := v1.MitigationsAuto
+				profile.Spec.Mitigations = &mitigations
+			}),
+			table.Entry("transparent_hugepage", "transparent_hugepage=always", "spec.transparentHugePages", func(profile *v1.PerformanceProfile) {
+				policy := v1.TransparentHugePagesNever
+				profile.Spec.TransparentHugePages = &policy
+			}),
+			table.Entry("kernelcore", "kernelcore=4G", "spec.memory.kernelCore", func(profile *v1.PerformanceProfile) {
+				profile.Spec.Memory = &v1.Memory{KernelCore: pointer.StringPtr("8G")}
+			}),
+			table.Entry("movablecore", "movablecore=10%", "spec.memory.movableCore", func(profile *v1.PerformanceProfile) {
+				profile.Spec.Memory = &v1.Memory{MovableCore: pointer.StringPtr("20%")}
+			}),
+			table.Entry("tsc_early_khz", "tsc_early_khz=2000000", "spec.tscFrequencyKHz", func(profile *v1.PerformanceProfile) {
+				profile.Spec.TSCFrequencyKHz = pointer.Int32Ptr(2100000)
+			}),
+			table.Entry("crashkernel", "crashkernel=512M", "spec.crashKernelMemory", func(profile *v1.PerformanceProfile) {
+				profile.Spec.CrashKernelMemory = pointer.StringPtr("256M")
+			}),
+			table.Entry("numa_balancing", "numa_balancing=enable", "spec.numaBalancing", func(profile *v1.PerformanceProfile) {
+				mode := v1.NUMABalancingDisable
+				profile.Spec.NUMABalancing = &mode
+			}),
+		)
+
+		table.DescribeTable("should validate conflicting power management kernel arguments",
+			func(args []string, hints *v1.WorkloadHints, strict bool, expectedError string) {
+				profile.Spec.AdditionalKernelArgs = args
+				profile.Spec.WorkloadHints = hints
+				if strict {
+					profile.Annotations = map[string]string{v1.PerformanceProfileStrictValidationAnnotation: "true"}
+				}
+				err := ValidateParameters(profile)
+				if expectedError == "" {
+					Expect(err).ToNot(HaveOccurred())
+					return
+				}
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(expectedError))
+			},
+			table.Entry("disabled and passive intel_pstate", []string{"intel_pstate=disable", "intel_pstate=passive"}, nil, false,
+				`the kernel arguments "intel_pstate=disable" and "intel_pstate=passive" contradict each other`),
+			table.Entry("per pod power management with the default intel_pstate", nil,
+				&v1.WorkloadHints{PerPodPowerManagement: pointer.BoolPtr(true)}, true, ""),
+			table.Entry("passive intel_pstate with the default intel_pstate", []string{"intel_pstate=passive"}, nil, true, ""),
+			table.Entry("disabled intel_pstate overriding the per pod power management", []string{"intel_pstate=disable"},
+				&v1.WorkloadHints{PerPodPowerManagement: pointer.BoolPtr(true)}, true, ""),
+			table.Entry("idle polling with the maximum C-state", []string{"processor.max_cstate=1", "idle=poll"}, nil, false, ""),
+			table.Entry("idle polling with the maximum C-state under the strict validation", []string{"processor.max_cstate=1", "idle=poll"}, nil, true,
+				`the kernel arguments "processor.max_cstate=1" and "idle=poll" are redundant`),
+			table.Entry("idle polling with the disabled cpuidle under the strict validation", []string{"idle=poll", "cpuidle.off=1"}, nil, true,
+				`the kernel arguments "idle=poll" and "cpuidle.off=1" are redundant`),
+			table.Entry("idle polling with the high power consumption under the strict validation", []string{"idle=poll"},
+				&v1.WorkloadHints{HighPowerConsumption: pointer.BoolPtr(true)}, true,
+				`the kernel arguments "idle=poll" and "processor.max_cstate=1" are redundant`),
+			table.Entry("real time high power consumption under the strict validation", nil,
+				&v1.WorkloadHints{RealTime: pointer.BoolPtr(true), HighPowerConsumption: pointer.BoolPtr(true)}, true, ""),
+			table.Entry("active and passive intel_pstate", []string{"intel_pstate=active", "intel_pstate=passive"}, nil, true, ""),
+			table.Entry("idle polling with the intel_idle maximum C-state", []string{"idle=poll", "intel_idle.max_cstate=0"}, nil, true, ""),
+		)
+
+		It("should validate the real time kernel additional arguments only when the real time kernel is enabled", func() {
+			profile.Spec.RealTimeKernel.AdditionalArgs = []string{"isolcpus=1-3"}
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`the additional kernel argument "isolcpus=1-3" contradicts the spec.cpu.isolated field`))
+
+			profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+			Expect(ValidateParameters(profile)).ToNot(HaveOccurred())
+		})
+
+		It("should append the real time kernel additional arguments only when the real time kernel is enabled", func() {
+			profile.Spec.AdditionalKernelArgs = []string{"nmi_watchdog=0"}
+			profile.Spec.RealTimeKernel.AdditionalArgs = []string{"rcupdate.rcu_normal_after_boot=0"}
+			Expect(GetAdditionalKernelArgs(profile)).To(Equal([]string{"nmi_watchdog=0", "rcupdate.rcu_normal_after_boot=0"}))
+			// the profile spec should stay untouched
+			Expect(profile.Spec.AdditionalKernelArgs).To(Equal([]string{"nmi_watchdog=0"}))
+
+			profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+			Expect(GetAdditionalKernelArgs(profile)).To(Equal([]string{"nmi_watchdog=0"}))
+		})
+
+		It("should reject isolcpus additional kernel argument", func() {
+			profile.Spec.AdditionalKernelArgs = []string{"isolcpus=1-3"}
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("contradicts the spec.cpu.isolated field"))
+		})
+
+		table.DescribeTable("should validate the tuning units service options",
+			func(unitType v1.TuningUnitType, remainAfterExit *bool, expectedError string) {
+				profile.Spec.TuningUnits = &v1.TuningUnits{
+					Type:            &unitType,
+					RemainAfterExit: remainAfterExit,
+				}
+
+				err := ValidateParameters(profile)
+				if expectedError == "" {
+					Expect(err).ToNot(HaveOccurred())
+					return
+				}
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(expectedError))
+			},
+			table.Entry("oneshot remaining after exit", v1.TuningUnitTypeOneshot, pointer.BoolPtr(true), ""),
+			table.Entry("oneshot not remaining after exit", v1.TuningUnitTypeOneshot, pointer.BoolPtr(false), ""),
+			table.Entry("simple", v1.TuningUnitTypeSimple, nil, ""),
+			table.Entry("simple not remaining after exit", v1.TuningUnitTypeSimple, pointer.BoolPtr(false), ""),
+			table.Entry("simple remaining after exit", v1.TuningUnitTypeSimple, pointer.BoolPtr(true), `the tuning units of the "simple" type can not remain after exit`),
+			table.Entry("unknown type", v1.TuningUnitType("forking"), nil, `the tuning units type should be equal to "oneshot" or "simple"`),
+		)
+
+		It("should default the tuning units service options", func() {
+			Expect(GetTuningUnitType(profile)).To(Equal(v1.TuningUnitTypeOneshot))
+			Expect(IsTuningUnitRemainAfterExit(profile)).To(BeTrue())
+
+			simple := v1.TuningUnitTypeSimple
+			profile.Spec.TuningUnits = &v1.TuningUnits{Type: &simple}
+			Expect(GetTuningUnitType(profile)).To(Equal(v1.TuningUnitTypeSimple))
+			Expect(IsTuningUnitRemainAfterExit(profile)).To(BeFalse())
+		})
+
+		table.DescribeTable("should map the cgroup mode to the kernel argument",
+			func(cgroupMode v1.CgroupMode, expected string) {
+				profile.Spec.CgroupMode = &cgroupMode
+				Expect(ValidateParameters(profile)).ToNot(HaveOccurred())
+				Expect(GetCgroupKernelArg(profile)).To(Equal(expected))
+			},
+			table.Entry("cgroup v1", v1.CgroupModeV1, "systemd.unified_cgroup_hierarchy=0"),
+			table.Entry("cgroup v2", v1.CgroupModeV2, "systemd.unified_cgroup_hierarchy=1"),
+		)
+
+		It("should reject unsupported cgroup mode", func() {
+			cgroupMode := v1.CgroupMode("v3")
+			profile.Spec.CgroupMode = &cgroupMode
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the cgroup mode \"v3\" is not supported"))
+		})
+
+		table.DescribeTable("should map the mitigations mode to the kernel argument",
+			func(mitigations v1.MitigationsMode, expected string) {
+				profile.Spec.Mitigations = &mitigations
+				Expect(ValidateParameters(profile)).ToNot(HaveOccurred())
+				Expect(GetMitigationsKernelArg(profile)).To(Equal(expected))
+			},
+			table.Entry("auto", v1.MitigationsAuto, ""),
+			table.Entry("off", v1.MitigationsOff, "mitigations=off"),
+			table.Entry("full", v1.MitigationsFull, "mitigations=auto,nosmt"),
+		)
+
+		It("should not generate the mitigations kernel argument by default", func() {
+			Expect(GetMitigationsKernelArg(profile)).To(BeEmpty())
+		})
+
+		It("should reject unsupported mitigations mode", func() {
+			mitigations := v1.MitigationsMode("Partial")
+			profile.Spec.Mitigations = &mitigations
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`the mitigations mode "Partial" is not supported`))
+		})
+
+		It("should explain security implications of disabled mitigations under the strict validation", func() {
+			mitigations := v1.MitigationsOff
+			profile.Spec.Mitigations = &mitigations
+			profile.Annotations = map[string]string{v1.PerformanceProfileStrictValidationAnnotation: "true"}
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("Spectre and Meltdown like attacks"))
+		})
+
+		table.DescribeTable("should map the transparent huge pages policy to the kernel argument",
+			func(policy v1.TransparentHugePagesPolicy, expected string) {
+				profile.Spec.TransparentHugePages = &policy
+				Expect(ValidateParameters(profile)).ToNot(HaveOccurred())
+				Expect(GetTransparentHugePagesKernelArg(profile)).To(Equal(expected))
+			},
+			table.Entry("always", v1.TransparentHugePagesAlways, "transparent_hugepage=always"),
+			table.Entry("madvise", v1.TransparentHugePagesMAdvise, "transparent_hugepage=madvise"),
+			table.Entry("never", v1.TransparentHugePagesNever, "transparent_hugepage=never"),
+		)
+
+		It("should not generate the transparent huge pages kernel argument by default", func() {
+			Expect(GetTransparentHugePagesKernelArg(profile)).To(BeEmpty())
+		})
+
+		table.DescribeTable("should map the NUMA balancing mode to the kernel argument",
+			func(mode v1.NUMABalancingMode, expected string) {
+				profile.Spec.NUMABalancing = &mode
+				Expect(ValidateParameters(profile)).ToNot(HaveOccurred())
+				Expect(GetNUMABalancingKernelArg(profile)).To(Equal(expected))
+			},
+			table.Entry("enable", v1.NUMABalancingEnable, "numa_balancing=enable"),
+			table.Entry("disable", v1.NUMABalancingDisable, "numa_balancing=disable"),
+		)
+
+		It("should not generate the NUMA balancing kernel argument by default", func() {
+			Expect(GetNUMABalancingKernelArg(profile)).To(BeEmpty())
+		})
+
+		It("should reject unsupported NUMA balancing mode", func() {
+			mode := v1.NUMABalancingMode("Auto")
+			profile.Spec.NUMABalancing = &mode
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`the NUMA balancing mode "Auto" is not supported`))
+		})
+
+		It("should reject unsupported transparent huge pages policy", func() {
+			policy := v1.TransparentHugePagesPolicy("Sometimes")
+			profile.Spec.TransparentHugePages = &policy
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`the transparent huge pages policy "Sometimes" is not supported`))
+		})
+
+		table.DescribeTable("should warn about huge pages with transparent huge pages enabled for all memory regions",
+			func(policy v1.TransparentHugePagesPolicy, withHugepages bool, expectedWarning bool) {
+				profile.Annotations = map[string]string{v1.PerformanceProfileStrictValidationAnnotation: "true"}
+				profile.Spec.TransparentHugePages = &policy
+				if !withHugepages {
+					profile.Spec.HugePages = nil
+				}
+				err := ValidateParameters(profile)
+				if !expectedWarning {
+					Expect(err).ShouldNot(HaveOccurred())
+					return
+				}
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("the profile allocates huge pages and enables transparent huge pages for all memory regions"))
+
+				delete(profile.Annotations, v1.PerformanceProfileStrictValidationAnnotation)
+				Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred(), "should only warn without the strict validation")
+			},
+			table.Entry("always with huge pages", v1.TransparentHugePagesAlways, true, true),
+			table.Entry("always without huge pages", v1.TransparentHugePagesAlways, false, false),
+			table.Entry("madvise with huge pages", v1.TransparentHugePagesMAdvise, true, false),
+			table.Entry("never with huge pages", v1.TransparentHugePagesNever, true, false),
+		)
+
+		It("should accept CPU lists with whitespaces", func() {
+			reserved := v1.CPUSet("0, 1 ,2,3")
+			profile.Spec.CPU.Reserved = &reserved
+			Expect(ValidateParameters(profile)).ToNot(HaveOccurred())
+		})
+
+		It("should reject malformed reserved CPUs", func() {
+			reserved := v1.CPUSet("0,1-a")
+			profile.Spec.CPU.Reserved = &reserved
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to parse reserved CPUs"))
+		})
+
+		It("should reject contradicting workload hints", func() {
+			profile.Spec.WorkloadHints = &v1.WorkloadHints{
+				HighPowerConsumption:  pointer.BoolPtr(true),
+				PerPodPowerManagement: pointer.BoolPtr(true),
+			}
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("can not be enabled together"))
+		})
+
+		It("should reject too low container runtime pids limit", func() {
+			profile.Spec.ContainerRuntime = &v1.ContainerRuntime{PidsLimit: pointer.Int64Ptr(20)}
+			Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred())
+
+			profile.Spec.ContainerRuntime.PidsLimit = pointer.Int64Ptr(19)
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("pids limit should be greater than or equal to 20"))
+		})
+
+		It("should reject unknown clock source", func() {
+			for _, clockSource := range []string{"tsc", "hpet", "acpi_pm"} {
+				profile.Spec.ClockSource = pointer.StringPtr(clockSource)
+				Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred(), "should pass with %q clock source", clockSource)
+			}
+
+			profile.Spec.ClockSource = pointer.StringPtr("jiffies")
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the clock source \"jiffies\" is not supported"))
+		})
+
+		It("should reject malformed kernel memory sizes", func() {
+			for _, size := range []string{"1024", "512M", "4G", "10%", "100%"} {
+				profile.Spec.Memory = &v1.Memory{KernelCore: pointer.StringPtr(size), MovableCore: pointer.StringPtr(size)}
+				Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred(), "should pass with %q memory size", size)
+			}
+
+			for _, size := range []string{"4Gi", "-1G", "101%", "G", ""} {
+				profile.Spec.Memory = &v1.Memory{KernelCore: pointer.StringPtr(size)}
+				err := ValidateParameters(profile)
+				Expect(err).Should(HaveOccurred(), "should fail with %q kernel core memory size", size)
+				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("the kernel core memory %q should be the memory size", size)))
+
+				profile.Spec.Memory = &v1.Memory{MovableCore: pointer.StringPtr(size)}
+				err = ValidateParameters(profile)
+				Expect(err).Should(HaveOccurred(), "should fail with %q movable core memory size", size)
+				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("the movable core memory %q should be the memory size", size)))
+			}
+		})
+
+		It("should reject not positive TSC frequency", func() {
+			profile.Spec.TSCFrequencyKHz = pointer.Int32Ptr(2100000)
+			Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred())
+
+			for _, frequency := range []int32{0, -1} {
+				profile.Spec.TSCFrequencyKHz = pointer.Int32Ptr(frequency)
+				err := ValidateParameters(profile)
+				Expect(err).Should(HaveOccurred(), "should fail with %d kHz TSC frequency", frequency)
+				Expect(err.Error()).To(ContainSubstring("the TSC frequency should be greater than 0"))
+			}
+		})
+
+		It("should reject invalid crash kernel memory", func() {
+			for _, memory := range []string{"256M", "1G@16M", "1G-4G:160M,4G-:256M", "512M-2G:64M@16M"} {
+				profile.Spec.CrashKernelMemory = pointer.StringPtr(memory)
+				Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred(), "should pass with %q crash kernel memory", memory)
+				Expect(GetCrashKernelKernelArg(profile)).To(Equal("crashkernel=" + memory))
+			}
+
+			for _, memory := range []string{"", "auto", "256MB", "-1G:128M", "1G-4G", "1G-4G:160M,"} {
+				profile.Spec.CrashKernelMemory = pointer.StringPtr(memory)
+				err := ValidateParameters(profile)
+				Expect(err).Should(HaveOccurred(), "should fail with %q crash kernel memory", memory)
+				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("the crash kernel memory %q should be the memory size", memory)))
+			}
+		})
+
+		It("should reject unknown and duplicated isolcpus flags", func() {
+			profile.Spec.CPU.IsolcpusFlags = []string{"managed_irq", "domain", "nohz"}
+			Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred())
+
+			profile.Spec.CPU.IsolcpusFlags = []string{"managed_irq", "unknown"}
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`the isolcpus flag "unknown" is not supported`))
+
+			profile.Spec.CPU.IsolcpusFlags = []string{"managed_irq", "managed_irq"}
+			err = ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`the isolcpus flag "managed_irq" is specified more than once`))
+		})
+
+		It("should reject isolcpus flags that contradict the balance isolated field", func() {
+			profile.Spec.CPU.BalanceIsolated = pointer.BoolPtr(true)
+			profile.Spec.CPU.IsolcpusFlags = []string{"domain", "managed_irq"}
+			err := ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("contradict the balanceIsolated field value true"))
+
+			profile.Spec.CPU.BalanceIsolated = pointer.BoolPtr(false)
+			profile.Spec.CPU.IsolcpusFlags = []string{"managed_irq"}
+			err = ValidateParameters(profile)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("contradict the balanceIsolated field value false"))
+		})
+
+		table.DescribeTable("should compute isolcpus flags",
+			func(balanceIsolated *bool, flags []string, expected []string) {
+				profile.Spec.CPU.BalanceIsolated = balanceIsolated
+				profile.Spec.CPU.IsolcpusFlags = flags
+				Expect(GetIsolcpusFlags(profile)).To(Equal(expected))
+			},
+			table.Entry("by default", nil, nil, []string{"managed_irq"}),
+			table.Entry("with the static isolation", pointer.BoolPtr(false), nil, []string{"domain", "managed_irq"}),
+			table.Entry("with the explicit flags", nil, []string{"nohz", "managed_irq"}, []string{"nohz", "managed_irq"}),
+		)
+
+		Context("with CPU0 under isolated CPUs", func() {
+			BeforeEach(func() {
+				isolated := v1.CPUSet("0,5-7")
+				reserved := v1.CPUSet("1-4")
+				profile.Spec.CPU.Isolated = &isolated
+				profile.Spec.CPU.Reserved = &reserved
+			})
+
+			It("should only warn by default", func() {
+				Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred())
+			})
+
+			It("should raise the validation error under the strict validation", func() {
+				profile.Annotations = map[string]string{v1.PerformanceProfileStrictValidationAnnotation: "true"}
+				err := ValidateParameters(profile)
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("the isolated CPUs include CPU0"))
+			})
+
+			It("should pass the strict validation when CPU0 is reserved", func() {
+				isolated := v1.CPUSet("4-7")
+				reserved := v1.CPUSet("0-3")
+				profile.Spec.CPU.Isolated = &isolated
+				profile.Spec.CPU.Reserved = &reserved
+				profile.Annotations = map[string]string{v1.PerformanceProfileStrictValidationAnnotation: "true"}
+				Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with real time kernel and 1G huge pages on the specified NUMA node", func() {
+			BeforeEach(func() {
+				profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(0)
+			})
+
+			It("should only warn by default", func() {
+				Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred())
+			})
+
+			It("should raise the validation error under the strict validation", func() {
+				profile.Annotations = map[string]string{v1.PerformanceProfileStrictValidationAnnotation: "true"}
+				err := ValidateParameters(profile)
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(`the allocation of "1G" huge pages on the specified NUMA node 0 is not reliable with the real time kernel on the amd64 architecture`))
+			})
+
+			It("should pass the strict validation with 2M huge pages on the specified NUMA node", func() {
+				profile.Annotations = map[string]string{v1.PerformanceProfileStrictValidationAnnotation: "true"}
+				defaultSize := v1.HugePageSize(hugepagesSize2M)
+				profile.Spec.HugePages.DefaultHugePagesSize = &defaultSize
+				profile.Spec.HugePages.Pages[0].Size = hugepagesSize2M
+				Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred())
+			})
+
+			It("should pass the strict validation without the real time kernel", func() {
+				profile.Annotations = map[string]string{v1.PerformanceProfileStrictValidationAnnotation: "true"}
+				profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+				Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred())
+			})
+		})
+
+		Context("with the default huge pages size and no huge pages", func() {
+			BeforeEach(func() {
+				profile.Spec.HugePages.Pages = nil
+			})
+
+			It("should only warn by default", func() {
+				Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred())
+			})
+
+			It("should raise the validation error under the strict validation", func() {
+				profile.Annotations = map[string]string{v1.PerformanceProfileStrictValidationAnnotation: "true"}
+				err := ValidateParameters(profile)
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(`the default huge pages size "1G" is set, but no huge pages are reserved at boot`))
+			})
+
+			It("should pass the strict validation without the default huge pages size", func() {
+				profile.Annotations = map[string]string{v1.PerformanceProfileStrictValidationAnnotation: "true"}
+				profile.Spec.HugePages.DefaultHugePagesSize = nil
+				Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred())
+			})
+
+			It("should pass the strict validation with declared huge pages", func() {
+				profile.Annotations = map[string]string{v1.PerformanceProfileStrictValidationAnnotation: "true"}
+				profile.Spec.HugePages.Pages = []v1.HugePage{{Size: hugepagesSize1G, Count: 4}}
+				Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred())
+			})
+		})
+
 		When("pages have duplication", func() {
 			Context("with specified NUMA node", func() {
 				It("should raise the validation error", func() {
@@ -135,6 +912,215 @@ var _ = Describe("PerformanceProfile", func() {
 				})
 			})
 		})
+
+		When("the page size is reserved both at boot and at runtime", func() {
+			It("should raise the validation error", func() {
+				profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, v1.HugePage{
+					Count: 2,
+					Size:  hugepagesSize1G,
+					Node:  pointer.Int32Ptr(1),
+				})
+				err := ValidateParameters(profile)
+				Expect(err).Should(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("the page size %q is reserved both at boot and at runtime on the NUMA node 1", hugepagesSize1G)))
+			})
+
+			It("should allow different sizes in different reservation paths", func() {
+				profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, v1.HugePage{
+					Count: 128,
+					Size:  hugepagesSize2M,
+					Node:  pointer.Int32Ptr(1),
+				})
+				Expect(ValidateParameters(profile)).ShouldNot(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("Huge pages count limits", func() {
+		It("should parse the limits of each size", func() {
+			limits, err := ParseHugepagesCountLimits(" 2M=1000000, 1G=256")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(limits).To(Equal(map[v1.HugePageSize]int32{hugepagesSize2M: 1000000, hugepagesSize1G: 256}))
+
+			limits, err = ParseHugepagesCountLimits("")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(limits).To(BeEmpty())
+		})
+
+		table.DescribeTable("should reject invalid limits",
+			func(value string, expectedError string) {
+				_, err := ParseHugepagesCountLimits(value)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(expectedError))
+			},
+			table.Entry("without the count", "2M", "should have the <size>=<count> format"),
+			table.Entry("with the unsupported size", "4M=10", `has the unsupported size "4M"`),
+			table.Entry("with the zero count", "2M=0", "should have the positive count"),
+			table.Entry("with the count overflow", "2M=4294967296", "should have the positive count"),
+		)
+
+		table.DescribeTable("should validate the count against the limit",
+			func(count int32, expectedError string) {
+				profile.Spec.HugePages.Pages = []v1.HugePage{
+					{Size: hugepagesSize2M, Count: count},
+				}
+				limits := map[v1.HugePageSize]int32{hugepagesSize2M: 1024}
+
+				err := ValidateHugepagesCountLimits(profile, limits)
+				if expectedError == "" {
+					Expect(err).ToNot(HaveOccurred())
+					return
+				}
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(expectedError))
+			},
+			table.Entry("below the limit", int32(1023), ""),
+			table.Entry("equal to the limit", int32(1024), ""),
+			table.Entry("above the limit", int32(1025), `the profile requests 1025 huge pages with the size "2M", that exceeds the configured maximum count 1024`),
+		)
+
+		It("should limit the total of NUMA specific pages", func() {
+			profile.Spec.HugePages.Pages = []v1.HugePage{
+				{Size: hugepagesSize1G, Count: 4, Node: pointer.Int32Ptr(0)},
+				{Size: hugepagesSize1G, Count: 4, Node: pointer.Int32Ptr(1)},
+			}
+			Expect(ValidateHugepagesCountLimits(profile, map[v1.HugePageSize]int32{hugepagesSize1G: 8})).ToNot(HaveOccurred())
+
+			err := ValidateHugepagesCountLimits(profile, map[v1.HugePageSize]int32{hugepagesSize1G: 7})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`the profile requests 8 huge pages with the size "1G"`))
+		})
+
+		It("should not limit sizes without the limit", func() {
+			Expect(ValidateHugepagesCountLimits(profile, map[v1.HugePageSize]int32{hugepagesSize2M: 1})).ToNot(HaveOccurred())
+			Expect(ValidateHugepagesCountLimits(profile, nil)).ToNot(HaveOccurred())
+		})
+	})
+
+	Describe("Isolated CPUs count", func() {
+		var provider *fakeTopologyProvider
+
+		BeforeEach(func() {
+			// cores with hardware threads 0,1 2,3 4,5 and 6,7
+			provider = &fakeTopologyProvider{
+				siblings: []cpuset.CPUSet{
+					cpuset.NewCPUSet(0, 1),
+					cpuset.NewCPUSet(2, 3),
+					cpuset.NewCPUSet(4, 5),
+					cpuset.NewCPUSet(6, 7),
+				},
+			}
+		})
+
+		It("should return the number of isolated CPUs", func() {
+			count, err := IsolatedCount(profile, provider)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(count).To(Equal(4))
+		})
+
+		It("should not count SMT siblings that go offline when SMT is disabled", func() {
+			profile.Spec.AdditionalKernelArgs = []string{kernelArgNoSMT}
+			count, err := IsolatedCount(profile, provider)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(count).To(Equal(2))
+		})
+
+		It("should not count SMT siblings that go offline with the full mitigations", func() {
+			mitigations := v1.MitigationsFull
+			profile.Spec.Mitigations = &mitigations
+			count, err := IsolatedCount(profile, provider)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(count).To(Equal(2))
+		})
+
+		It("should count the first hardware thread of each core with more than two threads per core", func() {
+			profile.Spec.AdditionalKernelArgs = []string{kernelArgNoSMT}
+			// the odd number of isolated CPUs 1-7 on cores with hardware threads 0-3 and 4-7
+			reserved := v1.CPUSet("0")
+			isolated := v1.CPUSet("1-7")
+			profile.Spec.CPU.Reserved = &reserved
+			profile.Spec.CPU.Isolated = &isolated
+			provider.siblings = []cpuset.CPUSet{cpuset.NewCPUSet(0, 1, 2, 3), cpuset.NewCPUSet(4, 5, 6, 7)}
+
+			count, err := IsolatedCount(profile, provider)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(count).To(Equal(1))
+		})
+
+		It("should count all isolated CPUs without the topology", func() {
+			profile.Spec.AdditionalKernelArgs = []string{kernelArgNoSMT}
+			count, err := IsolatedCount(profile, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(count).To(Equal(4))
+
+			provider.siblings = nil
+			count, err = IsolatedCount(profile, provider)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(count).To(Equal(4))
+		})
+
+		It("should return zero when isolated CPUs are not specified", func() {
+			profile.Spec.CPU.Isolated = nil
+			count, err := IsolatedCount(profile, provider)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(count).To(Equal(0))
+		})
+
+		It("should fail on malformed isolated CPUs", func() {
+			isolated := v1.CPUSet("4-a")
+			profile.Spec.CPU.Isolated = &isolated
+			_, err := IsolatedCount(profile, provider)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Canonicalization", func() {
+		It("should canonicalize differently ordered profiles identically", func() {
+			profile.Spec.HugePages.Pages = []v1.HugePage{
+				{Size: hugepagesSize1G, Count: 4},
+				{Size: hugepagesSize2M, Count: 128, Node: pointer.Int32Ptr(1)},
+				{Size: hugepagesSize2M, Count: 64, Node: pointer.Int32Ptr(0)},
+			}
+
+			other := profile.DeepCopy()
+			isolated := v1.CPUSet("7,4,5-6")
+			reserved := v1.CPUSet("0,1,2,3")
+			defaultSize := v1.HugePageSize("1g")
+			other.Spec.CPU.Isolated = &isolated
+			other.Spec.CPU.Reserved = &reserved
+			other.Spec.HugePages.DefaultHugePagesSize = &defaultSize
+			other.Spec.HugePages.Pages = []v1.HugePage{
+				{Size: "2m", Count: 64, Node: pointer.Int32Ptr(0)},
+				{Size: hugepagesSize2M, Count: 128, Node: pointer.Int32Ptr(1)},
+				{Size: "1g", Count: 4},
+			}
+
+			canonical, err := Canonicalize(profile)
+			Expect(err).ToNot(HaveOccurred())
+			otherCanonical, err := Canonicalize(other)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(otherCanonical.Spec).To(Equal(canonical.Spec))
+
+			Expect(string(*canonical.Spec.CPU.Isolated)).To(Equal("4-7"))
+			Expect(canonical.Spec.HugePages.Pages[0].Node).To(Equal(pointer.Int32Ptr(0)))
+			Expect(canonical.Spec.HugePages.Pages[2].Size).To(Equal(v1.HugePageSize(hugepagesSize1G)))
+		})
+
+		It("should not modify the original profile", func() {
+			isolated := v1.CPUSet("7,4,5-6")
+			profile.Spec.CPU.Isolated = &isolated
+
+			_, err := Canonicalize(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(*profile.Spec.CPU.Isolated)).To(Equal("7,4,5-6"))
+		})
+
+		It("should fail on malformed CPU lists", func() {
+			isolated := v1.CPUSet("4-a")
+			profile.Spec.CPU.Isolated = &isolated
+			_, err := Canonicalize(profile)
+			Expect(err).To(HaveOccurred())
+		})
 	})
 
 	Describe("Defaulting", func() {
@@ -170,6 +1156,110 @@ var _ = Describe("PerformanceProfile", func() {
 
 		})
 
+		It("should exclude CPUs from the IRQ affinity", func() {
+			irqAffinity, err := GetIRQAffinity(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(irqAffinity).To(BeEmpty())
+
+			irqExclude := v1.CPUSet("1-2")
+			profile.Spec.CPU.IRQExclude = &irqExclude
+			irqAffinity, err = GetIRQAffinity(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(irqAffinity).To(Equal("0,3"))
+		})
+
+		It("should return the explicit IRQ affinity as the CPU list", func() {
+			affinity := "1-2"
+			profile.Spec.CPU.IRQAffinity = &affinity
+			irqAffinity, err := GetIRQAffinity(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(irqAffinity).To(Equal("1-2"))
+
+			affinity = "0x9"
+			irqAffinity, err = GetIRQAffinity(profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(irqAffinity).To(Equal("0,3"))
+		})
+
+		It("should summarize the full profile", func() {
+			profile.Spec.HugePages.Pages = append(profile.Spec.HugePages.Pages, v1.HugePage{
+				Count: 128,
+				Size:  hugepagesSize2M,
+				Node:  pointer.Int32Ptr(1),
+			})
+			Expect(Summarize(profile)).To(Equal("4 isolated CPUs (4-7), 4 reserved CPUs (0-3), 4x1G hugepages, 128x2M hugepages on NUMA node 1, RT kernel"))
+		})
+
+		It("should summarize the minimal profile", func() {
+			isolated := v1.CPUSet("2-9")
+			minimal := &v1.PerformanceProfile{
+				Spec: v1.PerformanceProfileSpec{
+					CPU: &v1.CPU{Isolated: &isolated},
+				},
+			}
+			Expect(Summarize(minimal)).To(Equal("8 isolated CPUs (2-9)"))
+			Expect(Summarize(&v1.PerformanceProfile{})).To(Equal("no performance tuning"))
+		})
+
+		table.DescribeTable("should map workload hints to kernel arguments",
+			func(hints v1.WorkloadHints, expected []string) {
+				profile.Spec.WorkloadHints = &hints
+				Expect(GetWorkloadHintsKernelArgs(profile)).To(Equal(expected))
+			},
+			table.Entry("real time", v1.WorkloadHints{RealTime: pointer.BoolPtr(true)},
+				[]string{"skew_tick=1", "nohz_full=4-7"}),
+			table.Entry("high power consumption", v1.WorkloadHints{HighPowerConsumption: pointer.BoolPtr(true)},
+				[]string{"processor.max_cstate=1", "intel_idle.max_cstate=0"}),
+			table.Entry("real time with high power consumption", v1.WorkloadHints{RealTime: pointer.BoolPtr(true), HighPowerConsumption: pointer.BoolPtr(true)},
+				[]string{"skew_tick=1", "nohz_full=4-7", "processor.max_cstate=1", "intel_idle.max_cstate=0", "idle=poll"}),
+			table.Entry("per pod power management", v1.WorkloadHints{PerPodPowerManagement: pointer.BoolPtr(true)},
+				[]string{"intel_pstate=passive"}),
+			table.Entry("disabled hints", v1.WorkloadHints{RealTime: pointer.BoolPtr(false)}, nil),
+		)
+
+		table.DescribeTable("should replace the default CPU partitioning kernel arguments",
+			func(hints *v1.WorkloadHints, additionalArgs []string, expected []string) {
+				profile.Spec.WorkloadHints = hints
+				profile.Spec.AdditionalKernelArgs = additionalArgs
+				Expect(GetCPUPartitioningKernelArgs(profile)).To(Equal(expected))
+			},
+			table.Entry("without overrides", nil, nil, []string{"intel_pstate=disable", "nosoftlockup"}),
+			table.Entry("with the per pod power management", &v1.WorkloadHints{PerPodPowerManagement: pointer.BoolPtr(true)}, nil,
+				[]string{"nosoftlockup"}),
+			table.Entry("with the additional kernel argument", nil, []string{"intel_pstate=active"}, []string{"nosoftlockup"}),
+		)
+
+		It("should replace the default real time kernel arguments", func() {
+			Expect(GetRealTimeKernelArgs(profile)).To(Equal([]string{"tsc=nowatchdog"}))
+
+			profile.Spec.TSCFrequencyKHz = pointer.Int32Ptr(2100000)
+			Expect(GetRealTimeKernelArgs(profile)).To(BeEmpty())
+			Expect(GetTSCKernelArgs(profile)).To(Equal([]string{"tsc=reliable", "tsc_early_khz=2100000"}))
+
+			profile.Spec.TSCFrequencyKHz = nil
+			profile.Spec.AdditionalKernelArgs = []string{"tsc=unstable"}
+			Expect(GetRealTimeKernelArgs(profile)).To(BeEmpty())
+		})
+
+		It("should derive the real time kernel from the workload hints", func() {
+			profile.Spec.RealTimeKernel = nil
+			Expect(IsRealTimeKernelEnabled(profile)).To(BeFalse())
+
+			profile.Spec.WorkloadHints = &v1.WorkloadHints{RealTime: pointer.BoolPtr(true)}
+			Expect(IsRealTimeKernelEnabled(profile)).To(BeTrue())
+
+			// the explicit field overrides the hint
+			profile.Spec.RealTimeKernel = &v1.RealTimeKernel{Enabled: pointer.BoolPtr(false)}
+			Expect(IsRealTimeKernelEnabled(profile)).To(BeFalse())
+		})
+
+		It("should return default architecture", func() {
+			Expect(GetArchitecture(profile)).To(Equal(components.ArchitectureAMD64))
+
+			profile.Spec.Architecture = pointer.StringPtr(components.ArchitectureARM64)
+			Expect(GetArchitecture(profile)).To(Equal(components.ArchitectureARM64))
+		})
+
 		It("should return default MachineConfigPoolSelector", func() {
 
 			profile.Spec.MachineConfigPoolSelector = nil
@@ -0,0 +1,31 @@
+package profile
+
+import (
+	"fmt"
+
+	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// PlanReboots returns the number of node reboots caused by applying the batch of changed profiles,
+// every profile change is expected to reboot nodes selected by the profile node selector, and a node
+// selected by several profiles is counted once, the machine config pool applies all changes with a single reboot
+func PlanReboots(profiles []*v1.PerformanceProfile, nodes []corev1.Node) (int, error) {
+	rebooted := map[string]bool{}
+	for _, profile := range profiles {
+		// the empty selector matches all nodes, that can not be the profile intention
+		if len(profile.Spec.NodeSelector) == 0 {
+			return 0, fmt.Errorf("the performance profile %q does not have the node selector", profile.Name)
+		}
+
+		selector := labels.SelectorFromSet(profile.Spec.NodeSelector)
+		for i := range nodes {
+			if selector.Matches(labels.Set(nodes[i].Labels)) {
+				rebooted[nodes[i].Name] = true
+			}
+		}
+	}
+	return len(rebooted), nil
+}
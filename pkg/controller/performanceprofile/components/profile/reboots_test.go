@@ -0,0 +1,65 @@
+package profile
+
+import (
+	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+)
+
+var _ = Describe("Reboots planning", func() {
+	var nodes []corev1.Node
+
+	newNode := func(name string, labels map[string]string) corev1.Node {
+		return corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	newProfile := func(name string, nodeSelector map[string]string) *v1.PerformanceProfile {
+		profile := testutils.NewPerformanceProfile(name)
+		profile.Spec.NodeSelector = nodeSelector
+		return profile
+	}
+
+	BeforeEach(func() {
+		nodes = []corev1.Node{
+			newNode("worker-0", map[string]string{"node-role.kubernetes.io/worker-cnf": "", "zone": "a"}),
+			newNode("worker-1", map[string]string{"node-role.kubernetes.io/worker-cnf": "", "zone": "b"}),
+			newNode("worker-2", map[string]string{"node-role.kubernetes.io/worker-rt": "", "zone": "a"}),
+			newNode("worker-3", map[string]string{"node-role.kubernetes.io/worker": ""}),
+		}
+	})
+
+	It("should count nodes selected by each profile", func() {
+		profiles := []*v1.PerformanceProfile{
+			newProfile("cnf", map[string]string{"node-role.kubernetes.io/worker-cnf": ""}),
+			newProfile("rt", map[string]string{"node-role.kubernetes.io/worker-rt": ""}),
+		}
+		Expect(PlanReboots(profiles, nodes)).To(Equal(3))
+	})
+
+	It("should count the node selected by several profiles once", func() {
+		profiles := []*v1.PerformanceProfile{
+			newProfile("cnf", map[string]string{"node-role.kubernetes.io/worker-cnf": ""}),
+			newProfile("zone-a", map[string]string{"zone": "a"}),
+		}
+		Expect(PlanReboots(profiles, nodes)).To(Equal(3))
+	})
+
+	It("should not count nodes without profiles", func() {
+		profiles := []*v1.PerformanceProfile{
+			newProfile("none", map[string]string{"node-role.kubernetes.io/worker-none": ""}),
+		}
+		Expect(PlanReboots(profiles, nodes)).To(Equal(0))
+		Expect(PlanReboots(nil, nodes)).To(Equal(0))
+	})
+
+	It("should reject the profile without the node selector", func() {
+		_, err := PlanReboots([]*v1.PerformanceProfile{newProfile("all", nil)}, nodes)
+		Expect(err).To(MatchError(`the performance profile "all" does not have the node selector`))
+	})
+})
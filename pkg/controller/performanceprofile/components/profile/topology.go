@@ -0,0 +1,219 @@
+package profile
+
+import (
+	"fmt"
+	"sort"
+
+	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpuset"
+)
+
+// TopologyProvider returns the hardware topology of nodes selected by the performance profile
+type TopologyProvider interface {
+	// GetNUMANodesCount returns the smallest number of NUMA nodes among the profile nodes,
+	// zero means that the topology is unknown
+	GetNUMANodesCount(profile *v1.PerformanceProfile) (int, error)
+	// GetCoreSiblings returns sets of hardware threads that share the same core on the profile nodes,
+	// empty list means that the topology is unknown
+	GetCoreSiblings(profile *v1.PerformanceProfile) ([]cpuset.CPUSet, error)
+	// GetNUMANodesCPUs returns CPUs of each NUMA node on the profile nodes keyed by the NUMA node,
+	// empty map means that the topology is unknown
+	GetNUMANodesCPUs(profile *v1.PerformanceProfile) (map[int]cpuset.CPUSet, error)
+	// GetMemoryKilobytes returns the smallest amount of memory among the profile nodes in kilobytes,
+	// zero means that the topology is unknown
+	GetMemoryKilobytes(profile *v1.PerformanceProfile) (int64, error)
+}
+
+// ValidateHugepagesNUMANodes verifies that huge pages are allocated only on NUMA nodes that exist on all profile nodes
+func ValidateHugepagesNUMANodes(profile *v1.PerformanceProfile, provider TopologyProvider) error {
+	if profile.Spec.HugePages == nil {
+		return nil
+	}
+
+	count, err := provider.GetNUMANodesCount(profile)
+	if err != nil {
+		return err
+	}
+
+	// we can not validate huge pages without the topology
+	if count == 0 {
+		return nil
+	}
+
+	for _, page := range profile.Spec.HugePages.Pages {
+		if page.Node != nil && int(*page.Node) >= count {
+			return validationError(fmt.Sprintf("the huge pages %q can not be allocated on the NUMA node %d, the profile nodes have only %d NUMA nodes", page.Size, *page.Node, count))
+		}
+	}
+	return nil
+}
+
+// ValidateHugepagesNUMAAffinity warns when huge pages are allocated on the NUMA node without isolated CPUs,
+// workloads running on isolated CPUs access such huge pages via the remote memory, that increases the latency
+func ValidateHugepagesNUMAAffinity(profile *v1.PerformanceProfile, provider TopologyProvider) error {
+	if profile.Spec.HugePages == nil || profile.Spec.CPU == nil {
+		return nil
+	}
+
+	numaNodesCPUs, err := provider.GetNUMANodesCPUs(profile)
+	if err != nil {
+		return err
+	}
+
+	// we can not validate the affinity without the topology
+	if len(numaNodesCPUs) == 0 {
+		return nil
+	}
+
+	isolated, err := GetIsolatedCPUs(profile)
+	if err != nil {
+		return err
+	}
+
+	var isolatedNUMANodes []int
+	for node, cpus := range numaNodesCPUs {
+		if !cpus.Intersection(isolated).IsEmpty() {
+			isolatedNUMANodes = append(isolatedNUMANodes, node)
+		}
+	}
+	sort.Ints(isolatedNUMANodes)
+
+	for _, page := range profile.Spec.HugePages.Pages {
+		// huge pages allocated via kernel boot arguments are spread over all NUMA nodes
+		if page.Node == nil {
+			continue
+		}
+
+		if cpus, ok := numaNodesCPUs[int(*page.Node)]; ok && !cpus.Intersection(isolated).IsEmpty() {
+			continue
+		}
+
+		return validationWarning(profile, fmt.Sprintf("the huge pages %q are allocated on the NUMA node %d, but isolated CPUs %q are on NUMA nodes %v, allocate huge pages on the NUMA node of isolated CPUs to avoid the remote memory access", page.Size, *page.Node, isolated, isolatedNUMANodes))
+	}
+	return nil
+}
+
+// ValidateSMTSiblings verifies that isolated and reserved CPUs stay online once SMT is disabled,
+// the kernel keeps online only the first hardware thread of each core and takes its siblings offline
+func ValidateSMTSiblings(profile *v1.PerformanceProfile, provider TopologyProvider) error {
+	if !isSMTDisabled(profile) || profile.Spec.CPU == nil {
+		return nil
+	}
+
+	siblings, err := provider.GetCoreSiblings(profile)
+	if err != nil {
+		return err
+	}
+
+	// we can not validate CPUs without the topology
+	if len(siblings) == 0 {
+		return nil
+	}
+
+	offline := getSMTOfflineCPUs(siblings)
+
+	isolated, err := GetIsolatedCPUs(profile)
+	if err != nil {
+		return err
+	}
+	if overlap := isolated.Intersection(offline); !overlap.IsEmpty() {
+		return validationError(fmt.Sprintf("the isolated CPUs %q are SMT siblings that go offline once SMT is disabled", overlap))
+	}
+
+	if profile.Spec.CPU.Reserved != nil {
+		reserved, err := components.ParseCPUList(string(*profile.Spec.CPU.Reserved))
+		if err != nil {
+			return err
+		}
+		if overlap := reserved.Intersection(offline); !overlap.IsEmpty() {
+			return validationError(fmt.Sprintf("the reserved CPUs %q are SMT siblings that go offline once SMT is disabled", overlap))
+		}
+	}
+	return nil
+}
+
+// ValidateIsolatedCPUsPercentage verifies that the profile does not isolate more than the allowed percentage
+// of CPUs online on the profile nodes, too few housekeeping CPUs destabilize the node. SMT siblings that go offline
+// once SMT is disabled are not online.
+func ValidateIsolatedCPUsPercentage(profile *v1.PerformanceProfile, provider TopologyProvider) error {
+	if !hasIsolatedCPUs(profile) {
+		return nil
+	}
+
+	siblings, err := provider.GetCoreSiblings(profile)
+	if err != nil {
+		return err
+	}
+
+	// we can not know online CPUs without the topology
+	if len(siblings) == 0 {
+		return nil
+	}
+
+	online := cpuset.NewCPUSet()
+	for _, cpus := range siblings {
+		online = online.Union(cpus)
+	}
+	if isSMTDisabled(profile) {
+		offline := getSMTOfflineCPUs(siblings)
+		isolated, err := GetIsolatedCPUs(profile)
+		if err != nil {
+			return validationError(err.Error())
+		}
+		// ValidateSMTSiblings already rejects isolated CPUs that go offline
+		if !isolated.Intersection(offline).IsEmpty() {
+			return nil
+		}
+		online = online.Difference(offline)
+	}
+	return validateIsolatedCPUsPercentage(profile, online.Size())
+}
+
+// HousekeepingCPUs returns CPUs that handle device interrupts and run system services once all exclusions apply:
+// reserved CPUs without CPUs excluded from the interrupts handling and without SMT siblings that go offline
+// once SMT is disabled, the nil provider or the unknown topology keep SMT siblings
+func HousekeepingCPUs(profile *v1.PerformanceProfile, provider TopologyProvider) ([]int, error) {
+	if profile.Spec.CPU == nil || profile.Spec.CPU.Reserved == nil {
+		return nil, nil
+	}
+
+	housekeeping, err := components.ParseCPUList(string(*profile.Spec.CPU.Reserved))
+	if err != nil {
+		return nil, err
+	}
+
+	irqAffinity, err := GetIRQAffinity(profile)
+	if err != nil {
+		return nil, err
+	}
+	if irqAffinity != "" {
+		housekeeping, err = components.ParseCPUList(irqAffinity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if provider != nil && isSMTDisabled(profile) {
+		siblings, err := provider.GetCoreSiblings(profile)
+		if err != nil {
+			return nil, err
+		}
+		housekeeping = housekeeping.Difference(getSMTOfflineCPUs(siblings))
+	}
+	return housekeeping.ToSlice(), nil
+}
+
+// getSMTOfflineCPUs returns hardware threads that go offline once SMT is disabled,
+// the kernel keeps online only the first hardware thread of each core
+func getSMTOfflineCPUs(siblings []cpuset.CPUSet) cpuset.CPUSet {
+	offline := cpuset.NewCPUSet()
+	for _, core := range siblings {
+		threads := core.ToSlice()
+		if len(threads) > 1 {
+			offline = offline.Union(cpuset.NewCPUSet(threads[1:]...))
+		}
+	}
+	return offline
+}
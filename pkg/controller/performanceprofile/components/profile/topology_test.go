@@ -0,0 +1,338 @@
+package profile
+
+import (
+	"fmt"
+
+	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpuset"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+)
+
+type fakeTopologyProvider struct {
+	numaNodes     int
+	siblings      []cpuset.CPUSet
+	numaNodesCPUs map[int]cpuset.CPUSet
+	memory        int64
+	err           error
+}
+
+func (p *fakeTopologyProvider) GetNUMANodesCount(profile *v1.PerformanceProfile) (int, error) {
+	return p.numaNodes, p.err
+}
+
+func (p *fakeTopologyProvider) GetCoreSiblings(profile *v1.PerformanceProfile) ([]cpuset.CPUSet, error) {
+	return p.siblings, p.err
+}
+
+func (p *fakeTopologyProvider) GetNUMANodesCPUs(profile *v1.PerformanceProfile) (map[int]cpuset.CPUSet, error) {
+	return p.numaNodesCPUs, p.err
+}
+
+func (p *fakeTopologyProvider) GetMemoryKilobytes(profile *v1.PerformanceProfile) (int64, error) {
+	return p.memory, p.err
+}
+
+var _ = Describe("Huge pages NUMA nodes validation", func() {
+	var profile *v1.PerformanceProfile
+	var provider *fakeTopologyProvider
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+		provider = &fakeTopologyProvider{numaNodes: 2}
+	})
+
+	It("should accept huge pages on existing NUMA nodes", func() {
+		profile.Spec.HugePages.Pages = []v1.HugePage{
+			{Size: hugepagesSize1G, Count: 4, Node: pointer.Int32Ptr(0)},
+			{Size: hugepagesSize2M, Count: 128, Node: pointer.Int32Ptr(1)},
+			{Size: hugepagesSize2M, Count: 128},
+		}
+		Expect(ValidateHugepagesNUMANodes(profile, provider)).ToNot(HaveOccurred())
+	})
+
+	It("should reject huge pages on out of range NUMA nodes", func() {
+		profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(3)
+		err := ValidateHugepagesNUMANodes(profile, provider)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`the huge pages "1G" can not be allocated on the NUMA node 3, the profile nodes have only 2 NUMA nodes`))
+
+		profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(2)
+		Expect(ValidateHugepagesNUMANodes(profile, provider)).To(HaveOccurred())
+	})
+
+	It("should skip the validation when the topology is unknown", func() {
+		profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(3)
+		provider.numaNodes = 0
+		Expect(ValidateHugepagesNUMANodes(profile, provider)).ToNot(HaveOccurred())
+	})
+
+	It("should return the provider error", func() {
+		provider.err = fmt.Errorf("failed to get the topology")
+		Expect(ValidateHugepagesNUMANodes(profile, provider)).To(MatchError("failed to get the topology"))
+	})
+})
+
+var _ = Describe("Huge pages NUMA affinity validation", func() {
+	var profile *v1.PerformanceProfile
+	var provider *fakeTopologyProvider
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+		// the reserved CPUs 0-3 are on the NUMA node 0 and the isolated CPUs 4-7 are on the NUMA node 1
+		provider = &fakeTopologyProvider{
+			numaNodesCPUs: map[int]cpuset.CPUSet{
+				0: cpuset.NewCPUSet(0, 1, 2, 3),
+				1: cpuset.NewCPUSet(4, 5, 6, 7),
+			},
+		}
+	})
+
+	It("should accept huge pages on the NUMA node of isolated CPUs", func() {
+		profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(1)
+		Expect(ValidateHugepagesNUMAAffinity(profile, provider)).ToNot(HaveOccurred())
+	})
+
+	It("should accept huge pages allocated via kernel boot arguments", func() {
+		Expect(ValidateHugepagesNUMAAffinity(profile, provider)).ToNot(HaveOccurred())
+	})
+
+	It("should warn about huge pages on the NUMA node without isolated CPUs", func() {
+		profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(0)
+		Expect(ValidateHugepagesNUMAAffinity(profile, provider)).ToNot(HaveOccurred())
+
+		profile.Annotations = map[string]string{v1.PerformanceProfileStrictValidationAnnotation: "true"}
+		err := ValidateHugepagesNUMAAffinity(profile, provider)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`the huge pages "1G" are allocated on the NUMA node 0, but isolated CPUs "4-7" are on NUMA nodes [1]`))
+	})
+
+	It("should skip the validation when the topology is unknown", func() {
+		profile.Annotations = map[string]string{v1.PerformanceProfileStrictValidationAnnotation: "true"}
+		profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(0)
+		provider.numaNodesCPUs = nil
+		Expect(ValidateHugepagesNUMAAffinity(profile, provider)).ToNot(HaveOccurred())
+	})
+})
+
+var _ = Describe("Isolated CPUs percentage validation", func() {
+	var profile *v1.PerformanceProfile
+	var provider *fakeTopologyProvider
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+		// 20 online CPUs, one hardware thread per core
+		var siblings []cpuset.CPUSet
+		for cpu := 0; cpu < 20; cpu++ {
+			siblings = append(siblings, cpuset.NewCPUSet(cpu))
+		}
+		provider = &fakeTopologyProvider{siblings: siblings}
+	})
+
+	table.DescribeTable("should validate the isolated CPUs percentage of online CPUs",
+		func(reserved, isolated v1.CPUSet, maxPercentage string, valid bool) {
+			profile.Spec.CPU.Reserved = &reserved
+			profile.Spec.CPU.Isolated = &isolated
+			if maxPercentage != "" {
+				profile.Annotations = map[string]string{v1.PerformanceProfileMaxIsolatedCPUsPercentageAnnotation: maxPercentage}
+			}
+
+			err := ValidateIsolatedCPUsPercentage(profile, provider)
+			if valid {
+				Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			Expect(err).To(HaveOccurred())
+		},
+		table.Entry("at the default threshold", v1.CPUSet("0-1"), v1.CPUSet("2-19"), "", true),
+		table.Entry("above the default threshold", v1.CPUSet("0"), v1.CPUSet("1-19"), "", false),
+		// reserved and isolated CPUs cover only a part of online CPUs
+		table.Entry("with CPUs that are neither reserved nor isolated", v1.CPUSet("0"), v1.CPUSet("1-15"), "75", true),
+		table.Entry("above the configured threshold", v1.CPUSet("0"), v1.CPUSet("1-16"), "75", false),
+	)
+
+	It("should report online CPUs of the profile nodes", func() {
+		reserved := v1.CPUSet("0")
+		isolated := v1.CPUSet("1-19")
+		profile.Spec.CPU.Reserved = &reserved
+		profile.Spec.CPU.Isolated = &isolated
+
+		err := ValidateIsolatedCPUsPercentage(profile, provider)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("the profile isolates 19 out of 20 online CPUs, that exceeds 90% of online CPUs"))
+	})
+
+	It("should not count SMT siblings that go offline as online CPUs", func() {
+		reserved := v1.CPUSet("0-1")
+		isolated := v1.CPUSet("2-3")
+		profile.Spec.CPU.Reserved = &reserved
+		profile.Spec.CPU.Isolated = &isolated
+		profile.Annotations = map[string]string{v1.PerformanceProfileMaxIsolatedCPUsPercentageAnnotation: "40"}
+		// cores with hardware threads 0,4 1,5 2,6 and 3,7
+		provider.siblings = []cpuset.CPUSet{
+			cpuset.NewCPUSet(0, 4),
+			cpuset.NewCPUSet(1, 5),
+			cpuset.NewCPUSet(2, 6),
+			cpuset.NewCPUSet(3, 7),
+		}
+		Expect(ValidateIsolatedCPUsPercentage(profile, provider)).ToNot(HaveOccurred())
+
+		profile.Spec.AdditionalKernelArgs = []string{kernelArgNoSMT}
+		err := ValidateIsolatedCPUsPercentage(profile, provider)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("the profile isolates 2 out of 4 online CPUs, that exceeds 40% of online CPUs"))
+	})
+
+	It("should skip the validation when the topology is unknown", func() {
+		reserved := v1.CPUSet("0")
+		isolated := v1.CPUSet("1-19")
+		profile.Spec.CPU.Reserved = &reserved
+		profile.Spec.CPU.Isolated = &isolated
+		provider.siblings = nil
+		Expect(ValidateIsolatedCPUsPercentage(profile, provider)).ToNot(HaveOccurred())
+	})
+})
+
+var _ = Describe("SMT siblings validation", func() {
+	var profile *v1.PerformanceProfile
+	var provider *fakeTopologyProvider
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+		reserved := v1.CPUSet("0-1")
+		isolated := v1.CPUSet("2-3")
+		profile.Spec.CPU.Reserved = &reserved
+		profile.Spec.CPU.Isolated = &isolated
+		profile.Spec.AdditionalKernelArgs = []string{"nosmt"}
+		// cores with hardware threads 0,4 1,5 2,6 and 3,7
+		provider = &fakeTopologyProvider{
+			siblings: []cpuset.CPUSet{
+				cpuset.NewCPUSet(0, 4),
+				cpuset.NewCPUSet(1, 5),
+				cpuset.NewCPUSet(2, 6),
+				cpuset.NewCPUSet(3, 7),
+			},
+		}
+	})
+
+	It("should accept CPUs that stay online", func() {
+		Expect(ValidateSMTSiblings(profile, provider)).ToNot(HaveOccurred())
+	})
+
+	It("should reject isolated CPUs that go offline", func() {
+		isolated := v1.CPUSet("2-3,6")
+		profile.Spec.CPU.Isolated = &isolated
+		err := ValidateSMTSiblings(profile, provider)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`the isolated CPUs "6" are SMT siblings that go offline once SMT is disabled`))
+	})
+
+	It("should reject isolated group CPUs that go offline", func() {
+		profile.Spec.CPU.IsolatedGroups = []v1.IsolatedCPUGroup{{Name: "dpdk", CPUs: "7"}}
+		err := ValidateSMTSiblings(profile, provider)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`the isolated CPUs "7" are SMT siblings`))
+	})
+
+	It("should reject reserved CPUs that go offline", func() {
+		reserved := v1.CPUSet("0,4")
+		profile.Spec.CPU.Reserved = &reserved
+		err := ValidateSMTSiblings(profile, provider)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(`the reserved CPUs "4" are SMT siblings that go offline once SMT is disabled`))
+	})
+
+	It("should validate siblings with the full mitigations mode", func() {
+		profile.Spec.AdditionalKernelArgs = nil
+		mitigations := v1.MitigationsFull
+		profile.Spec.Mitigations = &mitigations
+		isolated := v1.CPUSet("2-3,6")
+		profile.Spec.CPU.Isolated = &isolated
+		Expect(ValidateSMTSiblings(profile, provider)).To(HaveOccurred())
+	})
+
+	It("should skip the validation when SMT is enabled", func() {
+		profile.Spec.AdditionalKernelArgs = nil
+		isolated := v1.CPUSet("2-7")
+		profile.Spec.CPU.Isolated = &isolated
+		Expect(ValidateSMTSiblings(profile, provider)).ToNot(HaveOccurred())
+	})
+
+	It("should skip the validation when the topology is unknown", func() {
+		isolated := v1.CPUSet("2-7")
+		profile.Spec.CPU.Isolated = &isolated
+		provider.siblings = nil
+		Expect(ValidateSMTSiblings(profile, provider)).ToNot(HaveOccurred())
+	})
+})
+
+var _ = Describe("Housekeeping CPUs", func() {
+	var profile *v1.PerformanceProfile
+	var provider *fakeTopologyProvider
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+		reserved := v1.CPUSet("0-1,4-5")
+		isolated := v1.CPUSet("2-3,6-7")
+		profile.Spec.CPU.Reserved = &reserved
+		profile.Spec.CPU.Isolated = &isolated
+		// cores with hardware threads 0,4 1,5 2,6 and 3,7
+		provider = &fakeTopologyProvider{
+			siblings: []cpuset.CPUSet{
+				cpuset.NewCPUSet(0, 4),
+				cpuset.NewCPUSet(1, 5),
+				cpuset.NewCPUSet(2, 6),
+				cpuset.NewCPUSet(3, 7),
+			},
+		}
+	})
+
+	It("should return reserved CPUs without exclusions", func() {
+		Expect(HousekeepingCPUs(profile, provider)).To(Equal([]int{0, 1, 4, 5}))
+	})
+
+	It("should drop CPUs excluded from the interrupts handling", func() {
+		irqExclude := v1.CPUSet("1")
+		profile.Spec.CPU.IRQExclude = &irqExclude
+		Expect(HousekeepingCPUs(profile, provider)).To(Equal([]int{0, 4, 5}))
+	})
+
+	It("should drop SMT siblings that go offline once SMT is disabled", func() {
+		profile.Spec.AdditionalKernelArgs = []string{"nosmt"}
+		Expect(HousekeepingCPUs(profile, provider)).To(Equal([]int{0, 1}))
+
+		// the unknown topology keeps SMT siblings
+		Expect(HousekeepingCPUs(profile, nil)).To(Equal([]int{0, 1, 4, 5}))
+	})
+
+	It("should combine all exclusions", func() {
+		irqExclude := v1.CPUSet("0")
+		profile.Spec.CPU.IRQExclude = &irqExclude
+		mitigations := v1.MitigationsFull
+		profile.Spec.Mitigations = &mitigations
+		Expect(HousekeepingCPUs(profile, provider)).To(Equal([]int{1}))
+	})
+
+	It("should return nothing without reserved CPUs", func() {
+		profile.Spec.CPU.Reserved = nil
+		Expect(HousekeepingCPUs(profile, provider)).To(BeEmpty())
+	})
+
+	It("should return the error on malformed exclusions", func() {
+		irqExclude := v1.CPUSet("2")
+		profile.Spec.CPU.IRQExclude = &irqExclude
+		_, err := HousekeepingCPUs(profile, provider)
+		Expect(err).To(HaveOccurred())
+
+		profile.Spec.CPU.IRQExclude = nil
+		profile.Spec.AdditionalKernelArgs = []string{"nosmt"}
+		provider.err = fmt.Errorf("failed")
+		_, err = HousekeepingCPUs(profile, provider)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -3,6 +3,7 @@ package runtimeclass
 import (
 	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	componentsprofile "github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/profile"
 
 	nodev1beta1 "k8s.io/api/node/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -11,17 +12,23 @@ import (
 // New returns a new RuntimeClass object
 func New(profile *performancev1.PerformanceProfile, handler string) *nodev1beta1.RuntimeClass {
 	name := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
-	return &nodev1beta1.RuntimeClass{
+	runtimeClass := &nodev1beta1.RuntimeClass{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "RuntimeClass",
 			APIVersion: "node.k8s.io/v1beta1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name: name,
+			Name:   name,
+			Labels: components.GetComponentLabels(nil),
 		},
 		Handler: handler,
 		Scheduling: &nodev1beta1.Scheduling{
 			NodeSelector: profile.Spec.NodeSelector,
 		},
 	}
+
+	if groups := componentsprofile.FormatIsolatedCPUGroups(profile); groups != "" {
+		runtimeClass.Annotations = map[string]string{components.IsolatedCPUGroupsAnnotation: groups}
+	}
+	return runtimeClass
 }
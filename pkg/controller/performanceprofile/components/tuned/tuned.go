@@ -4,7 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io/ioutil"
-	"strconv"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -19,12 +19,39 @@ import (
 )
 
 const (
-	cmdlineDelimiter             = " "
-	templateIsolatedCpus         = "IsolatedCpus"
-	templateStaticIsolation      = "StaticIsolation"
-	templateDefaultHugepagesSize = "DefaultHugepagesSize"
-	templateHugepages            = "Hugepages"
-	templateAdditionalArgs       = "AdditionalArgs"
+	cmdlineDelimiter                = " "
+	templateIsolatedCpus            = "IsolatedCpus"
+	templateIsolcpusFlags           = "IsolcpusFlags"
+	templateDefaultHugepagesSize    = "DefaultHugepagesSize"
+	templateHugepages               = "Hugepages"
+	templateAdditionalArgs          = "AdditionalArgs"
+	templateIOMMUArgs               = "IOMMUArgs"
+	templateClockSource             = "ClockSource"
+	templateTSCArgs                 = "TSCArgs"
+	templateMemoryArgs              = "MemoryArgs"
+	templateIRQAffinity             = "IRQAffinity"
+	templateWorkloadHintsArgs       = "WorkloadHintsArgs"
+	templateCgroupArg               = "CgroupArg"
+	templateMitigationsArg          = "MitigationsArg"
+	templateTransparentHugePages    = "TransparentHugePages"
+	templateTransparentHugePagesArg = "TransparentHugePagesArg"
+	templateCrashKernelArg          = "CrashKernelArg"
+	templateNUMABalancing           = "NUMABalancing"
+	templateNUMABalancingArg        = "NUMABalancingArg"
+	templateCPUPartitioningArgs     = "CPUPartitioningArgs"
+	templateRealTimeArgs            = "RealTimeArgs"
+)
+
+// cmdlineCgroupPrefix is the prefix of the tuned profile line that carries the cgroup mode kernel argument
+const cmdlineCgroupPrefix = "cmdline_cgroup=+"
+
+const (
+	// cmdlinePrefix is the prefix of tuned profile options that carry kernel arguments
+	cmdlinePrefix = "cmdline_"
+	// CmdlineRealtime is the tuned profile option that carries the isolcpus kernel argument
+	CmdlineRealtime = "cmdline_realtime"
+	// VariableIsolatedCores is the tuned profile variable that carries isolated CPUs of kernel arguments
+	VariableIsolatedCores = "isolated_cores"
 )
 
 func new(name string, profiles []tunedv1.TunedProfile, recommends []tunedv1.TunedRecommend) *tunedv1.Tuned {
@@ -36,6 +63,7 @@ func new(name string, profiles []tunedv1.TunedProfile, recommends []tunedv1.Tune
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: components.NamespaceNodeTuningOperator,
+			Labels:    components.GetComponentLabels(nil),
 		},
 		Spec: tunedv1.TunedSpec{
 			Profile:   profiles,
@@ -49,12 +77,15 @@ func NewNodePerformance(assetsDir string, profile *performancev1.PerformanceProf
 
 	templateArgs := make(map[string]string)
 
-	if profile.Spec.CPU.Isolated != nil {
-		templateArgs[templateIsolatedCpus] = string(*profile.Spec.CPU.Isolated)
-		if profile.Spec.CPU.BalanceIsolated != nil && *profile.Spec.CPU.BalanceIsolated == false {
-			templateArgs[templateStaticIsolation] = strconv.FormatBool(true)
-		}
+	// isolated groups are isolated together with the isolated CPUs
+	isolated, err := componentsprofile.GetIsolatedCPUs(profile)
+	if err != nil {
+		return nil, err
 	}
+	if !isolated.IsEmpty() {
+		templateArgs[templateIsolatedCpus] = isolated.String()
+	}
+	templateArgs[templateIsolcpusFlags] = strings.Join(componentsprofile.GetIsolcpusFlags(profile), ",")
 
 	if profile.Spec.HugePages != nil {
 		var defaultHugepageSize performancev1.HugePageSize
@@ -100,8 +131,68 @@ func NewNodePerformance(assetsDir string, profile *performancev1.PerformanceProf
 		templateArgs[templateHugepages] = hugepagesArgs
 	}
 
-	if profile.Spec.AdditionalKernelArgs != nil {
-		templateArgs[templateAdditionalArgs] = strings.Join(profile.Spec.AdditionalKernelArgs, cmdlineDelimiter)
+	irqAffinity, err := componentsprofile.GetIRQAffinity(profile)
+	if err != nil {
+		return nil, err
+	}
+	if irqAffinity != "" {
+		templateArgs[templateIRQAffinity] = irqAffinity
+	}
+
+	// the architecture specific machine configs carry IOMMU kernel arguments of the mixed architecture cluster
+	if len(componentsprofile.GetArchitectures(profile)) == 0 {
+		templateArgs[templateIOMMUArgs] = strings.Join(components.IOMMUKernelArgs[componentsprofile.GetArchitecture(profile)], cmdlineDelimiter)
+	}
+
+	if profile.Spec.ClockSource != nil {
+		templateArgs[templateClockSource] = *profile.Spec.ClockSource
+	}
+
+	if tscArgs := componentsprofile.GetTSCKernelArgs(profile); len(tscArgs) > 0 {
+		templateArgs[templateTSCArgs] = strings.Join(tscArgs, cmdlineDelimiter)
+	}
+
+	if memoryArgs := componentsprofile.GetMemoryKernelArgs(profile); len(memoryArgs) > 0 {
+		templateArgs[templateMemoryArgs] = strings.Join(memoryArgs, cmdlineDelimiter)
+	}
+
+	if crashKernelArg := componentsprofile.GetCrashKernelKernelArg(profile); crashKernelArg != "" {
+		templateArgs[templateCrashKernelArg] = crashKernelArg
+	}
+
+	if cgroupArg := componentsprofile.GetCgroupKernelArg(profile); cgroupArg != "" {
+		templateArgs[templateCgroupArg] = cgroupArg
+	}
+
+	if mitigationsArg := componentsprofile.GetMitigationsKernelArg(profile); mitigationsArg != "" {
+		templateArgs[templateMitigationsArg] = mitigationsArg
+	}
+
+	// the runtime policy should follow the boot policy, otherwise the tuned overrides it
+	if policy := componentsprofile.GetTransparentHugePagesPolicy(profile); policy != "" {
+		templateArgs[templateTransparentHugePages] = policy
+		templateArgs[templateTransparentHugePagesArg] = componentsprofile.GetTransparentHugePagesKernelArg(profile)
+	}
+
+	// the runtime mode should follow the boot mode, otherwise the tuned overrides it
+	if numaBalancingArg := componentsprofile.GetNUMABalancingKernelArg(profile); numaBalancingArg != "" {
+		templateArgs[templateNUMABalancingArg] = numaBalancingArg
+		if *profile.Spec.NUMABalancing == performancev1.NUMABalancingEnable {
+			templateArgs[templateNUMABalancing] = "1"
+		}
+	}
+
+	// the default kernel arguments of the template follow the same precedence as the workload hints arguments
+	templateArgs[templateCPUPartitioningArgs] = strings.Join(componentsprofile.GetCPUPartitioningKernelArgs(profile), cmdlineDelimiter)
+	templateArgs[templateRealTimeArgs] = strings.Join(componentsprofile.GetRealTimeKernelArgs(profile), cmdlineDelimiter)
+
+	workloadHintsArgs, additionalArgs := getKernelArgs(profile)
+	if len(workloadHintsArgs) > 0 {
+		templateArgs[templateWorkloadHintsArgs] = strings.Join(workloadHintsArgs, cmdlineDelimiter)
+	}
+
+	if additionalArgs != nil {
+		templateArgs[templateAdditionalArgs] = strings.Join(additionalArgs, cmdlineDelimiter)
 	}
 
 	profileData, err := getProfileData(getProfilePath(components.ProfileNamePerformance, assetsDir), templateArgs)
@@ -126,7 +217,94 @@ func NewNodePerformance(assetsDir string, profile *performancev1.PerformanceProf
 			MachineConfigLabels: componentsprofile.GetMachineConfigLabel(profile),
 		},
 	}
-	return new(name, profiles, recommends), nil
+
+	// nodes of architecture specific pools share the profile, their pools select only architecture specific machine configs
+	for _, architecture := range componentsprofile.GetArchitectures(profile) {
+		recommends = append(recommends, tunedv1.TunedRecommend{
+			Profile:             &name,
+			Priority:            &priority,
+			MachineConfigLabels: componentsprofile.GetArchitectureMachineConfigLabel(profile, architecture),
+		})
+	}
+
+	performanceTuned := new(name, profiles, recommends)
+	performanceTuned.Annotations = map[string]string{components.IsolatedCPUsAnnotation: isolated.String()}
+	if groups := componentsprofile.FormatIsolatedCPUGroups(profile); groups != "" {
+		performanceTuned.Annotations[components.IsolatedCPUGroupsAnnotation] = groups
+	}
+	return performanceTuned, nil
+}
+
+// GetCgroupKernelArg returns the cgroup mode kernel argument of the tuned profiles,
+// it returns an empty string when the tuned does not select the cgroup mode
+func GetCgroupKernelArg(tuned *tunedv1.Tuned) string {
+	for _, profile := range tuned.Spec.Profile {
+		if profile.Data == nil {
+			continue
+		}
+
+		for _, line := range strings.Split(*profile.Data, "\n") {
+			if strings.HasPrefix(line, cmdlineCgroupPrefix) {
+				return strings.TrimSpace(strings.TrimPrefix(line, cmdlineCgroupPrefix))
+			}
+		}
+	}
+	return ""
+}
+
+// GetKernelCmdlineOptions returns tuned profile options that carry kernel arguments and the isolated cores
+// variable they refer to, mapped by the option name
+func GetKernelCmdlineOptions(tuned *tunedv1.Tuned) map[string]string {
+	options := map[string]string{}
+	for _, profile := range tuned.Spec.Profile {
+		if profile.Data == nil {
+			continue
+		}
+
+		for _, line := range strings.Split(*profile.Data, "\n") {
+			parts := strings.SplitN(line, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+
+			name := strings.TrimSpace(parts[0])
+			if strings.HasPrefix(name, cmdlinePrefix) || name == VariableIsolatedCores {
+				options[name] = strings.TrimSpace(parts[1])
+			}
+		}
+	}
+	return options
+}
+
+// GetKernelArgs returns kernel arguments the tuned profiles append to the bootloader command line,
+// ordered by the option name, tuned variables the arguments refer to are not expanded
+func GetKernelArgs(tuned *tunedv1.Tuned) []string {
+	options := GetKernelCmdlineOptions(tuned)
+	delete(options, VariableIsolatedCores)
+
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var args []string
+	for _, name := range names {
+		args = append(args, strings.Fields(strings.TrimPrefix(options[name], "+"))...)
+	}
+	return args
+}
+
+// getKernelArgs returns kernel arguments derived from workload hints and the additional kernel arguments,
+// including the real time kernel additional arguments when the real time kernel is enabled.
+// The kernel uses the last occurrence of the repeated argument, so the user specified additional arguments
+// take precedence: the template puts them last and the derived arguments with the same key are dropped,
+// so the kernel command line carries only the user value. The default kernel arguments of the template are dropped
+// the same way once the profile derives the argument with the same key.
+func getKernelArgs(profile *performancev1.PerformanceProfile) ([]string, []string) {
+	additionalArgs := componentsprofile.GetAdditionalKernelArgs(profile)
+	workloadHintsArgs := componentsprofile.OverrideKernelArgs(componentsprofile.GetWorkloadHintsKernelArgs(profile), additionalArgs)
+	return workloadHintsArgs, additionalArgs
 }
 
 func getProfilePath(name string, assetsDir string) string {
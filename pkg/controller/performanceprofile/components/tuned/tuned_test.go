@@ -7,6 +7,7 @@ import (
 
 	"github.com/ghodss/yaml"
 	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	v1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
@@ -72,6 +73,325 @@ var _ = Describe("Tuned", func() {
 			Expect(cmdlineRealtimeWithoutCPUBalancing.MatchString(manifest)).To(BeTrue())
 		})
 
+		It("should generate isolcpus kernel argument with the specified flags", func() {
+			profile.Spec.CPU.IsolcpusFlags = []string{"nohz", "domain", "managed_irq"}
+			tuned, err := NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(*tuned.Spec.Profile[0].Data).To(ContainSubstring("isolcpus=nohz,domain,managed_irq,${isolated_cores}"))
+		})
+
+		It("should omit CPU isolation kernel arguments when isolated CPUs are not provided", func() {
+			profile.Spec.CPU.Isolated = nil
+			tuned, err := NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+
+			data := *tuned.Spec.Profile[0].Data
+			Expect(data).To(ContainSubstring("include=openshift-node,network-latency"))
+			Expect(data).ToNot(MatchRegexp(`(?m)^isolated_cores=`))
+			Expect(data).ToNot(ContainSubstring("isolcpus="))
+			Expect(data).ToNot(ContainSubstring("rcu_nocbs="))
+			Expect(data).To(MatchRegexp(`cmdline_cpu_part=\+nohz=on intel_pstate=disable nosoftlockup`))
+			Expect(data).To(MatchRegexp(`cmdline_realtime=\+tsc=nowatchdog intel_iommu=on iommu=pt\s`))
+		})
+
+		table.DescribeTable("should generate IOMMU kernel arguments according to the architecture",
+			func(architecture string, expectedArgs string, unexpectedArgs string) {
+				profile.Spec.Architecture = pointer.StringPtr(architecture)
+				manifest := getTunedManifest(profile)
+
+				cmdlineRealtime := regexp.MustCompile(`\s*cmdline_realtime=\+\s*tsc=nowatchdog\s+` + regexp.QuoteMeta(expectedArgs) + `\s+isolcpus=`)
+				Expect(cmdlineRealtime.MatchString(manifest)).To(BeTrue())
+				Expect(manifest).ToNot(ContainSubstring(unexpectedArgs))
+			},
+			table.Entry("amd64", components.ArchitectureAMD64, "intel_iommu=on iommu=pt", "iommu.passthrough=1"),
+			table.Entry("arm64", components.ArchitectureARM64, "iommu.passthrough=1", "intel_iommu=on"),
+		)
+
+		It("should share the profile without IOMMU kernel arguments between architecture specific pools", func() {
+			profile.Annotations = map[string]string{v1.PerformanceProfileArchitecturesAnnotation: "amd64, arm64"}
+			tuned, err := NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+
+			args := GetKernelArgs(tuned)
+			Expect(args).ToNot(ContainElement("intel_iommu=on"))
+			Expect(args).ToNot(ContainElement("iommu.passthrough=1"))
+
+			Expect(tuned.Spec.Recommend).To(HaveLen(3))
+			Expect(tuned.Spec.Recommend[0].MachineConfigLabels).To(Equal(map[string]string{testutils.MachineConfigLabelKey: testutils.MachineConfigLabelValue}))
+			Expect(tuned.Spec.Recommend[1].MachineConfigLabels).To(Equal(map[string]string{testutils.MachineConfigLabelKey: testutils.MachineConfigLabelValue + "-amd64"}))
+			Expect(tuned.Spec.Recommend[2].MachineConfigLabels).To(Equal(map[string]string{testutils.MachineConfigLabelKey: testutils.MachineConfigLabelValue + "-arm64"}))
+			for _, recommend := range tuned.Spec.Recommend {
+				Expect(*recommend.Profile).To(Equal(*tuned.Spec.Profile[0].Name))
+			}
+		})
+
+		It("should generate IRQ affinity kernel argument only when CPUs excluded", func() {
+			manifest := getTunedManifest(profile)
+			Expect(manifest).ToNot(ContainSubstring("irqaffinity="))
+
+			irqExclude := v1.CPUSet("0")
+			profile.Spec.CPU.IRQExclude = &irqExclude
+			manifest = getTunedManifest(profile)
+			Expect(manifest).To(ContainSubstring("cmdline_irqaffinity=+irqaffinity=1-3"))
+		})
+
+		It("should generate the IRQ affinity kernel argument from the IRQ affinity list and mask", func() {
+			irqAffinity := "0,2"
+			profile.Spec.CPU.IRQAffinity = &irqAffinity
+			manifest := getTunedManifest(profile)
+			Expect(manifest).To(ContainSubstring("cmdline_irqaffinity=+irqaffinity=0,2"))
+
+			irqAffinity = "0x5"
+			manifest = getTunedManifest(profile)
+			Expect(manifest).To(ContainSubstring("cmdline_irqaffinity=+irqaffinity=0,2"))
+		})
+
+		It("should generate workload hints kernel arguments before additional kernel arguments", func() {
+			manifest := getTunedManifest(profile)
+			Expect(manifest).ToNot(ContainSubstring("cmdline_workloadHints"))
+
+			profile.Spec.WorkloadHints = &v1.WorkloadHints{HighPowerConsumption: pointer.BoolPtr(true)}
+			profile.Spec.AdditionalKernelArgs = []string{"nosmt"}
+			tuned, err := NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+
+			cmdlineOrder := regexp.MustCompile(`cmdline_workloadHints=\+processor.max_cstate=1 intel_idle.max_cstate=0\s+cmdline_additionalArg=\+\s*nosmt`)
+			Expect(cmdlineOrder.MatchString(*tuned.Spec.Profile[0].Data)).To(BeTrue())
+		})
+
+		It("should keep only the user value of the kernel argument derived from workload hints", func() {
+			profile.Spec.WorkloadHints = &v1.WorkloadHints{HighPowerConsumption: pointer.BoolPtr(true)}
+			profile.Spec.AdditionalKernelArgs = []string{"processor.max_cstate=0"}
+			tuned, err := NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+
+			data := *tuned.Spec.Profile[0].Data
+			cmdlineOverride := regexp.MustCompile(`cmdline_workloadHints=\+intel_idle.max_cstate=0\s+cmdline_additionalArg=\+\s*processor.max_cstate=0`)
+			Expect(cmdlineOverride.MatchString(data)).To(BeTrue())
+			Expect(data).ToNot(ContainSubstring("processor.max_cstate=1"))
+
+			// the workload hints entry is omitted once the user overrides all derived arguments
+			profile.Spec.WorkloadHints = &v1.WorkloadHints{PerPodPowerManagement: pointer.BoolPtr(true)}
+			profile.Spec.AdditionalKernelArgs = []string{"intel_pstate=active"}
+			tuned, err = NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+
+			data = *tuned.Spec.Profile[0].Data
+			Expect(data).ToNot(ContainSubstring("cmdline_workloadHints"))
+			Expect(data).ToNot(ContainSubstring("intel_pstate=passive"))
+			Expect(data).To(MatchRegexp(`cmdline_additionalArg=\+\s*intel_pstate=active`))
+		})
+
+		It("should generate the single intel_pstate kernel argument with the per pod power management", func() {
+			profile.Spec.WorkloadHints = &v1.WorkloadHints{PerPodPowerManagement: pointer.BoolPtr(true)}
+			tuned, err := NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+
+			data := *tuned.Spec.Profile[0].Data
+			Expect(strings.Count(data, "intel_pstate=")).To(Equal(1))
+			Expect(data).To(MatchRegexp(`cmdline_cpu_part=\+nohz=on rcu_nocbs=\${isolated_cores} tuned.non_isolcpus=\${not_isolated_cpumask} nosoftlockup\s`))
+			Expect(data).To(ContainSubstring("cmdline_workloadHints=+intel_pstate=passive"))
+
+			// the real time kernel without CPU isolation shares the default arguments
+			profile.Spec.CPU.Isolated = nil
+			tuned, err = NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+
+			data = *tuned.Spec.Profile[0].Data
+			Expect(strings.Count(data, "intel_pstate=")).To(Equal(1))
+			Expect(data).To(MatchRegexp(`cmdline_cpu_part=\+nohz=on nosoftlockup\s`))
+		})
+
+		table.DescribeTable("should generate the cgroup mode kernel argument",
+			func(cgroupMode v1.CgroupMode, expected string) {
+				profile.Spec.CgroupMode = &cgroupMode
+				tuned, err := NewNodePerformance(testAssetsDir, profile)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(*tuned.Spec.Profile[0].Data).To(ContainSubstring("cmdline_cgroup=+" + expected))
+				Expect(GetCgroupKernelArg(tuned)).To(Equal(expected))
+			},
+			table.Entry("cgroup v1", v1.CgroupModeV1, "systemd.unified_cgroup_hierarchy=0"),
+			table.Entry("cgroup v2", v1.CgroupModeV2, "systemd.unified_cgroup_hierarchy=1"),
+		)
+
+		It("should not generate the cgroup mode kernel argument when the mode is not specified", func() {
+			tuned, err := NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(*tuned.Spec.Profile[0].Data).ToNot(ContainSubstring("cmdline_cgroup"))
+			Expect(GetCgroupKernelArg(tuned)).To(BeEmpty())
+		})
+
+		It("should return kernel cmdline options and isolated cores", func() {
+			tuned, err := NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+
+			options := GetKernelCmdlineOptions(tuned)
+			Expect(options).To(HaveKeyWithValue(VariableIsolatedCores, "4-7"))
+			Expect(options).To(HaveKeyWithValue(CmdlineRealtime, "+tsc=nowatchdog intel_iommu=on iommu=pt isolcpus=managed_irq,${isolated_cores} systemd.cpu_affinity=${not_isolated_cores_expanded}"))
+			Expect(options).To(HaveKey("cmdline_hugepages"))
+			Expect(options).ToNot(HaveKey("not_isolated_cores_expanded"))
+		})
+
+		It("should return kernel arguments of the bootloader command line", func() {
+			profile.Spec.AdditionalKernelArgs = []string{"nmi_watchdog=0"}
+			tuned, err := NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+
+			args := GetKernelArgs(tuned)
+			Expect(args).To(ContainElement("nmi_watchdog=0"))
+			Expect(args).To(ContainElement("rcu_nocbs=${isolated_cores}"))
+			Expect(args).To(ContainElement("default_hugepagesz=1G"))
+			Expect(args).ToNot(ContainElement("+"))
+			Expect(args).ToNot(ContainElement("4-7"))
+		})
+
+		It("should isolate CPUs of isolated groups and keep groups under the annotation", func() {
+			tuned, err := NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tuned.Annotations).ToNot(HaveKey(components.IsolatedCPUGroupsAnnotation))
+
+			isolated := v1.CPUSet("4-5")
+			profile.Spec.CPU.Isolated = &isolated
+			profile.Spec.CPU.IsolatedGroups = []v1.IsolatedCPUGroup{
+				{Name: "dpdk", CPUs: "6"},
+				{Name: "rt", CPUs: "7-8"},
+			}
+			tuned, err = NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(GetKernelCmdlineOptions(tuned)).To(HaveKeyWithValue(VariableIsolatedCores, "4-8"))
+			Expect(tuned.Annotations).To(HaveKeyWithValue(components.IsolatedCPUGroupsAnnotation, "dpdk=6;rt=7-8"))
+		})
+
+		It("should generate clock source kernel argument only when requested", func() {
+			manifest := getTunedManifest(profile)
+			Expect(manifest).ToNot(ContainSubstring("clocksource="))
+
+			profile.Spec.ClockSource = pointer.StringPtr("tsc")
+			manifest = getTunedManifest(profile)
+			Expect(manifest).To(ContainSubstring("cmdline_clocksource=+clocksource=tsc"))
+		})
+
+		table.DescribeTable("should generate the transparent huge pages kernel argument according to the policy",
+			func(policy *v1.TransparentHugePagesPolicy, expectedArg string, expectedRuntimePolicy string) {
+				profile.Spec.TransparentHugePages = policy
+				tuned, err := NewNodePerformance(testAssetsDir, profile)
+				Expect(err).ToNot(HaveOccurred())
+
+				data := *tuned.Spec.Profile[0].Data
+				Expect(data).To(MatchRegexp(`(?m)^transparent_hugepages=` + expectedRuntimePolicy + `\s`))
+				if expectedArg == "" {
+					Expect(data).ToNot(ContainSubstring("transparent_hugepage="))
+					return
+				}
+				Expect(data).To(ContainSubstring("cmdline_thp=+" + expectedArg + "\n"))
+			},
+			table.Entry("not set", nil, "", "never"),
+			table.Entry("always", transparentHugePagesPolicyPtr(v1.TransparentHugePagesAlways), "transparent_hugepage=always", "always"),
+			table.Entry("madvise", transparentHugePagesPolicyPtr(v1.TransparentHugePagesMAdvise), "transparent_hugepage=madvise", "madvise"),
+			table.Entry("never", transparentHugePagesPolicyPtr(v1.TransparentHugePagesNever), "transparent_hugepage=never", "never"),
+		)
+
+		table.DescribeTable("should generate the NUMA balancing kernel argument according to the mode",
+			func(mode *v1.NUMABalancingMode, expectedArg string, expectedRuntimeMode string) {
+				profile.Spec.NUMABalancing = mode
+				tuned, err := NewNodePerformance(testAssetsDir, profile)
+				Expect(err).ToNot(HaveOccurred())
+
+				data := *tuned.Spec.Profile[0].Data
+				Expect(data).To(MatchRegexp(`(?m)^kernel.numa_balancing=` + expectedRuntimeMode + `\s`))
+				if expectedArg == "" {
+					Expect(data).ToNot(ContainSubstring("cmdline_numa_balancing"))
+					return
+				}
+				Expect(data).To(ContainSubstring("cmdline_numa_balancing=+" + expectedArg + "\n"))
+			},
+			table.Entry("not set", nil, "", "0"),
+			table.Entry("enable", numaBalancingModePtr(v1.NUMABalancingEnable), "numa_balancing=enable", "1"),
+			table.Entry("disable", numaBalancingModePtr(v1.NUMABalancingDisable), "numa_balancing=disable", "0"),
+		)
+
+		table.DescribeTable("should generate the mitigations kernel argument according to the mode",
+			func(mitigations *v1.MitigationsMode, expected string) {
+				profile.Spec.Mitigations = mitigations
+				tuned, err := NewNodePerformance(testAssetsDir, profile)
+				Expect(err).ToNot(HaveOccurred())
+
+				data := *tuned.Spec.Profile[0].Data
+				if expected == "" {
+					Expect(data).ToNot(ContainSubstring("mitigations="))
+					return
+				}
+				Expect(data).To(ContainSubstring("cmdline_mitigations=+" + expected + "\n"))
+			},
+			table.Entry("not set", nil, ""),
+			table.Entry("auto", mitigationsModePtr(v1.MitigationsAuto), ""),
+			table.Entry("off", mitigationsModePtr(v1.MitigationsOff), "mitigations=off"),
+			table.Entry("full", mitigationsModePtr(v1.MitigationsFull), "mitigations=auto,nosmt"),
+		)
+
+		It("should generate memory kernel arguments only when requested", func() {
+			tuned, err := NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*tuned.Spec.Profile[0].Data).ToNot(ContainSubstring("cmdline_memory"))
+
+			profile.Spec.Memory = &v1.Memory{MovableCore: pointer.StringPtr("10%")}
+			tuned, err = NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*tuned.Spec.Profile[0].Data).To(ContainSubstring("cmdline_memory=+movablecore=10%\n"))
+
+			profile.Spec.Memory.KernelCore = pointer.StringPtr("4G")
+			tuned, err = NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*tuned.Spec.Profile[0].Data).To(ContainSubstring("cmdline_memory=+kernelcore=4G movablecore=10%"))
+		})
+
+		It("should generate crash kernel argument only when requested", func() {
+			tuned, err := NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*tuned.Spec.Profile[0].Data).ToNot(ContainSubstring("crashkernel"))
+
+			profile.Spec.CrashKernelMemory = pointer.StringPtr("1G-4G:160M,4G-:256M")
+			tuned, err = NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*tuned.Spec.Profile[0].Data).To(ContainSubstring("cmdline_crashkernel=+crashkernel=1G-4G:160M,4G-:256M\n"))
+		})
+
+		It("should generate TSC frequency kernel arguments only when requested", func() {
+			tuned, err := NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*tuned.Spec.Profile[0].Data).ToNot(ContainSubstring("tsc_early_khz"))
+
+			profile.Spec.TSCFrequencyKHz = pointer.Int32Ptr(2100000)
+			tuned, err = NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*tuned.Spec.Profile[0].Data).To(ContainSubstring("cmdline_tsc=+tsc=reliable tsc_early_khz=2100000"))
+		})
+
+		It("should replace the default tsc kernel argument with the TSC frequency one", func() {
+			profile.Spec.TSCFrequencyKHz = pointer.Int32Ptr(2100000)
+			tuned, err := NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+
+			data := *tuned.Spec.Profile[0].Data
+			Expect(regexp.MustCompile(`[\s+]tsc=`).FindAllString(data, -1)).To(HaveLen(1))
+			Expect(data).To(MatchRegexp(`cmdline_realtime=\+\s*intel_iommu=on iommu=pt isolcpus=`))
+			Expect(data).To(ContainSubstring("cmdline_tsc=+tsc=reliable tsc_early_khz=2100000"))
+		})
+
+		It("should keep only the user value of the default template kernel arguments", func() {
+			profile.Spec.AdditionalKernelArgs = []string{"tsc=unstable", "intel_pstate=active"}
+			tuned, err := NewNodePerformance(testAssetsDir, profile)
+			Expect(err).ToNot(HaveOccurred())
+
+			data := *tuned.Spec.Profile[0].Data
+			Expect(data).ToNot(ContainSubstring("tsc=nowatchdog"))
+			Expect(data).ToNot(ContainSubstring("intel_pstate=disable"))
+			Expect(data).To(MatchRegexp(`cmdline_additionalArg=\+\s*tsc=unstable intel_pstate=active`))
+		})
+
 		It("should generate yaml with expected parameters for additional kernel arguments", func() {
 			profile.Spec.AdditionalKernelArgs = additionalArgs
 			manifest := getTunedManifest(profile)
@@ -79,6 +399,18 @@ var _ = Describe("Tuned", func() {
 			Expect(cmdlineAdditionalArg.MatchString(manifest)).To(BeTrue())
 		})
 
+		It("should append the real time kernel additional arguments only when the real time kernel is enabled", func() {
+			profile.Spec.AdditionalKernelArgs = []string{"nmi_watchdog=0"}
+			profile.Spec.RealTimeKernel.AdditionalArgs = []string{"rcupdate.rcu_normal_after_boot=0"}
+			manifest := getTunedManifest(profile)
+			Expect(manifest).To(MatchRegexp(`cmdline_additionalArg=\+\s*nmi_watchdog=0\s+rcupdate.rcu_normal_after_boot=0`))
+
+			profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+			manifest = getTunedManifest(profile)
+			Expect(manifest).To(MatchRegexp(`cmdline_additionalArg=\+\s*nmi_watchdog=0`))
+			Expect(manifest).ToNot(ContainSubstring("rcupdate.rcu_normal_after_boot"))
+		})
+
 		It("should not allocate hugepages on the specific NUMA node via kernel arguments", func() {
 			manifest := getTunedManifest(profile)
 			Expect(strings.Count(manifest, "hugepagesz=")).Should(BeNumerically("==", 2))
@@ -162,3 +494,15 @@ var _ = Describe("Tuned", func() {
 		})
 	})
 })
+
+func mitigationsModePtr(mitigations v1.MitigationsMode) *v1.MitigationsMode {
+	return &mitigations
+}
+
+func numaBalancingModePtr(mode v1.NUMABalancingMode) *v1.NUMABalancingMode {
+	return &mode
+}
+
+func transparentHugePagesPolicyPtr(policy v1.TransparentHugePagesPolicy) *v1.TransparentHugePagesPolicy {
+	return &policy
+}
@@ -0,0 +1,82 @@
+package tuningdaemon
+
+import (
+	"fmt"
+	"strings"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	componentsprofile "github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/profile"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// NodeAnnotationPrefix is the prefix of node annotations that carry the tuning configuration,
+// the tuning script reads them, so the configuration can be updated without the node reboot
+const NodeAnnotationPrefix = "tuning.performance.openshift.io/"
+
+const (
+	nodeAnnotationProfile           = NodeAnnotationPrefix + "profile"
+	nodeAnnotationIsolatedCPUs      = NodeAnnotationPrefix + "isolated-cpus"
+	nodeAnnotationReservedCPUs      = NodeAnnotationPrefix + "reserved-cpus"
+	nodeAnnotationIsolatedCPUGroups = NodeAnnotationPrefix + "isolated-cpu-groups"
+)
+
+// maxNodeAnnotationsSize is the maximal size of the tuning annotations, the API server limits the total size
+// of all object annotations to 256KiB, so the tuning annotations use a quarter of it and leave the rest to others
+const maxNodeAnnotationsSize = 64 * 1024
+
+// GetNodeAnnotations returns node annotations with the tuning configuration derived from the performance profile,
+// values are normalized, so the same profile always produces the same annotations
+func GetNodeAnnotations(profile *performancev1.PerformanceProfile) (map[string]string, error) {
+	annotations := map[string]string{
+		nodeAnnotationProfile: profile.Name,
+	}
+
+	if profile.Spec.CPU != nil {
+		isolated, err := componentsprofile.GetIsolatedCPUs(profile)
+		if err != nil {
+			return nil, err
+		}
+		if !isolated.IsEmpty() {
+			annotations[nodeAnnotationIsolatedCPUs] = isolated.String()
+		}
+
+		if profile.Spec.CPU.Reserved != nil {
+			reserved, err := components.NormalizeCPUList(string(*profile.Spec.CPU.Reserved))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse reserved CPUs: %v", err)
+			}
+			annotations[nodeAnnotationReservedCPUs] = reserved
+		}
+
+		if groups := componentsprofile.FormatIsolatedCPUGroups(profile); groups != "" {
+			annotations[nodeAnnotationIsolatedCPUGroups] = groups
+		}
+	}
+
+	if err := validateNodeAnnotations(annotations); err != nil {
+		return nil, err
+	}
+	return annotations, nil
+}
+
+// IsNodeAnnotation returns true when the annotation key belongs to the tuning configuration
+func IsNodeAnnotation(key string) bool {
+	return strings.HasPrefix(key, NodeAnnotationPrefix)
+}
+
+func validateNodeAnnotations(annotations map[string]string) error {
+	size := 0
+	for key, value := range annotations {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("the node annotation %q is invalid: %s", key, strings.Join(errs, ", "))
+		}
+		size += len(key) + len(value)
+	}
+
+	if size > maxNodeAnnotationsSize {
+		return fmt.Errorf("the node annotations size %d bytes exceeds the limit of %d bytes", size, maxNodeAnnotationsSize)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package tuningdaemon
+
+import (
+	"strings"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tuning Daemon node annotations", func() {
+	var profile *performancev1.PerformanceProfile
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+	})
+
+	It("should serialize the profile configuration with normalized values", func() {
+		reserved := performancev1.CPUSet("3,2, 1,0")
+		profile.Spec.CPU.Reserved = &reserved
+		profile.Spec.CPU.IsolatedGroups = []performancev1.IsolatedCPUGroup{
+			{Name: "dpdk", CPUs: "9,8"},
+		}
+
+		annotations, err := GetNodeAnnotations(profile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(annotations).To(Equal(map[string]string{
+			"tuning.performance.openshift.io/profile":             "test",
+			"tuning.performance.openshift.io/isolated-cpus":       "4-9",
+			"tuning.performance.openshift.io/reserved-cpus":       "0-3",
+			"tuning.performance.openshift.io/isolated-cpu-groups": "dpdk=8-9",
+		}))
+
+		for key := range annotations {
+			Expect(IsNodeAnnotation(key)).To(BeTrue())
+		}
+	})
+
+	It("should produce the same annotations for equivalent profiles", func() {
+		annotations, err := GetNodeAnnotations(profile)
+		Expect(err).ToNot(HaveOccurred())
+
+		reserved := performancev1.CPUSet("0,1,2,3")
+		isolated := performancev1.CPUSet("7,6,5,4")
+		profile.Spec.CPU.Reserved = &reserved
+		profile.Spec.CPU.Isolated = &isolated
+
+		reordered, err := GetNodeAnnotations(profile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reordered).To(Equal(annotations))
+	})
+
+	It("should omit annotations of unset values", func() {
+		profile.Spec.CPU = nil
+
+		annotations, err := GetNodeAnnotations(profile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(annotations).To(Equal(map[string]string{
+			"tuning.performance.openshift.io/profile": "test",
+		}))
+	})
+
+	It("should fail on malformed CPUs", func() {
+		reserved := performancev1.CPUSet("0-a")
+		profile.Spec.CPU.Reserved = &reserved
+
+		_, err := GetNodeAnnotations(profile)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail when annotations exceed the size limit", func() {
+		err := validateNodeAnnotations(map[string]string{
+			nodeAnnotationIsolatedCPUGroups: strings.Repeat("a", maxNodeAnnotationsSize),
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("exceeds the limit"))
+	})
+
+	It("should not treat foreign annotations as the tuning configuration", func() {
+		Expect(IsNodeAnnotation("machineconfiguration.openshift.io/state")).To(BeFalse())
+	})
+})
@@ -0,0 +1,147 @@
+package tuningdaemon
+
+import (
+	"fmt"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	componentsprofile "github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/profile"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	// ContainerName contains the name of the tuning daemon container
+	ContainerName = "tuning-daemon"
+	// LabelProfile is the label that selects the tuning daemon pods of the specific performance profile
+	LabelProfile = "performance.openshift.io/tuning-daemon"
+)
+
+const (
+	environmentProfileName  = "PROFILE_NAME"
+	environmentNodeName     = "NODE_NAME"
+	environmentIsolatedCPUs = "ISOLATED_CPUS"
+	environmentReservedCPUs = "RESERVED_CPUS"
+)
+
+// New returns new DaemonSet object that runs the runtime tuning agent on the performance profile nodes
+func New(profile *performancev1.PerformanceProfile, image string) (*appsv1.DaemonSet, error) {
+	name := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+	labels := map[string]string{
+		LabelProfile: profile.Name,
+	}
+
+	env, err := getEnvironment(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &appsv1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+			Kind:       "DaemonSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: components.NamespaceNodeTuningOperator,
+			Labels:    components.GetComponentLabels(labels),
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					Affinity: getAffinity(profile),
+					// performance sensitive nodes can be tainted to keep regular workloads away
+					Tolerations: []corev1.Toleration{
+						{
+							Operator: corev1.TolerationOpExists,
+						},
+					},
+					HostPID: true,
+					Containers: []corev1.Container{
+						{
+							Name:  ContainerName,
+							Image: image,
+							Env:   env,
+							SecurityContext: &corev1.SecurityContext{
+								Privileged: pointer.BoolPtr(true),
+							},
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// getAffinity returns the node affinity that places the tuning daemon pods on the performance profile nodes
+func getAffinity(profile *performancev1.PerformanceProfile) *corev1.Affinity {
+	var requirements []corev1.NodeSelectorRequirement
+	for key, value := range profile.Spec.NodeSelector {
+		requirements = append(requirements, corev1.NodeSelectorRequirement{
+			Key:      key,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{value},
+		})
+	}
+
+	return &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{
+						MatchExpressions: requirements,
+					},
+				},
+			},
+		},
+	}
+}
+
+// getEnvironment returns the tuning daemon configuration derived from the performance profile
+func getEnvironment(profile *performancev1.PerformanceProfile) ([]corev1.EnvVar, error) {
+	env := []corev1.EnvVar{
+		{
+			Name:  environmentProfileName,
+			Value: profile.Name,
+		},
+		{
+			Name: environmentNodeName,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "spec.nodeName",
+				},
+			},
+		},
+	}
+
+	if profile.Spec.CPU == nil {
+		return env, nil
+	}
+
+	isolated, err := componentsprofile.GetIsolatedCPUs(profile)
+	if err != nil {
+		return nil, err
+	}
+	if !isolated.IsEmpty() {
+		env = append(env, corev1.EnvVar{Name: environmentIsolatedCPUs, Value: isolated.String()})
+	}
+
+	if profile.Spec.CPU.Reserved != nil {
+		reserved, err := components.NormalizeCPUList(string(*profile.Spec.CPU.Reserved))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse reserved CPUs: %v", err)
+		}
+		env = append(env, corev1.EnvVar{Name: environmentReservedCPUs, Value: reserved})
+	}
+
+	return env, nil
+}
@@ -0,0 +1,13 @@
+package tuningdaemon
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestTuningDaemon(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Tuning Daemon Suite")
+}
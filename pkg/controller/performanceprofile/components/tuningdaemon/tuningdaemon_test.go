@@ -0,0 +1,62 @@
+package tuningdaemon
+
+import (
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const testImage = "quay.io/openshift-kni/performance-tuning-daemon:test"
+
+var _ = Describe("Tuning Daemon", func() {
+	var profile *performancev1.PerformanceProfile
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+	})
+
+	It("should generate the DaemonSet targeting the profile nodes", func() {
+		ds, err := New(profile, testImage)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(ds.Name).To(Equal(components.GetComponentName(profile.Name, components.ComponentNamePrefix)))
+		Expect(ds.Namespace).To(Equal(components.NamespaceNodeTuningOperator))
+		Expect(ds.Spec.Selector.MatchLabels).To(Equal(ds.Spec.Template.Labels))
+
+		terms := ds.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		Expect(terms).To(HaveLen(1))
+		Expect(terms[0].MatchExpressions).To(ConsistOf(corev1.NodeSelectorRequirement{
+			Key:      "nodekey",
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{"nodeValue"},
+		}))
+	})
+
+	It("should pass the profile configuration via the environment", func() {
+		reserved := performancev1.CPUSet("0, 1 ,2,3")
+		profile.Spec.CPU.Reserved = &reserved
+
+		ds, err := New(profile, testImage)
+		Expect(err).ToNot(HaveOccurred())
+
+		containers := ds.Spec.Template.Spec.Containers
+		Expect(containers).To(HaveLen(1))
+		Expect(containers[0].Image).To(Equal(testImage))
+		Expect(containers[0].Env).To(ContainElement(corev1.EnvVar{Name: environmentProfileName, Value: "test"}))
+		Expect(containers[0].Env).To(ContainElement(corev1.EnvVar{Name: environmentIsolatedCPUs, Value: "4-7"}))
+		Expect(containers[0].Env).To(ContainElement(corev1.EnvVar{Name: environmentReservedCPUs, Value: "0-3"}))
+	})
+
+	It("should fail on malformed CPUs", func() {
+		isolated := performancev1.CPUSet("4-a")
+		profile.Spec.CPU.Isolated = &isolated
+
+		_, err := New(profile, testImage)
+		Expect(err).To(HaveOccurred())
+	})
+})
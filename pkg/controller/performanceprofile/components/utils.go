@@ -16,6 +16,20 @@ func GetComponentName(profileName string, prefix string) string {
 	return fmt.Sprintf("%s-%s", prefix, profileName)
 }
 
+// GetComponentLabels returns labels of the object generated for the performance profile, the given labels
+// together with the standard labels GitOps tools use to identify objects managed by the operator,
+// the given labels are usually selected by other objects, so they take precedence
+func GetComponentLabels(labels map[string]string) map[string]string {
+	componentLabels := map[string]string{
+		LabelManagedBy: ManagedByPerformanceOperator,
+		LabelPartOf:    PartOfPerformanceAddonOperator,
+	}
+	for key, value := range labels {
+		componentLabels[key] = value
+	}
+	return componentLabels
+}
+
 // GetFirstKeyAndValue return the first key / value pair of a map
 func GetFirstKeyAndValue(m map[string]string) (string, string) {
 	for k, v := range m {
@@ -33,6 +47,38 @@ func SplitLabelKey(s string) (domain, role string, err error) {
 	return parts[0], parts[1], nil
 }
 
+// ParseCPUList parses the list of cpus, it ignores whitespaces around CPU numbers and ranges
+func ParseCPUList(cpulist string) (cpuset.CPUSet, error) {
+	return cpuset.Parse(strings.Join(strings.Fields(cpulist), ""))
+}
+
+// ParseCPUMask parses the hexadecimal CPU mask, with or without the 0x prefix, commas that separate
+// 32 bit words of the mask, e.g. "ff,ffffffff", are ignored
+func ParseCPUMask(mask string) (cpuset.CPUSet, error) {
+	value := strings.Replace(strings.TrimPrefix(strings.TrimSpace(mask), "0x"), ",", "", -1)
+	bits, ok := new(big.Int).SetString(value, 16)
+	if !ok || value == "" {
+		return cpuset.NewCPUSet(), fmt.Errorf("invalid CPU mask %q", mask)
+	}
+
+	var cpus []int
+	for cpu := 0; cpu < bits.BitLen(); cpu++ {
+		if bits.Bit(cpu) == 1 {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpuset.NewCPUSet(cpus...), nil
+}
+
+// NormalizeCPUList returns the list of cpus in the canonical form, e.g. "0, 1 ,2" becomes "0-2"
+func NormalizeCPUList(cpulist string) (string, error) {
+	cpus, err := ParseCPUList(cpulist)
+	if err != nil {
+		return "", err
+	}
+	return cpus.String(), nil
+}
+
 // CPUListToHexMask converts a list of cpus into a cpu mask represented in hexdecimal
 func CPUListToHexMask(cpulist string) (hexMask string, err error) {
 	cpus, err := cpuset.Parse(cpulist)
@@ -39,4 +39,38 @@ var _ = Describe("Components utils", func() {
 			}
 		})
 	})
+
+	Context("Parse CPU mask", func() {
+		It("should parse CPU masks with and without the 0x prefix", func() {
+			for _, cpuEntry := range cpuListToMask {
+				cpus, err := ParseCPUMask(cpuEntry.cpuMask)
+				Expect(err).ToNot(HaveOccurred())
+				expected, err := NormalizeCPUList(cpuEntry.cpuList)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cpus.String()).Should(Equal(expected))
+			}
+
+			cpus, err := ParseCPUMask("0x3")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cpus.String()).Should(Equal("0-1"))
+		})
+		It("should fail on malformed CPU mask", func() {
+			_, err := ParseCPUMask("0xzz")
+			Expect(err).To(HaveOccurred())
+			_, err = ParseCPUMask("0x")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Normalize CPU list", func() {
+		It("should normalize CPU list with whitespaces", func() {
+			cpus, err := NormalizeCPUList("0, 1 ,2")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cpus).Should(Equal("0-2"))
+		})
+		It("should fail on malformed CPU list", func() {
+			_, err := NormalizeCPUList("0,a-2")
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
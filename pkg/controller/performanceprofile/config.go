@@ -0,0 +1,105 @@
+package performanceprofile
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	profileutil "github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/profile"
+
+	"k8s.io/klog"
+)
+
+// The environment variables that configure the operator, the operator deployment under deploy/operator.yaml
+// and the CSV document them, so update them together
+const (
+	// tuningDaemonImageEnv is the environment variable that holds the image of the runtime tuning daemon,
+	// the tuning daemon is not deployed when the variable is empty
+	tuningDaemonImageEnv = "TUNING_DAEMON_IMAGE"
+	// machineConfigPoolCreationEnv is the environment variable that allows the operator to create the machine config pool
+	// for the performance profile nodes, when the cluster does not have a pool for the profile
+	machineConfigPoolCreationEnv = "MACHINE_CONFIG_POOL_CREATION"
+	// hugepagesCountLimitsEnv is the environment variable that holds the maximum count of huge pages of each size
+	// a profile can request, e.g. "2M=1000000,1G=256", huge pages are not limited when the variable is empty
+	hugepagesCountLimitsEnv = "HUGEPAGES_COUNT_LIMITS"
+	// rollbackTimeoutEnv is the environment variable that holds the duration a machine config pool can stay degraded
+	// before the operator rolls back the machine config, the rollback is disabled when the variable is empty
+	rollbackTimeoutEnv = "MCP_DEGRADED_ROLLBACK_TIMEOUT"
+	// machineConfigBackupEnv is the environment variable that enables the backup of the previous machine config
+	// spec under the backup machine config, so a user can roll back the machine config manually
+	machineConfigBackupEnv = "MACHINE_CONFIG_BACKUP"
+)
+
+// parseEnv passes the value of the environment variable to the parse function, the unset or empty variable
+// is not parsed, the parse error is logged together with the fallback description, so the operator starts
+// with the default configuration instead of failing on the invalid value
+func parseEnv(name string, fallback string, parse func(value string) error) {
+	value, ok := os.LookupEnv(name)
+	if !ok || value == "" {
+		return
+	}
+
+	if err := parse(value); err != nil {
+		klog.Errorf("failed to parse %s environment variable value %q, %s: %v", name, value, fallback, err)
+	}
+}
+
+// getBoolEnv returns the boolean value of the environment variable, the unset, empty or invalid variable
+// returns false
+func getBoolEnv(name string, fallback string) bool {
+	var result bool
+	parseEnv(name, fallback, func(value string) error {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		result = parsed
+		return nil
+	})
+	return result
+}
+
+// getDurationEnv returns the duration value of the environment variable, the unset, empty or invalid variable
+// returns zero
+func getDurationEnv(name string, fallback string) time.Duration {
+	var result time.Duration
+	parseEnv(name, fallback, func(value string) error {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		result = parsed
+		return nil
+	})
+	return result
+}
+
+func getTuningDaemonImage() string {
+	return os.Getenv(tuningDaemonImageEnv)
+}
+
+func getMachineConfigPoolCreation() bool {
+	return getBoolEnv(machineConfigPoolCreationEnv, "the machine config pool creation is disabled")
+}
+
+func getRollbackTimeout() time.Duration {
+	return getDurationEnv(rollbackTimeoutEnv, "the rollback is disabled")
+}
+
+func getMachineConfigBackup() bool {
+	return getBoolEnv(machineConfigBackupEnv, "the backup is disabled")
+}
+
+func getHugepagesCountLimits() map[performancev1.HugePageSize]int32 {
+	var limits map[performancev1.HugePageSize]int32
+	parseEnv(hugepagesCountLimitsEnv, "huge pages are not limited", func(value string) error {
+		parsed, err := profileutil.ParseHugepagesCountLimits(value)
+		if err != nil {
+			return err
+		}
+		limits = parsed
+		return nil
+	})
+	return limits
+}
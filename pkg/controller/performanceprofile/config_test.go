@@ -0,0 +1,75 @@
+package performanceprofile
+
+import (
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+)
+
+var _ = Describe("Config", func() {
+	envs := []string{machineConfigPoolCreationEnv, rollbackTimeoutEnv, machineConfigBackupEnv, hugepagesCountLimitsEnv}
+	var previous map[string]string
+
+	setEnv := func(name string, value string) {
+		Expect(os.Setenv(name, value)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		previous = map[string]string{}
+		for _, name := range envs {
+			if value, ok := os.LookupEnv(name); ok {
+				previous[name] = value
+			}
+		}
+	})
+
+	AfterEach(func() {
+		for _, name := range envs {
+			if value, ok := previous[name]; ok {
+				Expect(os.Setenv(name, value)).To(Succeed())
+				continue
+			}
+			Expect(os.Unsetenv(name)).To(Succeed())
+		}
+	})
+
+	It("should use the default configuration without environment variables", func() {
+		setEnv(machineConfigPoolCreationEnv, "")
+		setEnv(rollbackTimeoutEnv, "")
+		setEnv(machineConfigBackupEnv, "")
+		setEnv(hugepagesCountLimitsEnv, "")
+
+		Expect(getMachineConfigPoolCreation()).To(BeFalse())
+		Expect(getRollbackTimeout()).To(BeZero())
+		Expect(getMachineConfigBackup()).To(BeFalse())
+		Expect(getHugepagesCountLimits()).To(BeNil())
+	})
+
+	It("should parse the environment variables", func() {
+		setEnv(machineConfigPoolCreationEnv, "true")
+		setEnv(rollbackTimeoutEnv, "10m")
+		setEnv(machineConfigBackupEnv, "1")
+		setEnv(hugepagesCountLimitsEnv, "1G=256")
+
+		Expect(getMachineConfigPoolCreation()).To(BeTrue())
+		Expect(getRollbackTimeout()).To(Equal(10 * time.Minute))
+		Expect(getMachineConfigBackup()).To(BeTrue())
+		Expect(getHugepagesCountLimits()).To(Equal(map[performancev1.HugePageSize]int32{"1G": 256}))
+	})
+
+	It("should fall back to the default configuration on invalid values", func() {
+		setEnv(machineConfigPoolCreationEnv, "sure")
+		setEnv(rollbackTimeoutEnv, "10")
+		setEnv(machineConfigBackupEnv, "yes")
+		setEnv(hugepagesCountLimitsEnv, "3M=10")
+
+		Expect(getMachineConfigPoolCreation()).To(BeFalse())
+		Expect(getRollbackTimeout()).To(BeZero())
+		Expect(getMachineConfigBackup()).To(BeFalse())
+		Expect(getHugepagesCountLimits()).To(BeNil())
+	})
+})
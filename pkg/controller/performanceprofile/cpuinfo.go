@@ -0,0 +1,42 @@
+package performanceprofile
+
+import (
+	"context"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	profileutil "github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/profile"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cpuVendorLabel is the node feature discovery label that contains the CPU vendor of the node
+const cpuVendorLabel = "feature.node.kubernetes.io/cpu-model.vendor_id"
+
+// nodeLabelsCPUInfoProvider provides CPU information from node feature discovery labels of the profile nodes
+type nodeLabelsCPUInfoProvider struct {
+	client client.Client
+}
+
+// GetCPUInfo returns CPU information of nodes selected by the profile node selector,
+// nodes without node feature discovery labels are omitted
+func (p *nodeLabelsCPUInfoProvider) GetCPUInfo(profile *performancev1.PerformanceProfile) ([]profileutil.CPUInfo, error) {
+	nodes := &corev1.NodeList{}
+	if err := p.client.List(context.TODO(), nodes, client.MatchingLabels(profile.Spec.NodeSelector)); err != nil {
+		return nil, err
+	}
+
+	var cpuInfos []profileutil.CPUInfo
+	for _, node := range nodes.Items {
+		vendorID, ok := node.Labels[cpuVendorLabel]
+		if !ok {
+			continue
+		}
+		cpuInfos = append(cpuInfos, profileutil.CPUInfo{
+			NodeName: node.Name,
+			VendorID: vendorID,
+		})
+	}
+	return cpuInfos, nil
+}
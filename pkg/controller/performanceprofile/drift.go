@@ -0,0 +1,92 @@
+package performanceprofile
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/kubeletconfig"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/machineconfig"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/tuned"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// detectDrift compares objects generated for the performance profile with objects under the cluster,
+// it returns the description of every object that differs from the desired state without updating it
+func (r *ReconcilePerformanceProfile) detectDrift(profile *performancev1.PerformanceProfile) ([]string, error) {
+	var drifts []string
+
+	mc, err := machineconfig.New(r.assetsDir, profile)
+	if err != nil {
+		return nil, err
+	}
+	existingMC, err := r.getMachineConfig(mc.Name)
+	if errors.IsNotFound(err) {
+		drifts = append(drifts, fmt.Sprintf("MachineConfig %q is missing", mc.Name))
+	} else if err != nil {
+		return nil, err
+	} else if fields := getMachineConfigDriftedFields(existingMC, mc); len(fields) > 0 {
+		drifts = append(drifts, fmt.Sprintf("MachineConfig %q differs in %s", mc.Name, strings.Join(fields, ", ")))
+	}
+
+	kc, err := kubeletconfig.New(profile)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.getKubeletConfig(kc.Name); errors.IsNotFound(err) {
+		drifts = append(drifts, fmt.Sprintf("KubeletConfig %q is missing", kc.Name))
+	} else if err != nil {
+		return nil, err
+	} else if kcMutated, err := r.getMutatedKubeletConfig(kc); err != nil {
+		return nil, err
+	} else if kcMutated != nil {
+		drifts = append(drifts, fmt.Sprintf("KubeletConfig %q differs from the desired state", kc.Name))
+	}
+
+	performanceTuned, err := tuned.NewNodePerformance(r.assetsDir, profile)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.getTuned(performanceTuned.Name, performanceTuned.Namespace); errors.IsNotFound(err) {
+		drifts = append(drifts, fmt.Sprintf("Tuned %q is missing", performanceTuned.Name))
+	} else if err != nil {
+		return nil, err
+	} else if tunedMutated, err := r.getMutatedTuned(performanceTuned); err != nil {
+		return nil, err
+	} else if tunedMutated != nil {
+		drifts = append(drifts, fmt.Sprintf("Tuned %q differs from the desired state", performanceTuned.Name))
+	}
+
+	return drifts, nil
+}
+
+// getMachineConfigDriftedFields returns names of the machine config fields that differ from the desired ones
+func getMachineConfigDriftedFields(existing *mcov1.MachineConfig, desired *mcov1.MachineConfig) []string {
+	var fields []string
+	if existing.Spec.KernelType != desired.Spec.KernelType {
+		fields = append(fields, "kernelType")
+	}
+	if !reflect.DeepEqual(existing.Spec.KernelArguments, desired.Spec.KernelArguments) {
+		fields = append(fields, "kernelArguments")
+	}
+	if !bytes.Equal(existing.Spec.Config.Raw, desired.Spec.Config.Raw) {
+		fields = append(fields, "config")
+	}
+	if existing.Spec.FIPS != desired.Spec.FIPS {
+		fields = append(fields, "fips")
+	}
+	if existing.Spec.OSImageURL != desired.Spec.OSImageURL {
+		fields = append(fields, "osImageURL")
+	}
+	for key, value := range desired.Labels {
+		if existing.Labels[key] != value {
+			fields = append(fields, "labels")
+			break
+		}
+	}
+	return fields
+}
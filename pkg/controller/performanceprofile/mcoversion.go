@@ -0,0 +1,43 @@
+package performanceprofile
+
+import (
+	"context"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// mcoClusterOperatorName is the name of the machine config operator ClusterOperator
+	mcoClusterOperatorName = "machine-config"
+	// operatorVersionName is the name of the ClusterOperator version entry that keeps the operator version
+	operatorVersionName = "operator"
+)
+
+// clusterOperatorMCOVersionProvider provides the machine config operator version from its ClusterOperator status
+type clusterOperatorMCOVersionProvider struct {
+	client client.Reader
+}
+
+// GetMCOVersion returns the operator version reported by the machine config operator ClusterOperator,
+// the version is unknown when the ClusterOperator is missing or does not report it yet
+func (p *clusterOperatorMCOVersionProvider) GetMCOVersion() (string, error) {
+	co := &configv1.ClusterOperator{}
+	if err := p.client.Get(context.TODO(), types.NamespacedName{Name: mcoClusterOperatorName}, co); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	for _, version := range co.Status.Versions {
+		if version.Name == operatorVersionName {
+			return version.Version, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,99 @@
+package performanceprofile
+
+import (
+	"sync"
+	"time"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	"github.com/prometheus/client_golang/prometheus"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const metricLabelProfile = "profile"
+
+// profileApplyDuration measures the time from the moment the performance profile components were applied
+// until all machine config pools of the profile are updated
+var profileApplyDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "performance_profile_apply_duration_seconds",
+		Help:    "Time from applying the performance profile components until the matching machine config pools are updated.",
+		Buckets: prometheus.ExponentialBuckets(30, 2, 10),
+	},
+	[]string{metricLabelProfile},
+)
+
+func init() {
+	metrics.Registry.MustRegister(profileApplyDuration)
+}
+
+// applyTimeTracker keeps the time when the performance profile components were applied,
+// until the matching machine config pools report that they are updated
+type applyTimeTracker struct {
+	lock   sync.Mutex
+	starts map[string]time.Time
+}
+
+func newApplyTimeTracker() *applyTimeTracker {
+	return &applyTimeTracker{
+		starts: map[string]time.Time{},
+	}
+}
+
+// start records the apply time of the profile, when the previous apply did not converge yet
+// we keep the original start time
+func (t *applyTimeTracker) start(profileName string, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.starts[profileName]; !ok {
+		t.starts[profileName] = now
+	}
+}
+
+// observe completes the profile apply duration measurement once all machine config pools
+// were updated after the apply, the profile that never converges will not have an observation
+func (t *applyTimeTracker) observe(profile *performancev1.PerformanceProfile, mcps []mcov1.MachineConfigPool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	start, ok := t.starts[profile.Name]
+	if !ok || len(mcps) == 0 {
+		return
+	}
+
+	var updated time.Time
+	for _, mcp := range mcps {
+		condition := getMCPCondition(&mcp, mcov1.MachineConfigPoolUpdated)
+		// the machine config pool can still report the updated condition from the previous configuration
+		if condition == nil || condition.Status != corev1.ConditionTrue || condition.LastTransitionTime.Time.Before(start) {
+			return
+		}
+
+		if condition.LastTransitionTime.Time.After(updated) {
+			updated = condition.LastTransitionTime.Time
+		}
+	}
+
+	profileApplyDuration.WithLabelValues(profile.Name).Observe(updated.Sub(start).Seconds())
+	delete(t.starts, profile.Name)
+}
+
+// forget removes the profile apply start time
+func (t *applyTimeTracker) forget(profileName string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.starts, profileName)
+}
+
+func getMCPCondition(mcp *mcov1.MachineConfigPool, conditionType mcov1.MachineConfigPoolConditionType) *mcov1.MachineConfigPoolCondition {
+	for i := range mcp.Status.Conditions {
+		if mcp.Status.Conditions[i].Type == conditionType {
+			return &mcp.Status.Conditions[i]
+		}
+	}
+	return nil
+}
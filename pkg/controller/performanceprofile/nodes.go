@@ -0,0 +1,124 @@
+package performanceprofile
+
+import (
+	"context"
+	"reflect"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/tuningdaemon"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// syncNodeAnnotations applies the tuning configuration annotations to nodes selected by the profile node selector
+// and removes stale tuning annotations, nodes are patched, because the kubelet updates them all the time
+func (r *ReconcilePerformanceProfile) syncNodeAnnotations(profile *performancev1.PerformanceProfile) error {
+	annotations, err := tuningdaemon.GetNodeAnnotations(profile)
+	if err != nil {
+		return err
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.client.List(context.TODO(), nodes, client.MatchingLabels(profile.Spec.NodeSelector)); err != nil {
+		return err
+	}
+
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if reflect.DeepEqual(getNodeTuningAnnotations(node), annotations) {
+			continue
+		}
+
+		patch := client.MergeFrom(node.DeepCopy())
+		for key := range node.Annotations {
+			if tuningdaemon.IsNodeAnnotation(key) {
+				delete(node.Annotations, key)
+			}
+		}
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		for key, value := range annotations {
+			node.Annotations[key] = value
+		}
+
+		klog.Infof("Update the node %q tuning annotations of the performance profile %q", node.Name, profile.Name)
+		if err := r.client.Patch(context.TODO(), node, patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncNodeLabels labels nodes selected by the profile node selector with the profile name when enabled
+// and removes the label from nodes that the profile does not select anymore, the disabled sync removes the label
+// from all nodes, e.g. once the profile is deleted
+func (r *ReconcilePerformanceProfile) syncNodeLabels(profile *performancev1.PerformanceProfile, enabled bool) error {
+	selected := map[string]bool{}
+	if enabled {
+		nodes := &corev1.NodeList{}
+		if err := r.client.List(context.TODO(), nodes, client.MatchingLabels(profile.Spec.NodeSelector)); err != nil {
+			return err
+		}
+
+		for i := range nodes.Items {
+			node := &nodes.Items[i]
+			selected[node.Name] = true
+
+			value, ok := node.Labels[performancev1.PerformanceProfileNodeLabel]
+			if value == profile.Name {
+				continue
+			}
+			// the node selected by several profiles keeps the first label, otherwise profiles overwrite it endlessly
+			if ok {
+				klog.Warningf("The node %q is already labeled by the performance profile %q, skip labeling it by the performance profile %q", node.Name, value, profile.Name)
+				continue
+			}
+
+			patch := client.MergeFrom(node.DeepCopy())
+			if node.Labels == nil {
+				node.Labels = map[string]string{}
+			}
+			node.Labels[performancev1.PerformanceProfileNodeLabel] = profile.Name
+
+			klog.Infof("Label the node %q by the performance profile %q", node.Name, profile.Name)
+			if err := r.client.Patch(context.TODO(), node, patch); err != nil {
+				return err
+			}
+		}
+	}
+
+	labeledNodes := &corev1.NodeList{}
+	if err := r.client.List(context.TODO(), labeledNodes, client.MatchingLabels{performancev1.PerformanceProfileNodeLabel: profile.Name}); err != nil {
+		return err
+	}
+
+	for i := range labeledNodes.Items {
+		node := &labeledNodes.Items[i]
+		if selected[node.Name] {
+			continue
+		}
+
+		patch := client.MergeFrom(node.DeepCopy())
+		delete(node.Labels, performancev1.PerformanceProfileNodeLabel)
+
+		klog.Infof("Remove the performance profile %q label from the node %q", profile.Name, node.Name)
+		if err := r.client.Patch(context.TODO(), node, patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getNodeTuningAnnotations(node *corev1.Node) map[string]string {
+	annotations := map[string]string{}
+	for key, value := range node.Annotations {
+		if tuningdaemon.IsNodeAnnotation(key) {
+			annotations[key] = value
+		}
+	}
+	return annotations
+}
@@ -0,0 +1,77 @@
+package performanceprofile
+
+import (
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/containerruntimeconfig"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/priorityclass"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	nodev1beta1 "k8s.io/api/node/v1beta1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ObjectRef references the object generated for the performance profile
+type ObjectRef struct {
+	GroupVersionKind schema.GroupVersionKind
+	Namespace        string
+	Name             string
+}
+
+// OwnedObjectRefs returns references to all objects the operator generates for the performance profile.
+// The machine config pool and the tuning daemon set are created only when the cluster does not have the pool
+// and the operator has the tuning daemon image, but they are always returned, because the profile can still own them.
+// NOTE: the list should be updated once the operator generates a new component.
+func OwnedObjectRefs(profile *performancev1.PerformanceProfile) []ObjectRef {
+	name := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+
+	refs := []ObjectRef{
+		{
+			GroupVersionKind: mcov1.GroupVersion.WithKind("MachineConfig"),
+			Name:             name,
+		},
+		{
+			GroupVersionKind: mcov1.GroupVersion.WithKind("MachineConfigPool"),
+			Name:             name,
+		},
+		{
+			GroupVersionKind: mcov1.GroupVersion.WithKind("KubeletConfig"),
+			Name:             name,
+		},
+	}
+
+	if containerruntimeconfig.IsEnabled(profile) {
+		refs = append(refs, ObjectRef{
+			GroupVersionKind: mcov1.GroupVersion.WithKind("ContainerRuntimeConfig"),
+			Name:             name,
+		})
+	}
+
+	refs = append(refs,
+		ObjectRef{
+			GroupVersionKind: tunedv1.SchemeGroupVersion.WithKind("Tuned"),
+			Namespace:        components.NamespaceNodeTuningOperator,
+			Name:             components.GetComponentName(profile.Name, components.ProfileNamePerformance),
+		},
+		ObjectRef{
+			GroupVersionKind: nodev1beta1.SchemeGroupVersion.WithKind("RuntimeClass"),
+			Name:             name,
+		},
+	)
+
+	if priorityclass.IsEnabled(profile) {
+		refs = append(refs, ObjectRef{
+			GroupVersionKind: schedulingv1.SchemeGroupVersion.WithKind("PriorityClass"),
+			Name:             name,
+		})
+	}
+
+	return append(refs, ObjectRef{
+		GroupVersionKind: appsv1.SchemeGroupVersion.WithKind("DaemonSet"),
+		Namespace:        components.NamespaceNodeTuningOperator,
+		Name:             name,
+	})
+}
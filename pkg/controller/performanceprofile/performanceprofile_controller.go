@@ -2,21 +2,28 @@ package performanceprofile
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/containerruntimeconfig"
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/kubeletconfig"
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/machineconfig"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/priorityclass"
 	profileutil "github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/profile"
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/runtimeclass"
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/tuned"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/tuningdaemon"
 	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
 	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	nodev1beta1 "k8s.io/api/node/v1beta1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -53,10 +60,20 @@ func Add(mgr manager.Manager) error {
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) *ReconcilePerformanceProfile {
 	return &ReconcilePerformanceProfile{
-		client:    mgr.GetClient(),
-		scheme:    mgr.GetScheme(),
-		recorder:  mgr.GetEventRecorderFor("performance-profile-controller"),
-		assetsDir: components.AssetsDir,
+		client:               mgr.GetClient(),
+		scheme:               mgr.GetScheme(),
+		recorder:             mgr.GetEventRecorderFor("performance-profile-controller"),
+		assetsDir:            components.AssetsDir,
+		rollbackTimeout:      getRollbackTimeout(),
+		machineConfigBackup:  getMachineConfigBackup(),
+		applyTimeTracker:     newApplyTimeTracker(),
+		tuningDaemonImage:    getTuningDaemonImage(),
+		mcpCreation:          getMachineConfigPoolCreation(),
+		hugepagesCountLimits: getHugepagesCountLimits(),
+		cpuInfoProvider:      &nodeLabelsCPUInfoProvider{client: mgr.GetClient()},
+		topologyProvider:     &nodeTopologyProvider{client: mgr.GetClient()},
+		podLister:            &nodesPodLister{client: mgr.GetAPIReader()},
+		mcoVersionProvider:   &clusterOperatorMCOVersionProvider{client: mgr.GetAPIReader()},
 	}
 }
 
@@ -117,6 +134,15 @@ func add(mgr manager.Manager, r *ReconcilePerformanceProfile) error {
 		return err
 	}
 
+	// Watch for changes to container runtime configs owned by our controller
+	err = c.Watch(&source.Kind{Type: &mcov1.ContainerRuntimeConfig{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &performancev1.PerformanceProfile{},
+	}, p)
+	if err != nil {
+		return err
+	}
+
 	// Watch for changes to tuned owned by our controller
 	err = c.Watch(&source.Kind{Type: &tunedv1.Tuned{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
@@ -135,6 +161,24 @@ func add(mgr manager.Manager, r *ReconcilePerformanceProfile) error {
 		return err
 	}
 
+	// Watch for changes for the PriorityClass owned by our resource
+	err = c.Watch(&source.Kind{Type: &schedulingv1.PriorityClass{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &performancev1.PerformanceProfile{},
+	}, p)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes for the tuning daemon DaemonSet owned by our resource
+	err = c.Watch(&source.Kind{Type: &appsv1.DaemonSet{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &performancev1.PerformanceProfile{},
+	}, p)
+	if err != nil {
+		return err
+	}
+
 	mcpPredicates := predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			if e.MetaOld == nil {
@@ -170,6 +214,18 @@ func add(mgr manager.Manager, r *ReconcilePerformanceProfile) error {
 		return err
 	}
 
+	// the full reconcile loop runs only once machine config pool conditions change, the status sync controller
+	// refreshes the profile status on any machine config pool status change, e.g. the updated machines count
+	statusController, err := controller.New("performanceprofile-status-controller", mgr, controller.Options{Reconciler: &statusSyncReconciler{r: r}})
+	if err != nil {
+		return err
+	}
+
+	err = statusController.Watch(&source.Kind{Type: &mcov1.MachineConfigPool{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: (handler.ToRequestsFunc)(r.ppRequestsFromMCP)}, predicate.Funcs{UpdateFunc: isMCPStatusChanged})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -184,6 +240,29 @@ type ReconcilePerformanceProfile struct {
 	scheme    *runtime.Scheme
 	recorder  record.EventRecorder
 	assetsDir string
+	// rollbackTimeout is the duration a machine config pool can stay degraded before the machine config
+	// is rolled back to the previous configuration, zero value disables the rollback
+	rollbackTimeout time.Duration
+	// machineConfigBackup enables the backup of the previous machine config spec on update
+	machineConfigBackup bool
+	// applyTimeTracker measures the time it takes to machine config pools to apply the performance profile
+	applyTimeTracker *applyTimeTracker
+	// tuningDaemonImage is the image of the runtime tuning daemon, empty value disables the tuning daemon
+	tuningDaemonImage string
+	// mcpCreation allows the operator to create the machine config pool for the profile nodes
+	mcpCreation bool
+	// hugepagesCountLimits holds the maximum count of huge pages of each size a profile can request
+	hugepagesCountLimits map[performancev1.HugePageSize]int32
+	// cpuInfoProvider provides CPU information of the profile nodes, nil value disables the CPU features validation
+	cpuInfoProvider profileutil.CPUInfoProvider
+	// topologyProvider provides the topology of the profile nodes, nil value disables the huge pages NUMA nodes,
+	// the huge pages NUMA affinity, the SMT siblings and the isolated CPUs percentage validation
+	topologyProvider profileutil.TopologyProvider
+	// podLister lists pods of the profile nodes, nil value disables the isolation reduction validation
+	podLister profileutil.PodLister
+	// mcoVersionProvider provides the machine config operator version, nil value disables the validation
+	// of profile features against the machine config operator version
+	mcoVersionProvider profileutil.MCOVersionProvider
 }
 
 // Reconcile reads that state of the cluster for a PerformanceProfile object and makes changes based on the state read
@@ -216,6 +295,7 @@ func (r *ReconcilePerformanceProfile) Reconcile(request reconcile.Request) (reco
 			return reconcile.Result{}, err
 		}
 		r.recorder.Eventf(instance, corev1.EventTypeNormal, "Deletion succeeded", "Succeeded to delete all components")
+		r.applyTimeTracker.forget(instance.Name)
 
 		if r.isComponentsExist(instance) {
 			return reconcile.Result{RequeueAfter: 10 * time.Second}, nil
@@ -247,11 +327,11 @@ func (r *ReconcilePerformanceProfile) Reconcile(request reconcile.Request) (reco
 	// TODO: we need to check if all under performance profiles values != nil
 	// first we need to decide if each of values required and we should move the check into validation webhook
 	// for now let's assume that all parameters needed for assets scrips are required
-	if err := profileutil.ValidateParameters(instance); err != nil {
+	if err := r.validateProfile(instance); err != nil {
 		klog.Errorf("failed to reconcile: %v", err)
 		r.recorder.Eventf(instance, corev1.EventTypeWarning, "Validation failed", "Profile validation failed: %v", err)
 		conditions := r.getDegradedConditions(conditionReasonValidationFailed, err.Error())
-		if err := r.updateStatus(instance, conditions); err != nil {
+		if err := r.updateStatus(instance, conditions, nil); err != nil {
 			klog.Errorf("failed to update performance profile %q status: %v", instance.Name, err)
 			return reconcile.Result{}, err
 		}
@@ -259,34 +339,109 @@ func (r *ReconcilePerformanceProfile) Reconcile(request reconcile.Request) (reco
 		return reconcile.Result{}, nil
 	}
 
+	// report drifted components without updating them, so a user can investigate manual changes
+	if profileutil.IsDriftDetectionOnly(instance) {
+		drifts, err := r.detectDrift(instance)
+		if err != nil {
+			klog.Errorf("failed to detect performance profile %q drift: %v", instance.Name, err)
+			return reconcile.Result{}, err
+		}
+		conditions := r.getDriftConditions(drifts)
+		if err := r.updateStatus(instance, conditions, nil); err != nil {
+			klog.Errorf("failed to update performance profile %q status: %v", instance.Name, err)
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	// roll back the machine config when the machine config pool failed to apply it
+	rollbackMessage, rollbackRequeueAfter, err := r.rollbackMachineConfig(instance)
+	if err != nil {
+		klog.Errorf("failed to roll back performance profile %q machine config: %v", instance.Name, err)
+		return reconcile.Result{}, err
+	}
+	if rollbackMessage != "" {
+		conditions := r.getDegradedConditions(conditionReasonMachineConfigRolledBack, rollbackMessage)
+		if err := r.updateStatus(instance, conditions, nil); err != nil {
+			klog.Errorf("failed to update performance profile %q status: %v", instance.Name, err)
+			return reconcile.Result{}, err
+		}
+		// we do not want to apply the same configuration again, a user will need to update the PerformanceProfile
+		return reconcile.Result{}, nil
+	}
+
+	// the kernel type change reboots nodes, so it should not interrupt the update of machine config pools,
+	// the update of machine config pools triggers the reconcile loop once they apply the current machine config
+	if !profileutil.IsPaused(instance) {
+		message, err := r.getPendingKernelTypeTransition(instance)
+		if err != nil {
+			klog.Errorf("failed to check performance profile %q kernel type transition: %v", instance.Name, err)
+			return reconcile.Result{}, err
+		}
+		if message != "" {
+			klog.Infof("The performance profile %s: %s", instance.Name, message)
+			conditions := r.getProgressingConditions(conditionReasonKernelTypeTransitionPending, message)
+			if err := r.updateStatus(instance, conditions, nil); err != nil {
+				klog.Errorf("failed to update performance profile %q status: %v", instance.Name, err)
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{RequeueAfter: rollbackRequeueAfter}, nil
+		}
+	}
+
 	// apply components
 	result, err := r.applyComponents(instance)
 	if err != nil {
 		klog.Errorf("failed to deploy performance profile %q components: %v", instance.Name, err)
 		r.recorder.Eventf(instance, corev1.EventTypeWarning, "Creation failed", "Failed to create all components: %v", err)
 		conditions := r.getDegradedConditions(conditionReasonComponentsCreationFailed, err.Error())
-		if err := r.updateStatus(instance, conditions); err != nil {
+		if err := r.updateStatus(instance, conditions, nil); err != nil {
 			klog.Errorf("failed to update performance profile %q status: %v", instance.Name, err)
 			return reconcile.Result{}, err
 		}
 		return reconcile.Result{}, err
 	}
 
-	conditions, err := r.getMCPConditionsByProfile(instance)
+	// the forced update was applied, so we should not force it again
+	if err := r.removeForceSyncAnnotation(instance); err != nil {
+		klog.Errorf("failed to remove performance profile %q force sync annotation: %v", instance.Name, err)
+		return reconcile.Result{}, err
+	}
+
+	// unpause machine config pools paused for the coordinated rollout once performance profile changes settled
+	requeueAfter, err := r.unpauseSettledMachineConfigPools(instance, time.Now())
+	if err != nil {
+		klog.Errorf("failed to unpause performance profile %q machine config pools: %v", instance.Name, err)
+		return reconcile.Result{}, err
+	}
+	if requeueAfter > 0 && (result == nil || result.RequeueAfter == 0) {
+		result = &reconcile.Result{RequeueAfter: requeueAfter}
+	}
+
+	// check degraded machine config pools again once the rollback timeout expires
+	if rollbackRequeueAfter > 0 && (result == nil || result.RequeueAfter == 0 || rollbackRequeueAfter < result.RequeueAfter) {
+		result = &reconcile.Result{RequeueAfter: rollbackRequeueAfter}
+	}
+
+	mcps, err := r.getMachineConfigPoolsByProfile(instance)
 	if err != nil {
 		conditions := r.getDegradedConditions(conditionFailedGettingMCPStatus, err.Error())
-		if err := r.updateStatus(instance, conditions); err != nil {
+		if err := r.updateStatus(instance, conditions, nil); err != nil {
 			klog.Errorf("failed to update performance profile %q status: %v", instance.Name, err)
 			return reconcile.Result{}, err
 		}
 		return reconcile.Result{}, err
 	}
 
+	r.applyTimeTracker.observe(instance, mcps)
+
 	// if conditions were not added due to machine config pool status change then set as availble
+	conditions := r.getMCPConditions(mcps)
 	if conditions == nil {
 		conditions = r.getAvailableConditions()
 	}
-	if err := r.updateStatus(instance, conditions); err != nil {
+	rebootRequired := isRebootRequired(instance, mcps)
+	if err := r.updateStatus(instance, conditions, &rebootRequired); err != nil {
 		klog.Errorf("failed to update performance profile %q status: %v", instance.Name, err)
 		// we still want to requeue after some, also in case of error, to avoid chance of multiple reboots
 		if result != nil {
@@ -354,6 +509,19 @@ func (r *ReconcilePerformanceProfile) applyComponents(profile *performancev1.Per
 		return nil, err
 	}
 
+	// get mutated architecture specific machine configs of the mixed architecture cluster
+	archMCsMutated, err := r.getMutatedArchitectureMachineConfigs(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	// get mutated machine config pool, the pool is created only when the creation is enabled
+	// and the cluster does not have one for the profile
+	mcpMutated, err := r.getMutatedMachineConfigPool(profile)
+	if err != nil {
+		return nil, err
+	}
+
 	// get mutated kubelet config
 	kc, err := kubeletconfig.New(profile)
 	if err != nil {
@@ -367,6 +535,22 @@ func (r *ReconcilePerformanceProfile) applyComponents(profile *performancev1.Per
 		return nil, err
 	}
 
+	// get mutated container runtime config
+	var crcMutated *mcov1.ContainerRuntimeConfig
+	crcName := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+	if containerruntimeconfig.IsEnabled(profile) {
+		crc := containerruntimeconfig.New(profile)
+		if err := controllerutil.SetControllerReference(profile, crc, r.scheme); err != nil {
+			return nil, err
+		}
+		crcMutated, err = r.getMutatedContainerRuntimeConfig(crc)
+		if err != nil {
+			return nil, err
+		}
+	} else if err := r.deleteContainerRuntimeConfig(crcName); err != nil {
+		return nil, err
+	}
+
 	// get mutated performance tuned
 	performanceTuned, err := tuned.NewNodePerformance(r.assetsDir, profile)
 	if err != nil {
@@ -380,6 +564,11 @@ func (r *ReconcilePerformanceProfile) applyComponents(profile *performancev1.Per
 	if err != nil {
 		return nil, err
 	}
+	if performanceTunedMutated != nil {
+		if err := r.warnCgroupModeChange(profile, performanceTuned); err != nil {
+			return nil, err
+		}
+	}
 
 	// get mutated RuntimeClass
 	runtimeClass := runtimeclass.New(profile, machineconfig.HighPerformanceRuntime)
@@ -391,44 +580,421 @@ func (r *ReconcilePerformanceProfile) applyComponents(profile *performancev1.Per
 		return nil, err
 	}
 
+	// get mutated PriorityClass
+	var priorityClassMutated *schedulingv1.PriorityClass
+	priorityClassName := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+	if priorityclass.IsEnabled(profile) {
+		priorityClass := priorityclass.New(profile)
+		if err := controllerutil.SetControllerReference(profile, priorityClass, r.scheme); err != nil {
+			return nil, err
+		}
+		priorityClassMutated, err = r.getMutatedPriorityClass(priorityClass)
+		if err != nil {
+			return nil, err
+		}
+	} else if err := r.deletePriorityClass(priorityClassName); err != nil {
+		return nil, err
+	}
+
+	// get mutated tuning daemon DaemonSet
+	var tuningDaemonMutated *appsv1.DaemonSet
+	tuningDaemonName := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+	if r.tuningDaemonImage != "" {
+		tuningDaemon, err := tuningdaemon.New(profile, r.tuningDaemonImage)
+		if err != nil {
+			return nil, err
+		}
+		if err := controllerutil.SetControllerReference(profile, tuningDaemon, r.scheme); err != nil {
+			return nil, err
+		}
+		tuningDaemonMutated, err = r.getMutatedDaemonSet(tuningDaemon)
+		if err != nil {
+			return nil, err
+		}
+	} else if err := r.deleteDaemonSet(tuningDaemonName, components.NamespaceNodeTuningOperator); err != nil {
+		return nil, err
+	}
+
+	// the tuning daemon reads the configuration from node annotations, nodes can join the pool at any time,
+	// so annotations are synced on each reconcile and do not depend on other components changes
+	if r.tuningDaemonImage != "" {
+		if err := r.syncNodeAnnotations(profile); err != nil {
+			return nil, err
+		}
+	}
+
+	// the node label is synced on each reconcile as well, so nodes that join or leave the pool are handled
+	if err := r.syncNodeLabels(profile, profileutil.IsNodeLabelEnabled(profile)); err != nil {
+		return nil, err
+	}
+
 	updated := mcMutated != nil ||
+		len(archMCsMutated) > 0 ||
+		mcpMutated != nil ||
 		kcMutated != nil ||
+		crcMutated != nil ||
 		performanceTunedMutated != nil ||
-		runtimeClassMutated != nil
+		runtimeClassMutated != nil ||
+		priorityClassMutated != nil ||
+		tuningDaemonMutated != nil
 
 	// does not update any resources, if it no changes to relevant objects and just continue to the status update
 	if !updated {
 		return nil, nil
 	}
 
+	// the machine config daemon reboots nodes to apply kernel and machine config changes,
+	// so we should get reasons before we update objects
+	rebootReasons, err := r.getRebootReasons(mcMutated, performanceTunedMutated)
+	if err != nil {
+		return nil, err
+	}
+
+	// the new version of the operator can stop generating some kernel arguments, nodes carry them
+	// until the updated tuned is applied, so users should know why they disappear
+	removedKernelArgs, err := r.getRemovedKernelArgs(performanceTunedMutated)
+	if err != nil {
+		return nil, err
+	}
+
+	// the machine config pool with the coordinated rollout should not apply changes until all of them are in place
+	if err := r.pauseMachineConfigPools(profile, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// the tuned follows the machine config immediately, both depend on isolated CPUs
 	if mcMutated != nil {
 		if err := r.createOrUpdateMachineConfig(mcMutated); err != nil {
 			return nil, err
 		}
 	}
 
+	for _, archMC := range archMCsMutated {
+		if err := r.createOrUpdateMachineConfig(archMC); err != nil {
+			return nil, err
+		}
+	}
+
 	if performanceTunedMutated != nil {
 		if err := r.createOrUpdateTuned(performanceTunedMutated, profile.Name); err != nil {
 			return nil, err
 		}
 	}
 
+	// the tuned lagging behind the machine config would isolate other CPUs than the machine config expects,
+	// the failed reconcile loop is requeued and writes both objects again
+	if err := r.validateIsolatedCPUsSync(mc.Name, performanceTuned.Name, performanceTuned.Namespace); err != nil {
+		return nil, err
+	}
+
+	if mcpMutated != nil {
+		if err := r.createOrUpdateMachineConfigPool(mcpMutated); err != nil {
+			return nil, err
+		}
+	}
+
 	if kcMutated != nil {
 		if err := r.createOrUpdateKubeletConfig(kcMutated); err != nil {
 			return nil, err
 		}
 	}
 
+	if crcMutated != nil {
+		if err := r.createOrUpdateContainerRuntimeConfig(crcMutated); err != nil {
+			return nil, err
+		}
+	}
+
 	if runtimeClassMutated != nil {
 		if err := r.createOrUpdateRuntimeClass(runtimeClassMutated); err != nil {
 			return nil, err
 		}
 	}
 
-	r.recorder.Eventf(profile, corev1.EventTypeNormal, "Creation succeeded", "Succeeded to create all components")
+	if priorityClassMutated != nil {
+		if err := r.createOrUpdatePriorityClass(priorityClassMutated); err != nil {
+			return nil, err
+		}
+	}
+
+	if tuningDaemonMutated != nil {
+		if err := r.createOrUpdateDaemonSet(tuningDaemonMutated); err != nil {
+			return nil, err
+		}
+	}
+
+	r.applyTimeTracker.start(profile.Name, time.Now())
+
+	if len(rebootReasons) > 0 {
+		klog.Infof("The performance profile %s triggers the reboot of the nodes: %s", profile.Name, strings.Join(rebootReasons, ", "))
+		r.recorder.Eventf(profile, corev1.EventTypeNormal, "RebootTriggered", "The nodes will be rebooted to apply the %s", strings.Join(rebootReasons, ", "))
+	}
+
+	if len(removedKernelArgs) > 0 {
+		klog.Infof("The performance profile %s no longer generates the kernel arguments: %s", profile.Name, strings.Join(removedKernelArgs, " "))
+		r.recorder.Eventf(profile, corev1.EventTypeNormal, "KernelArgumentsRemoved", "The kernel arguments %q are no longer generated and will be removed from the tuned %q", strings.Join(removedKernelArgs, " "), performanceTunedMutated.Name)
+	}
+
+	r.recorder.Eventf(profile, corev1.EventTypeNormal, "Creation succeeded", "Succeeded to create all components for %s", profileutil.Summarize(profile))
 	return &reconcile.Result{}, nil
 }
 
+// validateIsolatedCPUsSync verifies that the existing machine config and the existing tuned isolate the same CPUs,
+// missing objects are skipped
+func (r *ReconcilePerformanceProfile) validateIsolatedCPUsSync(mcName string, tunedName string, tunedNamespace string) error {
+	mc, err := r.getMachineConfig(mcName)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	performanceTuned, err := r.getTuned(tunedName, tunedNamespace)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	mcIsolated := mc.Annotations[components.IsolatedCPUsAnnotation]
+	tunedIsolated := performanceTuned.Annotations[components.IsolatedCPUsAnnotation]
+	if mcIsolated != tunedIsolated {
+		return fmt.Errorf("the machine config %q isolated CPUs %q differ from the tuned %q isolated CPUs %q", mc.Name, mcIsolated, performanceTuned.Name, tunedIsolated)
+	}
+	return nil
+}
+
+// validateProfile validates the profile parameters and, when providers are set, validates the profile
+// against CPUs, the topology and pods of the profile nodes and against the machine config operator version
+func (r *ReconcilePerformanceProfile) validateProfile(profile *performancev1.PerformanceProfile) error {
+	if err := profileutil.ValidateParameters(profile); err != nil {
+		return err
+	}
+
+	if err := machineconfig.ValidateProfileName(profile); err != nil {
+		return err
+	}
+
+	if err := profileutil.ValidateHugepagesCountLimits(profile, r.hugepagesCountLimits); err != nil {
+		return err
+	}
+
+	if r.cpuInfoProvider != nil {
+		if err := profileutil.ValidateCPUFeatures(profile, r.cpuInfoProvider); err != nil {
+			return err
+		}
+	}
+
+	if r.topologyProvider != nil {
+		if err := profileutil.ValidateHugepagesNUMANodes(profile, r.topologyProvider); err != nil {
+			return err
+		}
+		if err := profileutil.ValidateSMTSiblings(profile, r.topologyProvider); err != nil {
+			return err
+		}
+		if err := profileutil.ValidateIsolatedCPUsPercentage(profile, r.topologyProvider); err != nil {
+			return err
+		}
+		if err := profileutil.ValidateHugepagesNUMAAffinity(profile, r.topologyProvider); err != nil {
+			return err
+		}
+	}
+
+	if r.mcoVersionProvider != nil {
+		if err := profileutil.ValidateMCOVersion(profile, r.mcoVersionProvider); err != nil {
+			return err
+		}
+	}
+
+	if err := r.validateKernelTypeTransition(profile); err != nil {
+		return err
+	}
+
+	if r.podLister != nil {
+		previousIsolated, err := r.getAppliedIsolatedCPUs(profile)
+		if err != nil {
+			return err
+		}
+		if err := profileutil.ValidateIsolationReduction(profile, previousIsolated, r.podLister); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getAppliedIsolatedCPUs returns isolated CPUs of the existing performance tuned, that the profile nodes currently use
+func (r *ReconcilePerformanceProfile) getAppliedIsolatedCPUs(profile *performancev1.PerformanceProfile) (string, error) {
+	name := components.GetComponentName(profile.Name, components.ProfileNamePerformance)
+	existing, err := r.getTuned(name, components.NamespaceNodeTuningOperator)
+	if errors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return tuned.GetKernelCmdlineOptions(existing)[tuned.VariableIsolatedCores], nil
+}
+
+// getExistingKernelType returns the kernel type of the existing profile machine config,
+// the empty string is returned when the machine config does not exist yet
+func (r *ReconcilePerformanceProfile) getExistingKernelType(profile *performancev1.PerformanceProfile) (string, error) {
+	existing, err := r.getMachineConfig(components.GetComponentName(profile.Name, components.ComponentNamePrefix))
+	if errors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// the machine config operator installs the default kernel when the kernel type is not specified
+	if existing.Spec.KernelType == "" {
+		return machineconfig.MCKernelDefault, nil
+	}
+	return existing.Spec.KernelType, nil
+}
+
+// validateKernelTypeTransition validates that nodes can be switched from the kernel type of the existing
+// machine config to the kernel type of the profile
+func (r *ReconcilePerformanceProfile) validateKernelTypeTransition(profile *performancev1.PerformanceProfile) error {
+	existingKernelType, err := r.getExistingKernelType(profile)
+	if err != nil || existingKernelType == "" {
+		return err
+	}
+	return machineconfig.ValidateKernelTypeTransition(existingKernelType, machineconfig.GetKernelType(profile))
+}
+
+// getPendingKernelTypeTransition returns the message describing the kernel type transition that should wait
+// until machine config pools apply the current machine config, switching the kernel type in the middle
+// of the update leaves nodes with different kernels, that can not be reverted without manual steps on some platforms
+func (r *ReconcilePerformanceProfile) getPendingKernelTypeTransition(profile *performancev1.PerformanceProfile) (string, error) {
+	existingKernelType, err := r.getExistingKernelType(profile)
+	if err != nil {
+		return "", err
+	}
+
+	kernelType := machineconfig.GetKernelType(profile)
+	if existingKernelType == "" || existingKernelType == kernelType {
+		return "", nil
+	}
+
+	mcps, err := r.getMachineConfigPoolsByProfile(profile)
+	if err != nil {
+		return "", err
+	}
+	if !isRebootRequired(profile, mcps) {
+		return "", nil
+	}
+
+	return fmt.Sprintf("The kernel type change from %q to %q waits until machine config pools apply the current machine config", existingKernelType, kernelType), nil
+}
+
+// warnCgroupModeChange emits the warning event when the tuned changes the cgroup mode of the nodes,
+// the nodes should reboot to switch the cgroup hierarchy
+func (r *ReconcilePerformanceProfile) warnCgroupModeChange(profile *performancev1.PerformanceProfile, performanceTuned *tunedv1.Tuned) error {
+	existing, err := r.getTuned(performanceTuned.Name, performanceTuned.Namespace)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	existingArg := tuned.GetCgroupKernelArg(existing)
+	arg := tuned.GetCgroupKernelArg(performanceTuned)
+	if existingArg == arg {
+		return nil
+	}
+
+	klog.Warningf("The performance profile %s changes the cgroup mode, the nodes will be rebooted", profile.Name)
+	r.recorder.Eventf(profile, corev1.EventTypeWarning, "Cgroup mode changed", "Changing the cgroup mode from %q to %q forces the reboot of the nodes", existingArg, arg)
+	return nil
+}
+
+// getMutatedArchitectureMachineConfigs returns architecture specific machine configs that should be created or updated,
+// it deletes machine configs of architectures the profile does not target anymore and fails when the cluster
+// does not have the pool for one of the architectures
+func (r *ReconcilePerformanceProfile) getMutatedArchitectureMachineConfigs(profile *performancev1.PerformanceProfile) ([]*mcov1.MachineConfig, error) {
+	architectures := profileutil.GetArchitectures(profile)
+	mcs, err := machineconfig.NewForArchitectures(r.assetsDir, profile, architectures)
+	if err != nil {
+		return nil, err
+	}
+
+	name := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+	for architecture := range components.IOMMUKernelArgs {
+		if _, ok := mcs[architecture]; ok {
+			continue
+		}
+		if err := r.deleteMachineConfig(machineconfig.GetArchitectureName(name, architecture)); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(mcs) == 0 {
+		return nil, nil
+	}
+
+	mcpList := &mcov1.MachineConfigPoolList{}
+	if err := r.client.List(context.TODO(), mcpList); err != nil {
+		return nil, err
+	}
+	if err := machineconfig.ValidateArchitecturePools(mcs, mcpList.Items); err != nil {
+		return nil, err
+	}
+
+	var mutated []*mcov1.MachineConfig
+	for _, architecture := range architectures {
+		mc := mcs[architecture]
+		if err := controllerutil.SetControllerReference(profile, mc, r.scheme); err != nil {
+			return nil, err
+		}
+
+		mcMutated, err := r.getMutatedMachineConfig(mc)
+		if err != nil {
+			return nil, err
+		}
+		if mcMutated != nil {
+			mutated = append(mutated, mcMutated)
+		}
+	}
+	return mutated, nil
+}
+
+// getRemovedKernelArgs returns kernel arguments of the existing tuned bootloader command line that the desired
+// tuned does not have anymore, nil tuned and the creation of the tuned are skipped
+func (r *ReconcilePerformanceProfile) getRemovedKernelArgs(performanceTuned *tunedv1.Tuned) ([]string, error) {
+	if performanceTuned == nil {
+		return nil, nil
+	}
+
+	existing, err := r.getTuned(performanceTuned.Name, performanceTuned.Namespace)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return profileutil.RemovedKernelArgs(tuned.GetKernelArgs(existing), tuned.GetKernelArgs(performanceTuned)), nil
+}
+
+// removeForceSyncAnnotation removes the force sync annotation from the performance profile,
+// the paused profile keeps the annotation until the forced update is applied
+func (r *ReconcilePerformanceProfile) removeForceSyncAnnotation(profile *performancev1.PerformanceProfile) error {
+	if profileutil.IsPaused(profile) {
+		return nil
+	}
+
+	if _, ok := profile.Annotations[performancev1.PerformanceProfileForceSyncAnnotation]; !ok {
+		return nil
+	}
+
+	klog.Infof("Forced the machine config update of the performance profile %s", profile.Name)
+	delete(profile.Annotations, performancev1.PerformanceProfileForceSyncAnnotation)
+	return r.client.Update(context.TODO(), profile)
+}
+
 func (r *ReconcilePerformanceProfile) deleteComponents(profile *performancev1.PerformanceProfile) error {
 	tunedName := components.GetComponentName(profile.Name, components.ProfileNamePerformance)
 	if err := r.deleteTuned(tunedName, components.NamespaceNodeTuningOperator); err != nil {
@@ -440,14 +1006,50 @@ func (r *ReconcilePerformanceProfile) deleteComponents(profile *performancev1.Pe
 		return err
 	}
 
+	if err := r.deleteContainerRuntimeConfig(name); err != nil {
+		return err
+	}
+
+	// the pool paused by the operator in the middle of the coordinated rollout stays paused forever
+	// once the profile is gone
+	if err := r.unpauseMachineConfigPools(profile); err != nil {
+		return err
+	}
+
+	if err := r.deleteMachineConfigPool(name, profile); err != nil {
+		return err
+	}
+
 	if err := r.deleteMachineConfig(name); err != nil {
 		return err
 	}
 
+	if err := r.deleteMachineConfig(getBackupMachineConfigName(name)); err != nil {
+		return err
+	}
+
+	for architecture := range components.IOMMUKernelArgs {
+		if err := r.deleteMachineConfig(machineconfig.GetArchitectureName(name, architecture)); err != nil {
+			return err
+		}
+	}
+
 	if err := r.deleteRuntimeClass(name); err != nil {
 		return err
 	}
 
+	if err := r.deletePriorityClass(name); err != nil {
+		return err
+	}
+
+	if err := r.deleteDaemonSet(name, components.NamespaceNodeTuningOperator); err != nil {
+		return err
+	}
+
+	if err := r.syncNodeLabels(profile, false); err != nil {
+		return err
+	}
+
 	return nil
 
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	. "github.com/onsi/ginkgo"
+	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 
@@ -16,17 +17,26 @@ import (
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/kubeletconfig"
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/machineconfig"
+	profileutil "github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/profile"
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/runtimeclass"
 	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/tuned"
 	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+	configv1 "github.com/openshift/api/config/v1"
 	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
 	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
 	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	nodev1beta1 "k8s.io/api/node/v1beta1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -34,6 +44,7 @@ import (
 	"k8s.io/utils/pointer"
 
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -141,6 +152,649 @@ var _ = Describe("Controller", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("should create priority class only when requested", func() {
+			r := newFakeReconciler(profile)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			key := types.NamespacedName{
+				Name:      components.GetComponentName(profile.Name, components.ComponentNamePrefix),
+				Namespace: metav1.NamespaceNone,
+			}
+			priorityClass := &schedulingv1.PriorityClass{}
+			err := r.client.Get(context.TODO(), key, priorityClass)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			profile.Spec.PriorityClass = &performancev1.PriorityClass{
+				Enabled: pointer.BoolPtr(true),
+				Value:   pointer.Int32Ptr(1000),
+			}
+			r = newFakeReconciler(profile)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			Expect(r.client.Get(context.TODO(), key, priorityClass)).ToNot(HaveOccurred())
+			Expect(priorityClass.Value).To(Equal(int32(1000)))
+			Expect(priorityClass.OwnerReferences).To(HaveLen(1))
+			Expect(priorityClass.OwnerReferences[0].Name).To(Equal(profile.Name))
+		})
+
+		It("should create tuning daemon only when the image is specified", func() {
+			r := newFakeReconciler(profile)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			key := types.NamespacedName{
+				Name:      components.GetComponentName(profile.Name, components.ComponentNamePrefix),
+				Namespace: components.NamespaceNodeTuningOperator,
+			}
+			ds := &appsv1.DaemonSet{}
+			err := r.client.Get(context.TODO(), key, ds)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			r.tuningDaemonImage = "quay.io/openshift-kni/performance-tuning-daemon:test"
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			Expect(r.client.Get(context.TODO(), key, ds)).ToNot(HaveOccurred())
+			Expect(ds.Spec.Template.Spec.Containers[0].Image).To(Equal(r.tuningDaemonImage))
+			Expect(ds.OwnerReferences).To(HaveLen(1))
+			Expect(ds.OwnerReferences[0].Name).To(Equal(profile.Name))
+
+			// the tuning daemon should be removed once the image is not specified
+			r.tuningDaemonImage = ""
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			err = r.client.Get(context.TODO(), key, ds)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should sync tuning annotations of the profile nodes", func() {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node1",
+					Labels: profile.Spec.NodeSelector,
+					Annotations: map[string]string{
+						"tuning.performance.openshift.io/stale": "value",
+						"foreign":                               "value",
+					},
+				},
+			}
+			otherNode := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node2"},
+			}
+			r := newFakeReconciler(profile, node, otherNode)
+			r.tuningDaemonImage = "quay.io/openshift-kni/performance-tuning-daemon:test"
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			updatedNode := &corev1.Node{}
+			Expect(r.client.Get(context.TODO(), types.NamespacedName{Name: node.Name}, updatedNode)).ToNot(HaveOccurred())
+			Expect(updatedNode.Annotations).To(Equal(map[string]string{
+				"tuning.performance.openshift.io/profile":       profile.Name,
+				"tuning.performance.openshift.io/isolated-cpus": "4-7",
+				"tuning.performance.openshift.io/reserved-cpus": "0-3",
+				"foreign": "value",
+			}))
+
+			updatedOtherNode := &corev1.Node{}
+			Expect(r.client.Get(context.TODO(), types.NamespacedName{Name: otherNode.Name}, updatedOtherNode)).ToNot(HaveOccurred())
+			Expect(updatedOtherNode.Annotations).To(BeEmpty())
+		})
+
+		It("should label the profile nodes only when requested", func() {
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node1",
+					Labels: map[string]string{"nodekey": "nodeValue"},
+				},
+			}
+			otherProfileNode := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node2",
+					Labels: map[string]string{
+						"nodekey": "nodeValue",
+						performancev1.PerformanceProfileNodeLabel: "other",
+					},
+				},
+			}
+			staleNode := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node3",
+					Labels: map[string]string{performancev1.PerformanceProfileNodeLabel: profile.Name},
+				},
+			}
+			r := newFakeReconciler(profile, node, otherProfileNode, staleNode)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			// the stale label is removed even when node labeling is disabled
+			updatedNode := &corev1.Node{}
+			Expect(r.client.Get(context.TODO(), types.NamespacedName{Name: node.Name}, updatedNode)).ToNot(HaveOccurred())
+			Expect(updatedNode.Labels).ToNot(HaveKey(performancev1.PerformanceProfileNodeLabel))
+			updatedNode = &corev1.Node{}
+			Expect(r.client.Get(context.TODO(), types.NamespacedName{Name: staleNode.Name}, updatedNode)).ToNot(HaveOccurred())
+			Expect(updatedNode.Labels).ToNot(HaveKey(performancev1.PerformanceProfileNodeLabel))
+
+			updatedProfile := &performancev1.PerformanceProfile{}
+			Expect(r.client.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+			updatedProfile.Annotations = map[string]string{performancev1.PerformanceProfileNodeLabelAnnotation: "true"}
+			Expect(r.client.Update(context.TODO(), updatedProfile)).ToNot(HaveOccurred())
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			updatedNode = &corev1.Node{}
+			Expect(r.client.Get(context.TODO(), types.NamespacedName{Name: node.Name}, updatedNode)).ToNot(HaveOccurred())
+			Expect(updatedNode.Labels).To(HaveKeyWithValue(performancev1.PerformanceProfileNodeLabel, profile.Name))
+			// the node labeled by another profile keeps its label
+			updatedNode = &corev1.Node{}
+			Expect(r.client.Get(context.TODO(), types.NamespacedName{Name: otherProfileNode.Name}, updatedNode)).ToNot(HaveOccurred())
+			Expect(updatedNode.Labels).To(HaveKeyWithValue(performancev1.PerformanceProfileNodeLabel, "other"))
+
+			// the label is removed once the node does not match the node selector
+			updatedNode = &corev1.Node{}
+			Expect(r.client.Get(context.TODO(), types.NamespacedName{Name: node.Name}, updatedNode)).ToNot(HaveOccurred())
+			delete(updatedNode.Labels, "nodekey")
+			Expect(r.client.Update(context.TODO(), updatedNode)).ToNot(HaveOccurred())
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			updatedNode = &corev1.Node{}
+			Expect(r.client.Get(context.TODO(), types.NamespacedName{Name: node.Name}, updatedNode)).ToNot(HaveOccurred())
+			Expect(updatedNode.Labels).ToNot(HaveKey(performancev1.PerformanceProfileNodeLabel))
+		})
+
+		It("should reject the profile that exceeds the huge pages count limit", func() {
+			r := newFakeReconciler(profile)
+			r.hugepagesCountLimits = map[performancev1.HugePageSize]int32{"1G": 2}
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			updatedProfile := &performancev1.PerformanceProfile{}
+			Expect(r.client.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+			degradedCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionsv1.ConditionDegraded)
+			Expect(degradedCondition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(degradedCondition.Reason).To(Equal(conditionReasonValidationFailed))
+			Expect(degradedCondition.Message).To(ContainSubstring("exceeds the configured maximum count 2"))
+		})
+
+		It("should reject the profile that the machine config operator version does not support", func() {
+			co := &configv1.ClusterOperator{
+				ObjectMeta: metav1.ObjectMeta{Name: mcoClusterOperatorName},
+				Status: configv1.ClusterOperatorStatus{
+					Versions: []configv1.OperandVersion{{Name: operatorVersionName, Version: "4.3.5"}},
+				},
+			}
+			r := newFakeReconciler(profile, co)
+			r.mcoVersionProvider = &clusterOperatorMCOVersionProvider{client: r.client}
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			updatedProfile := &performancev1.PerformanceProfile{}
+			Expect(r.client.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+			degradedCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionsv1.ConditionDegraded)
+			Expect(degradedCondition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(degradedCondition.Reason).To(Equal(conditionReasonValidationFailed))
+			Expect(degradedCondition.Message).To(ContainSubstring("requires the machine config operator version 4.4.0 or newer"))
+		})
+
+		It("should skip the machine config operator version validation when the version is unknown", func() {
+			r := newFakeReconciler(profile)
+			r.mcoVersionProvider = &clusterOperatorMCOVersionProvider{client: r.client}
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			updatedProfile := &performancev1.PerformanceProfile{}
+			Expect(r.client.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+			degradedCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionsv1.ConditionDegraded)
+			Expect(degradedCondition.Status).To(Equal(corev1.ConditionFalse))
+		})
+
+		It("should create machine config pool from the node selector when it does not exist", func() {
+			profile.Spec.MachineConfigLabel = map[string]string{components.MachineConfigRoleLabelKey: "worker-cnf"}
+			r := newFakeReconciler(profile)
+			r.mcpCreation = true
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			key := types.NamespacedName{
+				Name:      components.GetComponentName(profile.Name, components.ComponentNamePrefix),
+				Namespace: metav1.NamespaceNone,
+			}
+			mcp := &mcov1.MachineConfigPool{}
+			Expect(r.client.Get(context.TODO(), key, mcp)).ToNot(HaveOccurred())
+			Expect(mcp.Spec.NodeSelector.MatchLabels).To(Equal(profile.Spec.NodeSelector))
+			Expect(mcp.Labels).To(Equal(components.GetComponentLabels(profile.Spec.MachineConfigPoolSelector)))
+			Expect(mcp.OwnerReferences).To(HaveLen(1))
+			Expect(mcp.OwnerReferences[0].Name).To(Equal(profile.Name))
+
+			// the generated machine config should be targeted at the created pool
+			mc := &mcov1.MachineConfig{}
+			Expect(r.client.Get(context.TODO(), key, mc)).ToNot(HaveOccurred())
+			selector, err := metav1.LabelSelectorAsSelector(mcp.Spec.MachineConfigSelector)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selector.Matches(labels.Set(mc.Labels))).To(BeTrue())
+		})
+
+		It("should not create machine config pool when the profile pool exists", func() {
+			profile.Spec.MachineConfigLabel = map[string]string{components.MachineConfigRoleLabelKey: "worker-cnf"}
+			userMCP := &mcov1.MachineConfigPool{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: mcov1.GroupVersion.String(),
+					Kind:       "MachineConfigPool",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "worker-cnf",
+					Labels: profile.Spec.MachineConfigPoolSelector,
+				},
+			}
+			r := newFakeReconciler(profile, userMCP)
+			r.mcpCreation = true
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			key := types.NamespacedName{
+				Name:      components.GetComponentName(profile.Name, components.ComponentNamePrefix),
+				Namespace: metav1.NamespaceNone,
+			}
+			err := r.client.Get(context.TODO(), key, &mcov1.MachineConfigPool{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should not create machine config pool when the creation is disabled", func() {
+			profile.Spec.MachineConfigLabel = map[string]string{components.MachineConfigRoleLabelKey: "worker-cnf"}
+			r := newFakeReconciler(profile)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			key := types.NamespacedName{
+				Name:      components.GetComponentName(profile.Name, components.ComponentNamePrefix),
+				Namespace: metav1.NamespaceNone,
+			}
+			err := r.client.Get(context.TODO(), key, &mcov1.MachineConfigPool{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should delete the created machine config pool together with the profile", func() {
+			profile.Spec.MachineConfigLabel = map[string]string{components.MachineConfigRoleLabelKey: "worker-cnf"}
+			r := newFakeReconciler(profile)
+			r.mcpCreation = true
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			key := types.NamespacedName{
+				Name:      components.GetComponentName(profile.Name, components.ComponentNamePrefix),
+				Namespace: metav1.NamespaceNone,
+			}
+			Expect(r.client.Get(context.TODO(), key, &mcov1.MachineConfigPool{})).ToNot(HaveOccurred())
+
+			updatedProfile := &performancev1.PerformanceProfile{}
+			Expect(r.client.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+			updatedProfile.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			Expect(r.client.Update(context.TODO(), updatedProfile)).ToNot(HaveOccurred())
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			err := r.client.Get(context.TODO(), key, &mcov1.MachineConfigPool{})
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			deletedProfile := &performancev1.PerformanceProfile{}
+			Expect(r.client.Get(context.TODO(), request.NamespacedName, deletedProfile)).ToNot(HaveOccurred())
+			Expect(hasFinalizer(deletedProfile, finalizer)).To(BeFalse())
+		})
+
+		It("should create container runtime config only when requested", func() {
+			r := newFakeReconciler(profile)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			key := types.NamespacedName{
+				Name:      components.GetComponentName(profile.Name, components.ComponentNamePrefix),
+				Namespace: metav1.NamespaceNone,
+			}
+			crc := &mcov1.ContainerRuntimeConfig{}
+			err := r.client.Get(context.TODO(), key, crc)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			profile.Spec.ContainerRuntime = &performancev1.ContainerRuntime{
+				PidsLimit: pointer.Int64Ptr(8192),
+			}
+			r = newFakeReconciler(profile)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			Expect(r.client.Get(context.TODO(), key, crc)).ToNot(HaveOccurred())
+			Expect(crc.Spec.ContainerRuntimeConfig.PidsLimit).To(Equal(int64(8192)))
+			Expect(crc.Spec.MachineConfigPoolSelector.MatchLabels).To(HaveKeyWithValue(testutils.MachineConfigPoolLabelKey, testutils.MachineConfigPoolLabelValue))
+			Expect(crc.OwnerReferences).To(HaveLen(1))
+			Expect(crc.OwnerReferences[0].Name).To(Equal(profile.Name))
+		})
+
+		It("should create all owned objects of the full profile", func() {
+			profile.Spec.MachineConfigLabel = map[string]string{components.MachineConfigRoleLabelKey: "worker-cnf"}
+			profile.Spec.ContainerRuntime = &performancev1.ContainerRuntime{
+				PidsLimit: pointer.Int64Ptr(8192),
+			}
+			profile.Spec.PriorityClass = &performancev1.PriorityClass{
+				Enabled: pointer.BoolPtr(true),
+			}
+
+			name := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+			refs := OwnedObjectRefs(profile)
+			Expect(refs).To(Equal([]ObjectRef{
+				{GroupVersionKind: mcov1.GroupVersion.WithKind("MachineConfig"), Name: name},
+				{GroupVersionKind: mcov1.GroupVersion.WithKind("MachineConfigPool"), Name: name},
+				{GroupVersionKind: mcov1.GroupVersion.WithKind("KubeletConfig"), Name: name},
+				{GroupVersionKind: mcov1.GroupVersion.WithKind("ContainerRuntimeConfig"), Name: name},
+				{
+					GroupVersionKind: tunedv1.SchemeGroupVersion.WithKind("Tuned"),
+					Namespace:        components.NamespaceNodeTuningOperator,
+					Name:             components.GetComponentName(profile.Name, components.ProfileNamePerformance),
+				},
+				{GroupVersionKind: nodev1beta1.SchemeGroupVersion.WithKind("RuntimeClass"), Name: name},
+				{GroupVersionKind: schedulingv1.SchemeGroupVersion.WithKind("PriorityClass"), Name: name},
+				{GroupVersionKind: appsv1.SchemeGroupVersion.WithKind("DaemonSet"), Namespace: components.NamespaceNodeTuningOperator, Name: name},
+			}))
+
+			r := newFakeReconciler(profile)
+			r.tuningDaemonImage = "quay.io/openshift-kni/performance-tuning-daemon:test"
+			r.mcpCreation = true
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			// verify that the profile does not own objects missing from the list
+			for _, ref := range refs {
+				obj := &unstructured.Unstructured{}
+				obj.SetGroupVersionKind(ref.GroupVersionKind)
+				key := types.NamespacedName{
+					Name:      ref.Name,
+					Namespace: ref.Namespace,
+				}
+				Expect(r.client.Get(context.TODO(), key, obj)).ToNot(HaveOccurred(), "%s %q should exist", ref.GroupVersionKind.Kind, ref.Name)
+			}
+		})
+
+		Context("with the mixed architecture cluster", func() {
+			newArchitecturePool := func(architecture string) *mcov1.MachineConfigPool {
+				return &mcov1.MachineConfigPool{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: mcov1.GroupVersion.String(),
+						Kind:       "MachineConfigPool",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "mcp-" + architecture,
+					},
+					Spec: mcov1.MachineConfigPoolSpec{
+						MachineConfigSelector: &metav1.LabelSelector{
+							MatchLabels: profileutil.GetArchitectureMachineConfigLabel(profile, architecture),
+						},
+					},
+				}
+			}
+
+			getArchitectureMC := func(r *ReconcilePerformanceProfile, architecture string) (*mcov1.MachineConfig, error) {
+				name := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+				key := types.NamespacedName{
+					Name:      machineconfig.GetArchitectureName(name, architecture),
+					Namespace: metav1.NamespaceNone,
+				}
+				mc := &mcov1.MachineConfig{}
+				err := r.client.Get(context.TODO(), key, mc)
+				return mc, err
+			}
+
+			BeforeEach(func() {
+				profile.Annotations = map[string]string{performancev1.PerformanceProfileArchitecturesAnnotation: "amd64,arm64"}
+			})
+
+			It("should create the machine config for each architecture", func() {
+				r := newFakeReconciler(profile, newArchitecturePool(components.ArchitectureAMD64), newArchitecturePool(components.ArchitectureARM64))
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				amd64MC, err := getArchitectureMC(r, components.ArchitectureAMD64)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(amd64MC.Spec.KernelArguments).To(Equal([]string{"intel_iommu=on", "iommu=pt"}))
+				Expect(amd64MC.OwnerReferences).To(HaveLen(1))
+				Expect(amd64MC.OwnerReferences[0].Name).To(Equal(profile.Name))
+
+				arm64MC, err := getArchitectureMC(r, components.ArchitectureARM64)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(arm64MC.Spec.KernelArguments).To(Equal([]string{"iommu.passthrough=1"}))
+
+				// the tuned shared by architecture specific pools should not carry IOMMU kernel arguments
+				tunedPerformance := &tunedv1.Tuned{}
+				key := types.NamespacedName{
+					Name:      components.GetComponentName(profile.Name, components.ProfileNamePerformance),
+					Namespace: components.NamespaceNodeTuningOperator,
+				}
+				Expect(r.client.Get(context.TODO(), key, tunedPerformance)).ToNot(HaveOccurred())
+				Expect(tuned.GetKernelArgs(tunedPerformance)).ToNot(ContainElement("intel_iommu=on"))
+				Expect(tunedPerformance.Spec.Recommend).To(HaveLen(3))
+			})
+
+			It("should fail when the cluster does not have the pool for the architecture", func() {
+				r := newFakeReconciler(profile, newArchitecturePool(components.ArchitectureAMD64))
+				_, err := r.Reconcile(request)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(`failed to find the machine config pool for the architecture "arm64"`))
+
+				updatedProfile := &performancev1.PerformanceProfile{}
+				key := types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				Expect(r.client.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+				degradedCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionsv1.ConditionDegraded)
+				Expect(degradedCondition).ToNot(BeNil())
+				Expect(degradedCondition.Status).To(Equal(corev1.ConditionTrue))
+			})
+
+			It("should delete the machine config of the architecture the profile does not target anymore", func() {
+				r := newFakeReconciler(profile, newArchitecturePool(components.ArchitectureAMD64), newArchitecturePool(components.ArchitectureARM64))
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedProfile := &performancev1.PerformanceProfile{}
+				key := types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				Expect(r.client.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+				updatedProfile.Annotations[performancev1.PerformanceProfileArchitecturesAnnotation] = "amd64"
+				Expect(r.client.Update(context.TODO(), updatedProfile)).ToNot(HaveOccurred())
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				_, err := getArchitectureMC(r, components.ArchitectureAMD64)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = getArchitectureMC(r, components.ArchitectureARM64)
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+
+		It("should validate kernel arguments against CPUs of the profile nodes", func() {
+			profile.Annotations = map[string]string{performancev1.PerformanceProfileStrictValidationAnnotation: "true"}
+			profile.Spec.AdditionalKernelArgs = []string{"intel_pstate=disable"}
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-amd",
+					Labels: map[string]string{
+						"nodekey":      "nodeValue",
+						cpuVendorLabel: "AMD",
+					},
+				},
+			}
+			r := newFakeReconciler(profile, node)
+			r.cpuInfoProvider = &nodeLabelsCPUInfoProvider{client: r.client}
+
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			updatedProfile := &performancev1.PerformanceProfile{}
+			key := types.NamespacedName{
+				Name:      profile.Name,
+				Namespace: metav1.NamespaceNone,
+			}
+			Expect(r.client.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+
+			degradedCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionsv1.ConditionDegraded)
+			Expect(degradedCondition).ToNot(BeNil())
+			Expect(degradedCondition.Status).To(Equal(corev1.ConditionTrue))
+			Expect(degradedCondition.Reason).To(Equal(conditionReasonValidationFailed))
+			Expect(degradedCondition.Message).To(ContainSubstring(`the node "node-amd" has AMD CPU`))
+		})
+
+		Context("with the node topology", func() {
+			newTopologyNode := func(name string, siblings string, numaNodesCPUs string) *corev1.Node {
+				return &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   name,
+						Labels: map[string]string{"nodekey": "nodeValue"},
+						Annotations: map[string]string{
+							performancev1.NodeCoreSiblingsAnnotation:  siblings,
+							performancev1.NodeNUMANodesCPUsAnnotation: numaNodesCPUs,
+						},
+					},
+					Status: corev1.NodeStatus{
+						Capacity: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("16Gi")},
+					},
+				}
+			}
+
+			It("should provide the topology of the annotated profile nodes", func() {
+				node := newTopologyNode("node-0", "0,4;1,5;2,6;3,7", "0-3;4-7")
+				smallNode := newTopologyNode("node-1", "0,4;1,5;2,6;3,7", "0-3;4-7")
+				smallNode.Status.Capacity[corev1.ResourceMemory] = resource.MustParse("8Gi")
+				// nodes without the annotations do not make the topology unknown
+				plainNode := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{"nodekey": "nodeValue"}},
+				}
+				r := newFakeReconciler(profile, node, smallNode, plainNode)
+				provider := &nodeTopologyProvider{client: r.client}
+
+				siblings, err := provider.GetCoreSiblings(profile)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(siblings).To(HaveLen(4))
+				Expect(siblings[1].String()).To(Equal("1,5"))
+
+				count, err := provider.GetNUMANodesCount(profile)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(count).To(Equal(2))
+
+				numaNodesCPUs, err := provider.GetNUMANodesCPUs(profile)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(numaNodesCPUs).To(HaveLen(2))
+				Expect(numaNodesCPUs[1].String()).To(Equal("4-7"))
+
+				memory, err := provider.GetMemoryKilobytes(profile)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(memory).To(Equal(int64(8 * 1024 * 1024)))
+			})
+
+			It("should return the unknown topology when the profile nodes differ", func() {
+				node := newTopologyNode("node-0", "0,4;1,5;2,6;3,7", "0-3;4-7")
+				otherNode := newTopologyNode("node-1", "0,1;2,3;4,5;6,7", "0-7")
+				r := newFakeReconciler(profile, node, otherNode)
+				provider := &nodeTopologyProvider{client: r.client}
+
+				siblings, err := provider.GetCoreSiblings(profile)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(siblings).To(BeEmpty())
+
+				numaNodesCPUs, err := provider.GetNUMANodesCPUs(profile)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(numaNodesCPUs).To(BeEmpty())
+
+				// the smallest number of NUMA nodes is known anyway
+				count, err := provider.GetNUMANodesCount(profile)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(count).To(Equal(1))
+			})
+
+			It("should skip nodes with malformed annotations", func() {
+				node := newTopologyNode("node-0", "0,4;;2,6", "0-a")
+				r := newFakeReconciler(profile, node)
+				provider := &nodeTopologyProvider{client: r.client}
+
+				siblings, err := provider.GetCoreSiblings(profile)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(siblings).To(BeEmpty())
+
+				count, err := provider.GetNUMANodesCount(profile)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(count).To(Equal(0))
+			})
+
+			It("should validate the profile against the topology of the profile nodes", func() {
+				profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(1)
+				node := newTopologyNode("node-0", "0,4;1,5;2,6;3,7", "0-7")
+				r := newFakeReconciler(profile, node)
+				r.topologyProvider = &nodeTopologyProvider{client: r.client}
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedProfile := &performancev1.PerformanceProfile{}
+				key := types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				Expect(r.client.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+
+				degradedCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionsv1.ConditionDegraded)
+				Expect(degradedCondition).ToNot(BeNil())
+				Expect(degradedCondition.Status).To(Equal(corev1.ConditionTrue))
+				Expect(degradedCondition.Message).To(ContainSubstring(`the huge pages "1G" can not be allocated on the NUMA node 1, the profile nodes have only 1 NUMA nodes`))
+			})
+
+			It("should validate the isolated CPUs percentage against online CPUs of the profile nodes", func() {
+				profile.Annotations = map[string]string{performancev1.PerformanceProfileMaxIsolatedCPUsPercentageAnnotation: "40"}
+				node := newTopologyNode("node-0", "0,4;1,5;2,6;3,7", "0-7")
+				r := newFakeReconciler(profile, node)
+				r.topologyProvider = &nodeTopologyProvider{client: r.client}
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedProfile := &performancev1.PerformanceProfile{}
+				key := types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				Expect(r.client.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+
+				degradedCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionsv1.ConditionDegraded)
+				Expect(degradedCondition).ToNot(BeNil())
+				Expect(degradedCondition.Status).To(Equal(corev1.ConditionTrue))
+				Expect(degradedCondition.Message).To(ContainSubstring("the profile isolates 4 out of 8 online CPUs, that exceeds 40% of online CPUs"))
+			})
+
+			It("should report CPUs that stay online once SMT is disabled on the profile nodes", func() {
+				profile.Spec.AdditionalKernelArgs = []string{"nosmt"}
+				node := newTopologyNode("node-0", "0,1;2,3;4,5;6,7", "0-7")
+				r := newFakeReconciler(profile, node)
+				r.topologyProvider = &nodeTopologyProvider{client: r.client}
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedProfile := &performancev1.PerformanceProfile{}
+				key := types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				Expect(r.client.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+
+				degradedCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionsv1.ConditionDegraded)
+				Expect(degradedCondition).ToNot(BeNil())
+				Expect(degradedCondition.Status).To(Equal(corev1.ConditionTrue))
+				Expect(degradedCondition.Message).To(ContainSubstring(`the isolated CPUs "5,7" are SMT siblings that go offline once SMT is disabled`))
+
+				Expect(updatedProfile.Status.IsolatedCPUCount).NotTo(BeNil())
+				Expect(*updatedProfile.Status.IsolatedCPUCount).To(Equal(int32(2)))
+				Expect(updatedProfile.Status.HousekeepingCPUs).NotTo(BeNil())
+				Expect(*updatedProfile.Status.HousekeepingCPUs).To(Equal(performancev1.CPUSet("0,2")))
+			})
+
+			It("should validate the huge pages NUMA affinity against the topology of the profile nodes", func() {
+				profile.Annotations = map[string]string{performancev1.PerformanceProfileStrictValidationAnnotation: "true"}
+				profile.Spec.HugePages.Pages[0].Size = "2M"
+				profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(0)
+				node := newTopologyNode("node-0", "0,4;1,5;2,6;3,7", "0-3;4-7")
+				r := newFakeReconciler(profile, node)
+				r.topologyProvider = &nodeTopologyProvider{client: r.client}
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedProfile := &performancev1.PerformanceProfile{}
+				key := types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				Expect(r.client.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+
+				degradedCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionsv1.ConditionDegraded)
+				Expect(degradedCondition).ToNot(BeNil())
+				Expect(degradedCondition.Status).To(Equal(corev1.ConditionTrue))
+				Expect(degradedCondition.Message).To(ContainSubstring(`the huge pages "2M" are allocated on the NUMA node 0, but isolated CPUs "4-7" are on NUMA nodes [1]`))
+			})
+		})
+
 		It("should create event on the second reconcile loop", func() {
 			r := newFakeReconciler(profile)
 
@@ -151,6 +805,7 @@ var _ = Describe("Controller", func() {
 			Expect(ok).To(BeTrue())
 			event := <-fakeRecorder.Events
 			Expect(event).To(ContainSubstring("Creation succeeded"))
+			Expect(event).To(ContainSubstring("4 isolated CPUs (4-7)"))
 		})
 
 		It("should update the profile status", func() {
@@ -298,6 +953,560 @@ var _ = Describe("Controller", func() {
 				}
 			})
 
+			It("should warn about the nodes reboot when the cgroup mode changes", func() {
+				cgroupMode := performancev1.CgroupModeV2
+				profile.Spec.CgroupMode = &cgroupMode
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass)
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				fakeRecorder, ok := r.recorder.(*record.FakeRecorder)
+				Expect(ok).To(BeTrue())
+				event := <-fakeRecorder.Events
+				Expect(event).To(ContainSubstring("Cgroup mode changed"))
+				Expect(event).To(ContainSubstring("systemd.unified_cgroup_hierarchy=1"))
+			})
+
+			It("should update isolated CPU groups annotation of RuntimeClass and Tuned", func() {
+				runtimeClass.Annotations = map[string]string{components.IsolatedCPUGroupsAnnotation: "dpdk=6-7"}
+				profile.Spec.CPU.IsolatedGroups = []performancev1.IsolatedCPUGroup{{Name: "rt", CPUs: "6-7"}}
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass)
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				key := types.NamespacedName{
+					Name:      runtimeClass.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				updatedRuntimeClass := &nodev1beta1.RuntimeClass{}
+				Expect(r.client.Get(context.TODO(), key, updatedRuntimeClass)).ToNot(HaveOccurred())
+				Expect(updatedRuntimeClass.Annotations).To(HaveKeyWithValue(components.IsolatedCPUGroupsAnnotation, "rt=6-7"))
+
+				key = types.NamespacedName{
+					Name:      tunedPerformance.Name,
+					Namespace: tunedPerformance.Namespace,
+				}
+				updatedTuned := &tunedv1.Tuned{}
+				Expect(r.client.Get(context.TODO(), key, updatedTuned)).ToNot(HaveOccurred())
+				Expect(updatedTuned.Annotations).To(HaveKeyWithValue(components.IsolatedCPUGroupsAnnotation, "rt=6-7"))
+
+				// the annotation should be removed once the profile does not have groups
+				updatedProfile := &performancev1.PerformanceProfile{}
+				key = types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				Expect(r.client.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+				updatedProfile.Spec.CPU.IsolatedGroups = nil
+				Expect(r.client.Update(context.TODO(), updatedProfile)).ToNot(HaveOccurred())
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				key = types.NamespacedName{
+					Name:      runtimeClass.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				updatedRuntimeClass = &nodev1beta1.RuntimeClass{}
+				Expect(r.client.Get(context.TODO(), key, updatedRuntimeClass)).ToNot(HaveOccurred())
+				Expect(updatedRuntimeClass.Annotations).ToNot(HaveKey(components.IsolatedCPUGroupsAnnotation))
+			})
+
+			It("should validate the isolation reduction against pinned pods of the profile nodes", func() {
+				profile.Annotations = map[string]string{performancev1.PerformanceProfileStrictValidationAnnotation: "true"}
+				isolated := performancev1.CPUSet("4-5")
+				profile.Spec.CPU.Isolated = &isolated
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "node-worker",
+						Labels: map[string]string{"nodekey": "nodeValue"},
+					},
+				}
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "pinned",
+						Namespace: "test",
+					},
+					Spec: corev1.PodSpec{
+						NodeName: node.Name,
+						Containers: []corev1.Container{
+							{
+								Name: "test",
+								Resources: corev1.ResourceRequirements{
+									Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+									Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+								},
+							},
+						},
+					},
+					Status: corev1.PodStatus{
+						Phase:    corev1.PodRunning,
+						QOSClass: corev1.PodQOSGuaranteed,
+					},
+				}
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, node, pod)
+				r.podLister = &nodesPodLister{client: r.client}
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedProfile := &performancev1.PerformanceProfile{}
+				key := types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				Expect(r.client.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+
+				degradedCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionsv1.ConditionDegraded)
+				Expect(degradedCondition).ToNot(BeNil())
+				Expect(degradedCondition.Reason).To(Equal(conditionReasonValidationFailed))
+				Expect(degradedCondition.Message).To(ContainSubstring(`the isolated CPUs "6-7" are removed`))
+				Expect(degradedCondition.Message).To(ContainSubstring("test/pinned"))
+			})
+
+			It("should update the machine config and the tuned together when isolated CPUs change", func() {
+				Expect(mc.Annotations).To(HaveKeyWithValue(components.IsolatedCPUsAnnotation, "4-7"))
+				Expect(tunedPerformance.Annotations).To(HaveKeyWithValue(components.IsolatedCPUsAnnotation, "4-7"))
+
+				isolated := performancev1.CPUSet("5-7")
+				profile.Spec.CPU.Isolated = &isolated
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass)
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedMC := &mcov1.MachineConfig{}
+				key := types.NamespacedName{
+					Name:      mc.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				Expect(r.client.Get(context.TODO(), key, updatedMC)).ToNot(HaveOccurred())
+				Expect(updatedMC.Annotations).To(HaveKeyWithValue(components.IsolatedCPUsAnnotation, "5-7"))
+
+				updatedTuned := &tunedv1.Tuned{}
+				key = types.NamespacedName{
+					Name:      tunedPerformance.Name,
+					Namespace: tunedPerformance.Namespace,
+				}
+				Expect(r.client.Get(context.TODO(), key, updatedTuned)).ToNot(HaveOccurred())
+				Expect(updatedTuned.Annotations).To(HaveKeyWithValue(components.IsolatedCPUsAnnotation, "5-7"))
+				Expect(*updatedTuned.Spec.Profile[0].Data).To(ContainSubstring("isolated_cores=5-7"))
+			})
+
+			It("should reject the existing machine config and tuned that isolate different CPUs", func() {
+				// the machine config was updated, but the tuned still isolates the previous CPUs
+				mc.Annotations[components.IsolatedCPUsAnnotation] = "5-7"
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass)
+
+				err := r.validateIsolatedCPUsSync(mc.Name, tunedPerformance.Name, tunedPerformance.Namespace)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(`isolated CPUs "5-7" differ from the tuned`))
+			})
+
+			It("should update the tuned that lags behind the existing machine config", func() {
+				isolated := performancev1.CPUSet("5-7")
+				profile.Spec.CPU.Isolated = &isolated
+				updatedMC, err := machineconfig.New(assetsDir, profile)
+				Expect(err).ToNot(HaveOccurred())
+				r := newFakeReconciler(profile, updatedMC, kc, tunedPerformance, runtimeClass)
+				Expect(r.validateIsolatedCPUsSync(updatedMC.Name, tunedPerformance.Name, tunedPerformance.Namespace)).ToNot(Succeed())
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+				Expect(r.validateIsolatedCPUsSync(updatedMC.Name, tunedPerformance.Name, tunedPerformance.Namespace)).To(Succeed())
+			})
+
+			It("should record the reboot event when the change requires the reboot", func() {
+				isolated := performancev1.CPUSet("3-7")
+				profile.Spec.CPU.Isolated = &isolated
+				profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass)
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				fakeRecorder, ok := r.recorder.(*record.FakeRecorder)
+				Expect(ok).To(BeTrue())
+				event := <-fakeRecorder.Events
+				Expect(event).To(ContainSubstring("RebootTriggered"))
+				Expect(event).To(ContainSubstring(`kernel type change from "realtime" to "default"`))
+				Expect(event).To(ContainSubstring("isolcpus change"))
+			})
+
+			It("should record the event with kernel arguments the operator no longer generates", func() {
+				// the tuned generated by the older version of the operator
+				legacyData := *tunedPerformance.Spec.Profile[0].Data + "\ncmdline_legacy=+legacy_arg=1 legacy_flag\n"
+				tunedPerformance.Spec.Profile[0].Data = &legacyData
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass)
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				fakeRecorder, ok := r.recorder.(*record.FakeRecorder)
+				Expect(ok).To(BeTrue())
+				var events []string
+				for len(fakeRecorder.Events) > 0 {
+					events = append(events, <-fakeRecorder.Events)
+				}
+				Expect(events).To(ContainElement(ContainSubstring(`KernelArgumentsRemoved The kernel arguments "legacy_arg=1 legacy_flag" are no longer generated and will be removed from the tuned %q`, tunedPerformance.Name)))
+			})
+
+			It("should not record the removed kernel arguments event when only values change", func() {
+				// the existing tuned carries the older value of the additional kernel argument
+				oldProfile := profile.DeepCopy()
+				oldProfile.Spec.AdditionalKernelArgs = []string{"nmi_watchdog=1"}
+				oldTuned, err := tuned.NewNodePerformance(assetsDir, oldProfile)
+				Expect(err).ToNot(HaveOccurred())
+
+				profile.Spec.AdditionalKernelArgs = []string{"nmi_watchdog=0"}
+				r := newFakeReconciler(profile, mc, kc, oldTuned, runtimeClass)
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedTuned := &tunedv1.Tuned{}
+				key := types.NamespacedName{
+					Name:      oldTuned.Name,
+					Namespace: oldTuned.Namespace,
+				}
+				Expect(r.client.Get(context.TODO(), key, updatedTuned)).ToNot(HaveOccurred())
+				Expect(tuned.GetKernelArgs(updatedTuned)).To(ContainElement("nmi_watchdog=0"))
+
+				fakeRecorder, ok := r.recorder.(*record.FakeRecorder)
+				Expect(ok).To(BeTrue())
+				for len(fakeRecorder.Events) > 0 {
+					Expect(<-fakeRecorder.Events).ToNot(ContainSubstring("KernelArgumentsRemoved"))
+				}
+			})
+
+			It("should record the event with kernel arguments the profile change drops from the tuned", func() {
+				// the existing tuned carries the additional kernel argument the profile does not have anymore
+				oldProfile := profile.DeepCopy()
+				oldProfile.Spec.AdditionalKernelArgs = []string{"nmi_watchdog=0"}
+				oldTuned, err := tuned.NewNodePerformance(assetsDir, oldProfile)
+				Expect(err).ToNot(HaveOccurred())
+				r := newFakeReconciler(profile, mc, kc, oldTuned, runtimeClass)
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				fakeRecorder, ok := r.recorder.(*record.FakeRecorder)
+				Expect(ok).To(BeTrue())
+				var events []string
+				for len(fakeRecorder.Events) > 0 {
+					events = append(events, <-fakeRecorder.Events)
+				}
+				Expect(events).To(ContainElement(ContainSubstring(`KernelArgumentsRemoved The kernel arguments "nmi_watchdog=0" are no longer generated`)))
+			})
+
+			Context("with the status sync", func() {
+				var mcp *mcov1.MachineConfigPool
+
+				BeforeEach(func() {
+					// the pool still applies the profile machine config
+					mcp = &mcov1.MachineConfigPool{
+						TypeMeta: metav1.TypeMeta{
+							APIVersion: mcov1.GroupVersion.String(),
+							Kind:       "MachineConfigPool",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "mcp-test",
+						},
+						Spec: mcov1.MachineConfigPoolSpec{
+							MachineConfigSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{testutils.MachineConfigPoolLabelKey: testutils.MachineConfigPoolLabelValue},
+							},
+							Configuration: mcov1.MachineConfigPoolStatusConfiguration{
+								ObjectReference: corev1.ObjectReference{Name: "rendered-new"},
+							},
+						},
+						Status: mcov1.MachineConfigPoolStatus{
+							Configuration: mcov1.MachineConfigPoolStatusConfiguration{
+								ObjectReference: corev1.ObjectReference{Name: "rendered-new"},
+								Source:          []corev1.ObjectReference{{Name: mc.Name}},
+							},
+							MachineCount:        2,
+							UpdatedMachineCount: 1,
+						},
+					}
+					profile.Status.Conditions = (&ReconcilePerformanceProfile{}).getAvailableConditions()
+				})
+
+				getProfile := func(r *ReconcilePerformanceProfile) *performancev1.PerformanceProfile {
+					updatedProfile := &performancev1.PerformanceProfile{}
+					Expect(r.client.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+					return updatedProfile
+				}
+
+				It("should refresh the reboot required status without updating components", func() {
+					// the outdated machine config should stay as is
+					profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+					r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, mcp)
+					s := &statusSyncReconciler{r: r}
+
+					key := types.NamespacedName{Name: mc.Name, Namespace: metav1.NamespaceNone}
+					existingMC := &mcov1.MachineConfig{}
+					Expect(r.client.Get(context.TODO(), key, existingMC)).ToNot(HaveOccurred())
+
+					Expect(s.Reconcile(request)).To(Equal(reconcile.Result{}))
+					Expect(getProfile(r).Status.RebootRequired).To(BeTrue())
+
+					// all machines were updated
+					mcp.Status.UpdatedMachineCount = 2
+					Expect(r.client.Status().Update(context.TODO(), mcp)).ToNot(HaveOccurred())
+					Expect(s.Reconcile(request)).To(Equal(reconcile.Result{}))
+					Expect(getProfile(r).Status.RebootRequired).To(BeFalse())
+
+					updatedMC := &mcov1.MachineConfig{}
+					Expect(r.client.Get(context.TODO(), key, updatedMC)).ToNot(HaveOccurred())
+					Expect(updatedMC.ResourceVersion).To(Equal(existingMC.ResourceVersion))
+					Expect(updatedMC.Spec.KernelType).To(Equal(machineconfig.MCKernelRT))
+
+					fakeRecorder, ok := r.recorder.(*record.FakeRecorder)
+					Expect(ok).To(BeTrue())
+					Expect(fakeRecorder.Events).To(BeEmpty())
+				})
+
+				It("should not create missing components", func() {
+					r := newFakeReconciler(profile, mcp)
+					s := &statusSyncReconciler{r: r}
+					Expect(s.Reconcile(request)).To(Equal(reconcile.Result{}))
+
+					key := types.NamespacedName{Name: mc.Name, Namespace: metav1.NamespaceNone}
+					Expect(errors.IsNotFound(r.client.Get(context.TODO(), key, &mcov1.MachineConfig{}))).To(BeTrue())
+				})
+
+				It("should report the degraded machine config pool", func() {
+					r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, mcp)
+					s := &statusSyncReconciler{r: r}
+
+					mcp.Status.Conditions = []mcov1.MachineConfigPoolCondition{
+						{
+							Type:    mcov1.MachineConfigPoolNodeDegraded,
+							Status:  corev1.ConditionTrue,
+							Reason:  "failed to apply",
+							Message: "node node1 failed",
+						},
+					}
+					Expect(r.client.Status().Update(context.TODO(), mcp)).ToNot(HaveOccurred())
+					Expect(s.Reconcile(request)).To(Equal(reconcile.Result{}))
+
+					degradedCondition := conditionsv1.FindStatusCondition(getProfile(r).Status.Conditions, conditionsv1.ConditionDegraded)
+					Expect(degradedCondition.Status).To(Equal(corev1.ConditionTrue))
+					Expect(degradedCondition.Reason).To(Equal(conditionReasonMCPDegraded))
+
+					// the pool recovered
+					mcp.Status.Conditions = nil
+					Expect(r.client.Status().Update(context.TODO(), mcp)).ToNot(HaveOccurred())
+					Expect(s.Reconcile(request)).To(Equal(reconcile.Result{}))
+
+					degradedCondition = conditionsv1.FindStatusCondition(getProfile(r).Status.Conditions, conditionsv1.ConditionDegraded)
+					Expect(degradedCondition.Status).To(Equal(corev1.ConditionFalse))
+				})
+
+				It("should keep conditions reported by the full reconcile loop", func() {
+					profile.Status.Conditions = (&ReconcilePerformanceProfile{}).getDegradedConditions(conditionReasonValidationFailed, "invalid profile")
+					r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, mcp)
+					s := &statusSyncReconciler{r: r}
+
+					Expect(s.Reconcile(request)).To(Equal(reconcile.Result{}))
+
+					updatedProfile := getProfile(r)
+					Expect(updatedProfile.Status.RebootRequired).To(BeTrue())
+					degradedCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionsv1.ConditionDegraded)
+					Expect(degradedCondition.Reason).To(Equal(conditionReasonValidationFailed))
+				})
+
+				It("should sync the status only on machine config pool status changes", func() {
+					updatedMCP := mcp.DeepCopy()
+					updatedMCP.Labels = map[string]string{"custom": "label"}
+					Expect(isMCPStatusChanged(event.UpdateEvent{ObjectOld: mcp, ObjectNew: updatedMCP})).To(BeFalse())
+
+					updatedMCP.Status.UpdatedMachineCount = 2
+					Expect(isMCPStatusChanged(event.UpdateEvent{ObjectOld: mcp, ObjectNew: updatedMCP})).To(BeTrue())
+				})
+			})
+
+			Context("with the kernel type change", func() {
+				var mcp *mcov1.MachineConfigPool
+
+				BeforeEach(func() {
+					// the pool still applies the previous machine config
+					mcp = &mcov1.MachineConfigPool{
+						TypeMeta: metav1.TypeMeta{
+							APIVersion: mcov1.GroupVersion.String(),
+							Kind:       "MachineConfigPool",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "mcp-test",
+						},
+						Spec: mcov1.MachineConfigPoolSpec{
+							MachineConfigSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{testutils.MachineConfigPoolLabelKey: testutils.MachineConfigPoolLabelValue},
+							},
+							Configuration: mcov1.MachineConfigPoolStatusConfiguration{
+								ObjectReference: corev1.ObjectReference{Name: "rendered-new"},
+							},
+						},
+						Status: mcov1.MachineConfigPoolStatus{
+							Configuration: mcov1.MachineConfigPoolStatusConfiguration{
+								ObjectReference: corev1.ObjectReference{Name: "rendered-old"},
+							},
+							MachineCount:        2,
+							UpdatedMachineCount: 1,
+						},
+					}
+				})
+
+				table.DescribeTable("should wait until MCP applies the current machine config",
+					func(existingKernelType string, realTimeKernel bool) {
+						mc.Spec.KernelType = existingKernelType
+						profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(realTimeKernel)
+						desiredKernelType := machineconfig.GetKernelType(profile)
+						r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, mcp)
+
+						Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+						key := types.NamespacedName{Name: mc.Name, Namespace: metav1.NamespaceNone}
+						updatedMC := &mcov1.MachineConfig{}
+						Expect(r.client.Get(context.TODO(), key, updatedMC)).ToNot(HaveOccurred())
+						Expect(updatedMC.Spec.KernelType).To(Equal(existingKernelType))
+
+						updatedProfile := &performancev1.PerformanceProfile{}
+						Expect(r.client.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+						progressingCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionsv1.ConditionProgressing)
+						Expect(progressingCondition).ToNot(BeNil())
+						Expect(progressingCondition.Status).To(Equal(corev1.ConditionTrue))
+						Expect(progressingCondition.Reason).To(Equal(conditionReasonKernelTypeTransitionPending))
+						Expect(progressingCondition.Message).To(ContainSubstring(fmt.Sprintf("kernel type change from %q to %q", existingKernelType, desiredKernelType)))
+
+						// the pool applied the current machine config
+						mcp.Status.Configuration = mcov1.MachineConfigPoolStatusConfiguration{
+							ObjectReference: corev1.ObjectReference{Name: "rendered-new"},
+							Source:          []corev1.ObjectReference{{Name: mc.Name}},
+						}
+						mcp.Status.UpdatedMachineCount = 2
+						Expect(r.client.Status().Update(context.TODO(), mcp)).ToNot(HaveOccurred())
+						Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+						updatedMC = &mcov1.MachineConfig{}
+						Expect(r.client.Get(context.TODO(), key, updatedMC)).ToNot(HaveOccurred())
+						Expect(updatedMC.Spec.KernelType).To(Equal(desiredKernelType))
+
+						fakeRecorder, ok := r.recorder.(*record.FakeRecorder)
+						Expect(ok).To(BeTrue())
+						event := <-fakeRecorder.Events
+						Expect(event).To(ContainSubstring("RebootTriggered"))
+						Expect(event).To(ContainSubstring(fmt.Sprintf("kernel type change from %q to %q", existingKernelType, desiredKernelType)))
+					},
+					table.Entry("from the default to the real time kernel", machineconfig.MCKernelDefault, true),
+					table.Entry("from the real time to the default kernel", machineconfig.MCKernelRT, false),
+				)
+
+				It("should reject the transition from the unsupported kernel type", func() {
+					mc.Spec.KernelType = "64k-pages"
+					r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, mcp)
+
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+					updatedProfile := &performancev1.PerformanceProfile{}
+					Expect(r.client.Get(context.TODO(), request.NamespacedName, updatedProfile)).ToNot(HaveOccurred())
+					degradedCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionsv1.ConditionDegraded)
+					Expect(degradedCondition.Status).To(Equal(corev1.ConditionTrue))
+					Expect(degradedCondition.Reason).To(Equal(conditionReasonValidationFailed))
+					Expect(degradedCondition.Message).To(ContainSubstring(`the kernel type transition from "64k-pages" to "realtime" is not supported`))
+				})
+			})
+
+			It("should not record the reboot event when the change does not require the reboot", func() {
+				profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(true)
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance)
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				fakeRecorder, ok := r.recorder.(*record.FakeRecorder)
+				Expect(ok).To(BeTrue())
+				event := <-fakeRecorder.Events
+				Expect(event).ToNot(ContainSubstring("RebootTriggered"))
+				Expect(event).To(ContainSubstring("Creation succeeded"))
+			})
+
+			It("should report drifted components without updating them when drift detection only annotation is set", func() {
+				profile.Annotations = map[string]string{
+					performancev1.PerformanceProfileDriftDetectionOnlyAnnotation: "true",
+				}
+				mc.Spec.KernelType = machineconfig.MCKernelDefault
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance)
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedProfile := &performancev1.PerformanceProfile{}
+				key := types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				Expect(r.client.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+
+				driftedCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionTypeDrifted)
+				Expect(driftedCondition).ToNot(BeNil())
+				Expect(driftedCondition.Status).To(Equal(corev1.ConditionTrue))
+				Expect(driftedCondition.Reason).To(Equal(conditionReasonComponentsDrifted))
+				Expect(driftedCondition.Message).To(ContainSubstring(fmt.Sprintf("MachineConfig %q differs in kernelType", mc.Name)))
+
+				// verify that the machine config was not reverted
+				key.Name = mc.Name
+				updatedMC := &mcov1.MachineConfig{}
+				Expect(r.client.Get(context.TODO(), key, updatedMC)).ToNot(HaveOccurred())
+				Expect(updatedMC.Spec.KernelType).To(Equal(machineconfig.MCKernelDefault))
+			})
+
+			It("should report no drift when components match the profile", func() {
+				profile.Annotations = map[string]string{
+					performancev1.PerformanceProfileDriftDetectionOnlyAnnotation: "true",
+				}
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance)
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedProfile := &performancev1.PerformanceProfile{}
+				key := types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				Expect(r.client.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+
+				driftedCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionTypeDrifted)
+				Expect(driftedCondition).ToNot(BeNil())
+				Expect(driftedCondition.Status).To(Equal(corev1.ConditionFalse))
+			})
+
+			It("should update MC with the same content when the force sync annotation is set", func() {
+				profile.Annotations = map[string]string{
+					performancev1.PerformanceProfileForceSyncAnnotation: "2020-10-16T10:00:00Z",
+				}
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass)
+
+				key := types.NamespacedName{
+					Name:      mc.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				existingMC := &mcov1.MachineConfig{}
+				Expect(r.client.Get(context.TODO(), key, existingMC)).ToNot(HaveOccurred())
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedMC := &mcov1.MachineConfig{}
+				Expect(r.client.Get(context.TODO(), key, updatedMC)).ToNot(HaveOccurred())
+				Expect(updatedMC.ResourceVersion).ToNot(Equal(existingMC.ResourceVersion))
+				Expect(updatedMC.Spec).To(Equal(existingMC.Spec))
+				Expect(updatedMC.Annotations).To(HaveKeyWithValue(performancev1.PerformanceProfileForceSyncAnnotation, "2020-10-16T10:00:00Z"))
+
+				// verify that the force sync annotation was removed from the profile
+				updatedProfile := &performancev1.PerformanceProfile{}
+				key.Name = profile.Name
+				Expect(r.client.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+				Expect(updatedProfile.Annotations).ToNot(HaveKey(performancev1.PerformanceProfileForceSyncAnnotation))
+
+				// verify that the next reconcile does not update MC again
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+				syncedMC := &mcov1.MachineConfig{}
+				key.Name = mc.Name
+				Expect(r.client.Get(context.TODO(), key, syncedMC)).ToNot(HaveOccurred())
+				Expect(syncedMC.ResourceVersion).To(Equal(updatedMC.ResourceVersion))
+			})
+
 			It("should update MC when RT kernel gets disabled", func() {
 				profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
 				r := newFakeReconciler(profile, mc, kc, tunedPerformance)
@@ -453,31 +1662,127 @@ var _ = Describe("Controller", func() {
 				t := &tunedv1.Tuned{}
 				err := r.client.Get(context.TODO(), key, t)
 				Expect(err).ToNot(HaveOccurred())
-				cmdlineHugepages := regexp.MustCompile(`\s*cmdline_hugepages=\+\s*`)
-				Expect(cmdlineHugepages.MatchString(*t.Spec.Profile[0].Data)).To(BeTrue())
+				cmdlineHugepages := regexp.MustCompile(`\s*cmdline_hugepages=\+\s*`)
+				Expect(cmdlineHugepages.MatchString(*t.Spec.Profile[0].Data)).To(BeTrue())
+
+				By("Verifying MC update")
+				key = types.NamespacedName{
+					Name:      components.GetComponentName(profile.Name, components.ComponentNamePrefix),
+					Namespace: metav1.NamespaceNone,
+				}
+				mc := &mcov1.MachineConfig{}
+				err = r.client.Get(context.TODO(), key, mc)
+				Expect(err).ToNot(HaveOccurred())
+
+				config := &igntypes.Config{}
+				err = json.Unmarshal(mc.Spec.Config.Raw, config)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(config.Systemd.Units).To(ContainElement(MatchFields(IgnoreMissing|IgnoreExtras, Fields{
+					"Contents": And(
+						ContainSubstring("Description=Hugepages"),
+						ContainSubstring("Environment=HUGEPAGES_COUNT=8"),
+						ContainSubstring("Environment=HUGEPAGES_SIZE=2048"),
+						ContainSubstring("Environment=NUMA_NODE=0"),
+					),
+				})))
+
+			})
+
+			It("should add profile generation annotation to the existing MC", func() {
+				profile.Generation = 2
+				mc.Annotations = nil
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass)
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				key := types.NamespacedName{
+					Name:      components.GetComponentName(profile.Name, components.ComponentNamePrefix),
+					Namespace: metav1.NamespaceNone,
+				}
+				updatedMC := &mcov1.MachineConfig{}
+				Expect(r.client.Get(context.TODO(), key, updatedMC)).ToNot(HaveOccurred())
+				Expect(updatedMC.Annotations).To(HaveKeyWithValue(machineconfig.ProfileGenerationAnnotation, "2"))
+			})
+
+			It("should not update the MC when only the profile generation changes", func() {
+				profile.Generation = 2
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass)
+
+				key := types.NamespacedName{
+					Name:      components.GetComponentName(profile.Name, components.ComponentNamePrefix),
+					Namespace: metav1.NamespaceNone,
+				}
+				existingMC := &mcov1.MachineConfig{}
+				Expect(r.client.Get(context.TODO(), key, existingMC)).ToNot(HaveOccurred())
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedMC := &mcov1.MachineConfig{}
+				Expect(r.client.Get(context.TODO(), key, updatedMC)).ToNot(HaveOccurred())
+				Expect(updatedMC.ResourceVersion).To(Equal(existingMC.ResourceVersion))
+				Expect(updatedMC.Annotations).ToNot(HaveKeyWithValue(machineconfig.ProfileGenerationAnnotation, "2"))
+			})
+
+			It("should remove operator annotations the MC does not have anymore", func() {
+				mc.Annotations[performancev1.PerformanceProfileForceSyncAnnotation] = "2020-10-16T10:00:00Z"
+				mc.Annotations["performance.openshift.io/previous-config"] = "{}"
+				mc.Annotations[rolledBackGenerationAnnotation] = "1"
+				mc.Annotations["user-annotation"] = "value"
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass)
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				key := types.NamespacedName{
+					Name:      components.GetComponentName(profile.Name, components.ComponentNamePrefix),
+					Namespace: metav1.NamespaceNone,
+				}
+				updatedMC := &mcov1.MachineConfig{}
+				Expect(r.client.Get(context.TODO(), key, updatedMC)).ToNot(HaveOccurred())
+				Expect(updatedMC.Annotations).ToNot(HaveKey("performance.openshift.io/previous-config"))
+				Expect(updatedMC.Annotations).To(HaveKeyWithValue(performancev1.PerformanceProfileForceSyncAnnotation, "2020-10-16T10:00:00Z"))
+				Expect(updatedMC.Annotations).To(HaveKeyWithValue(rolledBackGenerationAnnotation, "1"))
+				Expect(updatedMC.Annotations).To(HaveKeyWithValue("user-annotation", "value"))
+				Expect(updatedMC.Annotations).To(HaveKeyWithValue(components.IsolatedCPUsAnnotation, "4-7"))
+			})
+
+			It("should update only MC when NUMA specific hugepages count changes", func() {
+				profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(0)
+
+				var err error
+				mc, err = machineconfig.New(assetsDir, profile)
+				Expect(err).ToNot(HaveOccurred())
+				tunedPerformance, err = tuned.NewNodePerformance(assetsDir, profile)
+				Expect(err).ToNot(HaveOccurred())
 
-				By("Verifying MC update")
-				key = types.NamespacedName{
+				profile.Spec.HugePages.Pages[0].Count = 8
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass)
+
+				mcKey := types.NamespacedName{
 					Name:      components.GetComponentName(profile.Name, components.ComponentNamePrefix),
 					Namespace: metav1.NamespaceNone,
 				}
-				mc := &mcov1.MachineConfig{}
-				err = r.client.Get(context.TODO(), key, mc)
-				Expect(err).ToNot(HaveOccurred())
+				tunedKey := types.NamespacedName{
+					Name:      components.GetComponentName(profile.Name, components.ProfileNamePerformance),
+					Namespace: components.NamespaceNodeTuningOperator,
+				}
 
-				config := &igntypes.Config{}
-				err = json.Unmarshal(mc.Spec.Config.Raw, config)
-				Expect(err).ToNot(HaveOccurred())
+				existingMC := &mcov1.MachineConfig{}
+				Expect(r.client.Get(context.TODO(), mcKey, existingMC)).ToNot(HaveOccurred())
+				existingTuned := &tunedv1.Tuned{}
+				Expect(r.client.Get(context.TODO(), tunedKey, existingTuned)).ToNot(HaveOccurred())
 
-				Expect(config.Systemd.Units).To(ContainElement(MatchFields(IgnoreMissing|IgnoreExtras, Fields{
-					"Contents": And(
-						ContainSubstring("Description=Hugepages"),
-						ContainSubstring("Environment=HUGEPAGES_COUNT=8"),
-						ContainSubstring("Environment=HUGEPAGES_SIZE=2048"),
-						ContainSubstring("Environment=NUMA_NODE=0"),
-					),
-				})))
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
 
+				By("Verifying MC update")
+				updatedMC := &mcov1.MachineConfig{}
+				Expect(r.client.Get(context.TODO(), mcKey, updatedMC)).ToNot(HaveOccurred())
+				Expect(updatedMC.ResourceVersion).ToNot(Equal(existingMC.ResourceVersion))
+
+				By("Verifying Tuned was not updated")
+				updatedTuned := &tunedv1.Tuned{}
+				Expect(r.client.Get(context.TODO(), tunedKey, updatedTuned)).ToNot(HaveOccurred())
+				Expect(updatedTuned.ResourceVersion).To(Equal(existingTuned.ResourceVersion))
 			})
 
 			It("should update status with generated tuned", func() {
@@ -501,6 +1806,36 @@ var _ = Describe("Controller", func() {
 				Expect(*updatedProfile.Status.Tuned).To(Equal(tunedNamespacedName))
 			})
 
+			It("should update status with the isolated CPUs count", func() {
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance)
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedProfile := &performancev1.PerformanceProfile{}
+				key := types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				Expect(r.client.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+				Expect(updatedProfile.Status.IsolatedCPUCount).NotTo(BeNil())
+				Expect(*updatedProfile.Status.IsolatedCPUCount).To(Equal(int32(4)))
+			})
+
+			It("should update status with housekeeping CPUs", func() {
+				irqExclude := performancev1.CPUSet("1")
+				profile.Spec.CPU.IRQExclude = &irqExclude
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance)
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedProfile := &performancev1.PerformanceProfile{}
+				key := types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				Expect(r.client.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+				Expect(updatedProfile.Status.HousekeepingCPUs).NotTo(BeNil())
+				Expect(*updatedProfile.Status.HousekeepingCPUs).To(Equal(performancev1.CPUSet("0,2-3")))
+			})
+
 			It("should update status with generated runtime class", func() {
 				r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass)
 				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
@@ -579,6 +1914,404 @@ var _ = Describe("Controller", func() {
 				Expect(degradedCondition.Reason).To(Equal(conditionReasonMCPDegraded))
 				Expect(degradedCondition.Message).To(ContainSubstring(mcpMessage))
 			})
+
+			It("should report the pending reboot until MCP applies the machine config", func() {
+				mcp := &mcov1.MachineConfigPool{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: mcov1.GroupVersion.String(),
+						Kind:       "MachineConfigPool",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "mcp-test",
+					},
+					Spec: mcov1.MachineConfigPoolSpec{
+						MachineConfigSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{testutils.MachineConfigPoolLabelKey: testutils.MachineConfigPoolLabelValue},
+						},
+						Configuration: mcov1.MachineConfigPoolStatusConfiguration{
+							ObjectReference: corev1.ObjectReference{Name: "rendered-new"},
+						},
+					},
+					Status: mcov1.MachineConfigPoolStatus{
+						Configuration: mcov1.MachineConfigPoolStatusConfiguration{
+							ObjectReference: corev1.ObjectReference{Name: "rendered-old"},
+						},
+						MachineCount:        2,
+						UpdatedMachineCount: 1,
+					},
+				}
+
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, mcp)
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedProfile := &performancev1.PerformanceProfile{}
+				key := types.NamespacedName{
+					Name:      profile.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				Expect(r.client.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+				Expect(updatedProfile.Status.RebootRequired).To(BeTrue())
+
+				// the pool rendered the profile machine config and updated all nodes
+				mcp.Status.Configuration = mcov1.MachineConfigPoolStatusConfiguration{
+					ObjectReference: corev1.ObjectReference{Name: "rendered-new"},
+					Source:          []corev1.ObjectReference{{Name: mc.Name}},
+				}
+				mcp.Status.UpdatedMachineCount = 2
+				Expect(r.client.Status().Update(context.TODO(), mcp)).ToNot(HaveOccurred())
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				updatedProfile = &performancev1.PerformanceProfile{}
+				Expect(r.client.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+				Expect(updatedProfile.Status.RebootRequired).To(BeFalse())
+			})
+
+			It("should observe the apply duration once MCP is updated", func() {
+				getObservationsCount := func() uint64 {
+					metric := &dto.Metric{}
+					Expect(profileApplyDuration.WithLabelValues(profile.Name).(prometheus.Metric).Write(metric)).ToNot(HaveOccurred())
+					return metric.GetHistogram().GetSampleCount()
+				}
+
+				mcp := &mcov1.MachineConfigPool{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: mcov1.GroupVersion.String(),
+						Kind:       "MachineConfigPool",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "mcp-test",
+					},
+					Spec: mcov1.MachineConfigPoolSpec{
+						MachineConfigSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{testutils.MachineConfigPoolLabelKey: testutils.MachineConfigPoolLabelValue},
+						},
+					},
+					Status: mcov1.MachineConfigPoolStatus{
+						// the pool applied the current machine config, so the kernel type can be changed
+						Configuration: mcov1.MachineConfigPoolStatusConfiguration{
+							Source: []corev1.ObjectReference{{Name: mc.Name}},
+						},
+						Conditions: []mcov1.MachineConfigPoolCondition{
+							{
+								Type:               mcov1.MachineConfigPoolUpdated,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: metav1.Time{Time: time.Now().Add(-time.Hour)},
+							},
+						},
+					},
+				}
+
+				profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, mcp)
+				observations := getObservationsCount()
+
+				// the MCP still reports the updated condition of the previous configuration
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+				Expect(getObservationsCount()).To(Equal(observations))
+
+				mcp.Status.Conditions[0].Status = corev1.ConditionFalse
+				mcp.Status.Conditions[0].LastTransitionTime = metav1.Time{Time: time.Now()}
+				Expect(r.client.Update(context.TODO(), mcp)).ToNot(HaveOccurred())
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+				Expect(getObservationsCount()).To(Equal(observations))
+
+				mcp.Status.Conditions[0].Status = corev1.ConditionTrue
+				mcp.Status.Conditions[0].LastTransitionTime = metav1.Time{Time: time.Now().Add(time.Minute)}
+				Expect(r.client.Update(context.TODO(), mcp)).ToNot(HaveOccurred())
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+				Expect(getObservationsCount()).To(Equal(observations + 1))
+
+				// the converged apply should not be observed again
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+				Expect(getObservationsCount()).To(Equal(observations + 1))
+			})
+
+			getBackupMC := func(r *ReconcilePerformanceProfile) (*mcov1.MachineConfig, error) {
+				key := types.NamespacedName{
+					Name:      getBackupMachineConfigName(mc.Name),
+					Namespace: metav1.NamespaceNone,
+				}
+				backup := &mcov1.MachineConfig{}
+				err := r.client.Get(context.TODO(), key, backup)
+				return backup, err
+			}
+
+			newDegradedMCP := func(degradedSince time.Time) *mcov1.MachineConfigPool {
+				return &mcov1.MachineConfigPool{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: mcov1.GroupVersion.String(),
+						Kind:       "MachineConfigPool",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "mcp-test",
+					},
+					Spec: mcov1.MachineConfigPoolSpec{
+						MachineConfigSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{testutils.MachineConfigPoolLabelKey: testutils.MachineConfigPoolLabelValue},
+						},
+					},
+					Status: mcov1.MachineConfigPoolStatus{
+						Conditions: []mcov1.MachineConfigPoolCondition{
+							{
+								Type:               mcov1.MachineConfigPoolNodeDegraded,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: metav1.Time{Time: degradedSince},
+							},
+						},
+					},
+				}
+			}
+
+			newBackupMC := func(updated time.Time) *mcov1.MachineConfig {
+				backup := mc.DeepCopy()
+				backup.Name = getBackupMachineConfigName(mc.Name)
+				backup.Labels = nil
+				backup.Annotations = map[string]string{configUpdatedAnnotation: updated.UTC().Format(time.RFC3339)}
+				backup.Spec.KernelType = machineconfig.MCKernelDefault
+				return backup
+			}
+
+			It("should keep the previous MC spec under the backup MC when the rollback is enabled", func() {
+				profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance)
+				r.rollbackTimeout = time.Minute
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				key := types.NamespacedName{
+					Name:      mc.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				updatedMC := &mcov1.MachineConfig{}
+				Expect(r.client.Get(context.TODO(), key, updatedMC)).ToNot(HaveOccurred())
+				Expect(updatedMC.Spec.KernelType).To(Equal(machineconfig.MCKernelDefault))
+
+				backup, err := getBackupMC(r)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(backup.Spec.KernelType).To(Equal(machineconfig.MCKernelRT))
+				Expect(backup.Spec.Config).To(Equal(mc.Spec.Config))
+				Expect(backup.Labels).To(BeEmpty())
+				Expect(backup.Annotations).To(HaveKey(configUpdatedAnnotation))
+			})
+
+			It("should keep the previous MC kernel arguments when the backup is enabled", func() {
+				mc.Spec.KernelArguments = []string{"nosmt"}
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance)
+				r.machineConfigBackup = true
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				key := types.NamespacedName{
+					Name:      mc.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				updatedMC := &mcov1.MachineConfig{}
+				Expect(r.client.Get(context.TODO(), key, updatedMC)).ToNot(HaveOccurred())
+				Expect(updatedMC.Spec.KernelArguments).To(BeEmpty())
+
+				backup, err := getBackupMC(r)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(backup.Spec.KernelArguments).To(Equal([]string{"nosmt"}))
+			})
+
+			It("should not keep the previous MC spec by default", func() {
+				profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance)
+
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				_, err := getBackupMC(r)
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			})
+
+			It("should roll back MC when MCP stays degraded longer than the rollback timeout", func() {
+				backup := newBackupMC(time.Now().Add(-10 * time.Minute))
+				mcp := newDegradedMCP(time.Now().Add(-5 * time.Minute))
+
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, mcp, backup)
+				r.rollbackTimeout = time.Minute
+
+				// the second reconcile loop should not apply the rolled back configuration again
+				Expect(reconcileTimes(r, request, 2)).To(Equal(reconcile.Result{}))
+
+				key := types.NamespacedName{
+					Name:      mc.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				updatedMC := &mcov1.MachineConfig{}
+				Expect(r.client.Get(context.TODO(), key, updatedMC)).ToNot(HaveOccurred())
+				Expect(updatedMC.Spec.KernelType).To(Equal(machineconfig.MCKernelDefault))
+				Expect(updatedMC.Annotations[rolledBackGenerationAnnotation]).To(Equal(fmt.Sprintf("%d", profile.Generation)))
+
+				_, err := getBackupMC(r)
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+
+				updatedProfile := &performancev1.PerformanceProfile{}
+				key.Name = profile.Name
+				Expect(r.client.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
+
+				degradedCondition := conditionsv1.FindStatusCondition(updatedProfile.Status.Conditions, conditionsv1.ConditionDegraded)
+				Expect(degradedCondition).ToNot(BeNil())
+				Expect(degradedCondition.Status).To(Equal(corev1.ConditionTrue))
+				Expect(degradedCondition.Reason).To(Equal(conditionReasonMachineConfigRolledBack))
+			})
+
+			It("should count the MCP degradation only after the MC update", func() {
+				// the pool was degraded long before the update was applied
+				backup := newBackupMC(time.Now().Add(-30 * time.Second))
+				mcp := newDegradedMCP(time.Now().Add(-10 * time.Minute))
+
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, mcp, backup)
+				r.rollbackTimeout = time.Minute
+
+				// the pool should be checked again once it stays degraded a minute after the update
+				result := reconcileTimes(r, request, 1)
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+				Expect(result.RequeueAfter).To(BeNumerically("<=", 30*time.Second))
+
+				key := types.NamespacedName{
+					Name:      mc.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				updatedMC := &mcov1.MachineConfig{}
+				Expect(r.client.Get(context.TODO(), key, updatedMC)).ToNot(HaveOccurred())
+				Expect(updatedMC.Spec.KernelType).To(Equal(machineconfig.MCKernelRT))
+				Expect(updatedMC.Annotations).ToNot(HaveKey(rolledBackGenerationAnnotation))
+
+				_, err := getBackupMC(r)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should requeue until MCP stays degraded longer than the rollback timeout", func() {
+				backup := newBackupMC(time.Now().Add(-10 * time.Minute))
+				mcp := newDegradedMCP(time.Now().Add(-20 * time.Second))
+
+				r := newFakeReconciler(profile, mc, kc, tunedPerformance, mcp, backup)
+				r.rollbackTimeout = time.Minute
+
+				result := reconcileTimes(r, request, 1)
+				Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+				Expect(result.RequeueAfter).To(BeNumerically("<=", 40*time.Second))
+
+				_, err := getBackupMC(r)
+				Expect(err).ToNot(HaveOccurred())
+
+				// the requeued reconcile loop rolls back MC once the timeout expires
+				r.rollbackTimeout = 10 * time.Second
+				Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+				key := types.NamespacedName{
+					Name:      mc.Name,
+					Namespace: metav1.NamespaceNone,
+				}
+				updatedMC := &mcov1.MachineConfig{}
+				Expect(r.client.Get(context.TODO(), key, updatedMC)).ToNot(HaveOccurred())
+				Expect(updatedMC.Spec.KernelType).To(Equal(machineconfig.MCKernelDefault))
+				Expect(updatedMC.Annotations[rolledBackGenerationAnnotation]).To(Equal(fmt.Sprintf("%d", profile.Generation)))
+			})
+
+			Context("with coordinated rollout machine config pool", func() {
+				var mcp *mcov1.MachineConfigPool
+				var mcpKey types.NamespacedName
+
+				BeforeEach(func() {
+					mcp = &mcov1.MachineConfigPool{
+						TypeMeta: metav1.TypeMeta{
+							APIVersion: mcov1.GroupVersion.String(),
+							Kind:       "MachineConfigPool",
+						},
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "mcp-test",
+							Annotations: map[string]string{
+								performancev1.MachineConfigPoolCoordinatedRolloutAnnotation: "true",
+							},
+						},
+						Spec: mcov1.MachineConfigPoolSpec{
+							MachineConfigSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{testutils.MachineConfigPoolLabelKey: testutils.MachineConfigPoolLabelValue},
+							},
+						},
+						// the pool applied the current machine config, so the kernel type can be changed
+						Status: mcov1.MachineConfigPoolStatus{
+							Configuration: mcov1.MachineConfigPoolStatusConfiguration{
+								Source: []corev1.ObjectReference{{Name: mc.Name}},
+							},
+						},
+					}
+					mcpKey = types.NamespacedName{
+						Name:      mcp.Name,
+						Namespace: metav1.NamespaceNone,
+					}
+
+					// change the machine config kernel type
+					profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(false)
+				})
+
+				It("should pause MCP while profile changes are applied and unpause it once they settle", func() {
+					r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, mcp)
+
+					result := reconcileTimes(r, request, 1)
+					Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+					Expect(result.RequeueAfter).To(BeNumerically("<=", rolloutSettleDuration))
+
+					updatedMCP := &mcov1.MachineConfigPool{}
+					Expect(r.client.Get(context.TODO(), mcpKey, updatedMCP)).ToNot(HaveOccurred())
+					Expect(updatedMCP.Spec.Paused).To(BeTrue())
+					Expect(updatedMCP.Annotations).To(HaveKey(rolloutPausedAnnotation))
+					Expect(updatedMCP.Annotations).To(HaveKey(rolloutLastChangeAnnotation))
+
+					// the pool should stay paused until the settle duration passes
+					result = reconcileTimes(r, request, 1)
+					Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+					Expect(r.client.Get(context.TODO(), mcpKey, updatedMCP)).ToNot(HaveOccurred())
+					Expect(updatedMCP.Spec.Paused).To(BeTrue())
+
+					lastChange := time.Now().Add(-2 * rolloutSettleDuration).UTC().Format(time.RFC3339)
+					updatedMCP.Annotations[rolloutLastChangeAnnotation] = lastChange
+					Expect(r.client.Update(context.TODO(), updatedMCP)).ToNot(HaveOccurred())
+
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+					updatedMCP = &mcov1.MachineConfigPool{}
+					Expect(r.client.Get(context.TODO(), mcpKey, updatedMCP)).ToNot(HaveOccurred())
+					Expect(updatedMCP.Spec.Paused).To(BeFalse())
+					Expect(updatedMCP.Annotations).ToNot(HaveKey(rolloutPausedAnnotation))
+					Expect(updatedMCP.Annotations).ToNot(HaveKey(rolloutLastChangeAnnotation))
+					Expect(updatedMCP.Annotations).To(HaveKey(performancev1.MachineConfigPoolCoordinatedRolloutAnnotation))
+
+					fakeRecorder, ok := r.recorder.(*record.FakeRecorder)
+					Expect(ok).To(BeTrue())
+					var events []string
+					for len(fakeRecorder.Events) > 0 {
+						events = append(events, <-fakeRecorder.Events)
+					}
+					Expect(events).To(ContainElement(ContainSubstring("RolloutPaused")))
+					Expect(events).To(ContainElement(ContainSubstring("RolloutResumed")))
+				})
+
+				It("should not unpause MCP paused by a user", func() {
+					mcp.Spec.Paused = true
+					r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, mcp)
+
+					Expect(reconcileTimes(r, request, 2)).To(Equal(reconcile.Result{}))
+
+					updatedMCP := &mcov1.MachineConfigPool{}
+					Expect(r.client.Get(context.TODO(), mcpKey, updatedMCP)).ToNot(HaveOccurred())
+					Expect(updatedMCP.Spec.Paused).To(BeTrue())
+					Expect(updatedMCP.Annotations).ToNot(HaveKey(rolloutPausedAnnotation))
+					Expect(updatedMCP.Annotations).ToNot(HaveKey(rolloutLastChangeAnnotation))
+				})
+
+				It("should not pause MCP without the coordinated rollout annotation", func() {
+					mcp.Annotations = nil
+					r := newFakeReconciler(profile, mc, kc, tunedPerformance, runtimeClass, mcp)
+
+					Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+					updatedMCP := &mcov1.MachineConfigPool{}
+					Expect(r.client.Get(context.TODO(), mcpKey, updatedMCP)).ToNot(HaveOccurred())
+					Expect(updatedMCP.Spec.Paused).To(BeFalse())
+				})
+			})
 		})
 
 	})
@@ -641,6 +2374,74 @@ var _ = Describe("Controller", func() {
 			Expect(r.client.Get(context.TODO(), key, updatedProfile)).ToNot(HaveOccurred())
 			Expect(hasFinalizer(updatedProfile, finalizer)).To(Equal(false))
 		})
+
+		It("should unpause the machine config pool paused by the operator in the middle of the rollout", func() {
+			newPausedMCP := func(name string, annotations map[string]string) *mcov1.MachineConfigPool {
+				return &mcov1.MachineConfigPool{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: mcov1.GroupVersion.String(),
+						Kind:       "MachineConfigPool",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        name,
+						Annotations: annotations,
+					},
+					Spec: mcov1.MachineConfigPoolSpec{
+						MachineConfigSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{testutils.MachineConfigPoolLabelKey: testutils.MachineConfigPoolLabelValue},
+						},
+						Paused: true,
+					},
+				}
+			}
+			// the profile is deleted before its changes settle
+			operatorPausedMCP := newPausedMCP("mcp-operator-paused", map[string]string{
+				performancev1.MachineConfigPoolCoordinatedRolloutAnnotation: "true",
+				rolloutPausedAnnotation:     "true",
+				rolloutLastChangeAnnotation: time.Now().UTC().Format(time.RFC3339),
+			})
+			userPausedMCP := newPausedMCP("mcp-user-paused", map[string]string{
+				performancev1.MachineConfigPoolCoordinatedRolloutAnnotation: "true",
+			})
+
+			r := newFakeReconciler(profile, operatorPausedMCP, userPausedMCP)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			updatedMCP := &mcov1.MachineConfigPool{}
+			key := types.NamespacedName{
+				Name:      operatorPausedMCP.Name,
+				Namespace: metav1.NamespaceNone,
+			}
+			Expect(r.client.Get(context.TODO(), key, updatedMCP)).ToNot(HaveOccurred())
+			Expect(updatedMCP.Spec.Paused).To(BeFalse())
+			Expect(updatedMCP.Annotations).ToNot(HaveKey(rolloutPausedAnnotation))
+			Expect(updatedMCP.Annotations).ToNot(HaveKey(rolloutLastChangeAnnotation))
+
+			key.Name = userPausedMCP.Name
+			Expect(r.client.Get(context.TODO(), key, updatedMCP)).ToNot(HaveOccurred())
+			Expect(updatedMCP.Spec.Paused).To(BeTrue())
+		})
+
+		It("should remove the profile label from nodes", func() {
+			profile.Annotations = map[string]string{performancev1.PerformanceProfileNodeLabelAnnotation: "true"}
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node1",
+					Labels: map[string]string{
+						"nodekey": "nodeValue",
+						performancev1.PerformanceProfileNodeLabel: profile.Name,
+					},
+				},
+			}
+
+			r := newFakeReconciler(profile, node)
+			Expect(reconcileTimes(r, request, 1)).To(Equal(reconcile.Result{}))
+
+			updatedNode := &corev1.Node{}
+			Expect(r.client.Get(context.TODO(), types.NamespacedName{Name: node.Name}, updatedNode)).ToNot(HaveOccurred())
+			Expect(updatedNode.Labels).ToNot(HaveKey(performancev1.PerformanceProfileNodeLabel))
+			Expect(updatedNode.Labels).To(HaveKeyWithValue("nodekey", "nodeValue"))
+		})
 	})
 })
 
@@ -659,9 +2460,10 @@ func newFakeReconciler(initObjects ...runtime.Object) *ReconcilePerformanceProfi
 	fakeClient := fake.NewFakeClientWithScheme(scheme.Scheme, initObjects...)
 	fakeRecorder := record.NewFakeRecorder(10)
 	return &ReconcilePerformanceProfile{
-		client:    fakeClient,
-		scheme:    scheme.Scheme,
-		recorder:  fakeRecorder,
-		assetsDir: assetsDir,
+		client:           fakeClient,
+		scheme:           scheme.Scheme,
+		recorder:         fakeRecorder,
+		assetsDir:        assetsDir,
+		applyTimeTracker: newApplyTimeTracker(),
 	}
 }
@@ -0,0 +1,47 @@
+package performanceprofile
+
+import (
+	"context"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// nodesPodLister lists pods scheduled to the profile nodes, the reader should not be cached,
+// because the validation lists pods rarely and caching pods of all namespaces costs a lot of memory
+type nodesPodLister struct {
+	client client.Reader
+}
+
+// ListPods returns pods of all namespaces scheduled to nodes selected by the profile node selector
+func (l *nodesPodLister) ListPods(profile *performancev1.PerformanceProfile) ([]corev1.Pod, error) {
+	nodes := &corev1.NodeList{}
+	if err := l.client.List(context.TODO(), nodes, client.MatchingLabels(profile.Spec.NodeSelector)); err != nil {
+		return nil, err
+	}
+
+	if len(nodes.Items) == 0 {
+		return nil, nil
+	}
+
+	nodeNames := map[string]bool{}
+	for _, node := range nodes.Items {
+		nodeNames[node.Name] = true
+	}
+
+	pods := &corev1.PodList{}
+	if err := l.client.List(context.TODO(), pods); err != nil {
+		return nil, err
+	}
+
+	var nodePods []corev1.Pod
+	for _, pod := range pods.Items {
+		if nodeNames[pod.Spec.NodeName] {
+			nodePods = append(nodePods, pod)
+		}
+	}
+	return nodePods, nil
+}
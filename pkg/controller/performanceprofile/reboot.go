@@ -0,0 +1,76 @@
+package performanceprofile
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/tuned"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	rebootReasonKernelArgs = "kernel arguments change"
+	rebootReasonIsolcpus   = "isolcpus change"
+	rebootReasonMCConfig   = "machine config files change"
+)
+
+// getRebootReasons returns reasons of the nodes reboot caused by the update of the existing machine config and tuned,
+// nil objects are skipped, the creation of objects is not reported
+func (r *ReconcilePerformanceProfile) getRebootReasons(mc *mcov1.MachineConfig, performanceTuned *tunedv1.Tuned) ([]string, error) {
+	var reasons []string
+	addReason := func(reason string) {
+		for _, existing := range reasons {
+			if existing == reason {
+				return
+			}
+		}
+		reasons = append(reasons, reason)
+	}
+
+	if mc != nil {
+		existing, err := r.getMachineConfig(mc.Name)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+
+		if existing != nil {
+			if existing.Spec.KernelType != mc.Spec.KernelType {
+				addReason(fmt.Sprintf("kernel type change from %q to %q", existing.Spec.KernelType, mc.Spec.KernelType))
+			}
+			if !reflect.DeepEqual(existing.Spec.KernelArguments, mc.Spec.KernelArguments) {
+				addReason(rebootReasonKernelArgs)
+			}
+			if !reflect.DeepEqual(existing.Spec.Config.Raw, mc.Spec.Config.Raw) {
+				addReason(rebootReasonMCConfig)
+			}
+		}
+	}
+
+	if performanceTuned != nil {
+		existing, err := r.getTuned(performanceTuned.Name, performanceTuned.Namespace)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, err
+		}
+
+		if existing != nil {
+			existingOptions := tuned.GetKernelCmdlineOptions(existing)
+			options := tuned.GetKernelCmdlineOptions(performanceTuned)
+			for _, name := range []string{tuned.VariableIsolatedCores, tuned.CmdlineRealtime} {
+				if existingOptions[name] != options[name] {
+					addReason(rebootReasonIsolcpus)
+				}
+				delete(existingOptions, name)
+				delete(options, name)
+			}
+
+			if !reflect.DeepEqual(existingOptions, options) {
+				addReason(rebootReasonKernelArgs)
+			}
+		}
+	}
+
+	return reasons, nil
+}
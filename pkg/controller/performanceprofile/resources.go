@@ -4,17 +4,29 @@ import (
 	"context"
 	"encoding/json"
 	"reflect"
-
+	"strings"
+	"time"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/machineconfig"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/machineconfigpool"
+	profileutil "github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/profile"
 	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
 	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 
+	appsv1 "k8s.io/api/apps/v1"
 	nodev1beta1 "k8s.io/api/node/v1beta1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog"
 	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 func mergeMaps(src map[string]string, dst map[string]string) {
@@ -24,6 +36,49 @@ func mergeMaps(src map[string]string, dst map[string]string) {
 	}
 }
 
+// removeStaleOperatorAnnotations removes the operator annotations of the mutated object that the desired object
+// does not have anymore, the preserved annotations are set by the controller outside of the object generation
+func removeStaleOperatorAnnotations(desired map[string]string, mutated map[string]string, preserved ...string) {
+	keep := map[string]bool{}
+	for _, k := range preserved {
+		keep[k] = true
+	}
+
+	prefix := performancev1.SchemeGroupVersion.Group + "/"
+	for k := range mutated {
+		if _, ok := desired[k]; !ok && !keep[k] && strings.HasPrefix(k, prefix) {
+			delete(mutated, k)
+		}
+	}
+}
+
+// withoutAnnotation returns the copy of annotations without the specified annotation
+func withoutAnnotation(annotations map[string]string, key string) map[string]string {
+	copied := map[string]string{}
+	for k, v := range annotations {
+		if k != key {
+			copied[k] = v
+		}
+	}
+	return copied
+}
+
+// syncIsolatedCPUGroupsAnnotation copies the isolated CPU groups annotation of the desired object to the mutated one,
+// the annotation is removed once the profile does not have isolated groups anymore
+func syncIsolatedCPUGroupsAnnotation(desired metav1.Object, mutated metav1.Object) {
+	annotations := mutated.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	if groups, ok := desired.GetAnnotations()[components.IsolatedCPUGroupsAnnotation]; ok {
+		annotations[components.IsolatedCPUGroupsAnnotation] = groups
+	} else {
+		delete(annotations, components.IsolatedCPUGroupsAnnotation)
+	}
+	mutated.SetAnnotations(annotations)
+}
+
 // TODO: we should merge all create, get and delete methods
 
 func (r *ReconcilePerformanceProfile) getMachineConfig(name string) (*mcov1.MachineConfig, error) {
@@ -49,14 +104,25 @@ func (r *ReconcilePerformanceProfile) getMutatedMachineConfig(mc *mcov1.MachineC
 	}
 
 	mutated := existing.DeepCopy()
+	// machine configs created by older versions of the operator do not have annotations
+	if mutated.Annotations == nil {
+		mutated.Annotations = map[string]string{}
+	}
 	mergeMaps(mc.Annotations, mutated.Annotations)
+	// the force sync annotation stays once the profile annotation is removed, so the next sync does not update it again
+	removeStaleOperatorAnnotations(mc.Annotations, mutated.Annotations,
+		performancev1.PerformanceProfileForceSyncAnnotation, rolledBackGenerationAnnotation)
 	mergeMaps(mc.Labels, mutated.Labels)
 	mutated.Spec = mc.Spec
 
-	// we do not need to update if it no change between mutated and existing object
+	// we do not need to update if it no change between mutated and existing object,
+	// the profile generation alone does not change the machine config, it is updated together with other changes
 	if reflect.DeepEqual(existing.Spec, mutated.Spec) &&
 		apiequality.Semantic.DeepEqual(existing.Labels, mutated.Labels) &&
-		apiequality.Semantic.DeepEqual(existing.Annotations, mutated.Annotations) {
+		apiequality.Semantic.DeepEqual(
+			withoutAnnotation(existing.Annotations, machineconfig.ProfileGenerationAnnotation),
+			withoutAnnotation(mutated.Annotations, machineconfig.ProfileGenerationAnnotation),
+		) {
 		return nil, nil
 	}
 
@@ -64,7 +130,7 @@ func (r *ReconcilePerformanceProfile) getMutatedMachineConfig(mc *mcov1.MachineC
 }
 
 func (r *ReconcilePerformanceProfile) createOrUpdateMachineConfig(mc *mcov1.MachineConfig) error {
-	_, err := r.getMachineConfig(mc.Name)
+	existing, err := r.getMachineConfig(mc.Name)
 	if errors.IsNotFound(err) {
 		klog.Infof("Create machine-config %q", mc.Name)
 		if err := r.client.Create(context.TODO(), mc); err != nil {
@@ -77,6 +143,12 @@ func (r *ReconcilePerformanceProfile) createOrUpdateMachineConfig(mc *mcov1.Mach
 		return err
 	}
 
+	if r.shouldRememberPreviousConfig() && !reflect.DeepEqual(existing.Spec, mc.Spec) {
+		if err := r.backupMachineConfig(existing, mc, time.Now()); err != nil {
+			return err
+		}
+	}
+
 	klog.Infof("Update machine-config %q", mc.Name)
 	return r.client.Update(context.TODO(), mc)
 }
@@ -194,6 +266,7 @@ func (r *ReconcilePerformanceProfile) getMutatedTuned(tuned *tunedv1.Tuned) (*tu
 	}
 
 	mutated := existing.DeepCopy()
+	syncIsolatedCPUGroupsAnnotation(tuned, mutated)
 	mergeMaps(tuned.Annotations, mutated.Annotations)
 	mergeMaps(tuned.Labels, mutated.Labels)
 	mutated.Spec = tuned.Spec
@@ -285,6 +358,7 @@ func (r *ReconcilePerformanceProfile) getMutatedRuntimeClass(runtimeClass *nodev
 	}
 
 	mutated := existing.DeepCopy()
+	syncIsolatedCPUGroupsAnnotation(runtimeClass, mutated)
 	mergeMaps(runtimeClass.Annotations, mutated.Annotations)
 	mergeMaps(runtimeClass.Labels, mutated.Labels)
 	mutated.Handler = runtimeClass.Handler
@@ -329,3 +403,324 @@ func (r *ReconcilePerformanceProfile) deleteRuntimeClass(name string) error {
 	}
 	return r.client.Delete(context.TODO(), runtimeClass)
 }
+
+func (r *ReconcilePerformanceProfile) getPriorityClass(name string) (*schedulingv1.PriorityClass, error) {
+	priorityClass := &schedulingv1.PriorityClass{}
+	key := types.NamespacedName{
+		Name: name,
+	}
+	if err := r.client.Get(context.TODO(), key, priorityClass); err != nil {
+		return nil, err
+	}
+	return priorityClass, nil
+}
+
+func (r *ReconcilePerformanceProfile) getMutatedPriorityClass(priorityClass *schedulingv1.PriorityClass) (*schedulingv1.PriorityClass, error) {
+	existing, err := r.getPriorityClass(priorityClass.Name)
+	if errors.IsNotFound(err) {
+		return priorityClass, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	mutated := existing.DeepCopy()
+	mergeMaps(priorityClass.Annotations, mutated.Annotations)
+	mergeMaps(priorityClass.Labels, mutated.Labels)
+	mutated.Value = priorityClass.Value
+	mutated.GlobalDefault = priorityClass.GlobalDefault
+	mutated.Description = priorityClass.Description
+
+	// we do not need to update if it no change between mutated and existing object
+	if existing.Value == mutated.Value &&
+		existing.GlobalDefault == mutated.GlobalDefault &&
+		existing.Description == mutated.Description &&
+		apiequality.Semantic.DeepEqual(existing.Labels, mutated.Labels) &&
+		apiequality.Semantic.DeepEqual(existing.Annotations, mutated.Annotations) {
+		return nil, nil
+	}
+
+	return mutated, nil
+}
+
+func (r *ReconcilePerformanceProfile) createOrUpdatePriorityClass(priorityClass *schedulingv1.PriorityClass) error {
+	existing, err := r.getPriorityClass(priorityClass.Name)
+	if errors.IsNotFound(err) {
+		klog.Infof("Create priority class %q", priorityClass.Name)
+		if err := r.client.Create(context.TODO(), priorityClass); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// the priority class value is immutable, so we should re-create the object to change it
+	if existing.Value != priorityClass.Value {
+		klog.Infof("Re-create priority class %q with the new value %d", priorityClass.Name, priorityClass.Value)
+		if err := r.client.Delete(context.TODO(), existing); err != nil {
+			return err
+		}
+		priorityClass.ResourceVersion = ""
+		return r.client.Create(context.TODO(), priorityClass)
+	}
+
+	klog.Infof("Update priority class %q", priorityClass.Name)
+	return r.client.Update(context.TODO(), priorityClass)
+}
+
+func (r *ReconcilePerformanceProfile) deletePriorityClass(name string) error {
+	priorityClass, err := r.getPriorityClass(name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return r.client.Delete(context.TODO(), priorityClass)
+}
+
+func (r *ReconcilePerformanceProfile) getDaemonSet(name, namespace string) (*appsv1.DaemonSet, error) {
+	ds := &appsv1.DaemonSet{}
+	key := types.NamespacedName{
+		Name:      name,
+		Namespace: namespace,
+	}
+	if err := r.client.Get(context.TODO(), key, ds); err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+func (r *ReconcilePerformanceProfile) getMutatedDaemonSet(ds *appsv1.DaemonSet) (*appsv1.DaemonSet, error) {
+	existing, err := r.getDaemonSet(ds.Name, ds.Namespace)
+	if errors.IsNotFound(err) {
+		return ds, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the API server sets defaults under the pod template, so we should update only fields
+	// generated by the operator to avoid endless updates
+	mutated := existing.DeepCopy()
+	mergeMaps(ds.Annotations, mutated.Annotations)
+	mergeMaps(ds.Labels, mutated.Labels)
+	mutated.Spec.Template.Labels = ds.Spec.Template.Labels
+	mutated.Spec.Template.Spec.Affinity = ds.Spec.Template.Spec.Affinity
+	mutated.Spec.Template.Spec.Tolerations = ds.Spec.Template.Spec.Tolerations
+	mutated.Spec.Template.Spec.HostPID = ds.Spec.Template.Spec.HostPID
+	if len(mutated.Spec.Template.Spec.Containers) != len(ds.Spec.Template.Spec.Containers) {
+		mutated.Spec.Template.Spec.Containers = ds.Spec.Template.Spec.Containers
+	}
+	for i, container := range ds.Spec.Template.Spec.Containers {
+		mutated.Spec.Template.Spec.Containers[i].Name = container.Name
+		mutated.Spec.Template.Spec.Containers[i].Image = container.Image
+		mutated.Spec.Template.Spec.Containers[i].Env = container.Env
+		mutated.Spec.Template.Spec.Containers[i].SecurityContext = container.SecurityContext
+	}
+
+	// we do not need to update if it no change between mutated and existing object
+	if apiequality.Semantic.DeepEqual(existing.Spec, mutated.Spec) &&
+		apiequality.Semantic.DeepEqual(existing.Labels, mutated.Labels) &&
+		apiequality.Semantic.DeepEqual(existing.Annotations, mutated.Annotations) {
+		return nil, nil
+	}
+
+	return mutated, nil
+}
+
+func (r *ReconcilePerformanceProfile) createOrUpdateDaemonSet(ds *appsv1.DaemonSet) error {
+	_, err := r.getDaemonSet(ds.Name, ds.Namespace)
+	if errors.IsNotFound(err) {
+		klog.Infof("Create daemon set %q under the namespace %q", ds.Name, ds.Namespace)
+		if err := r.client.Create(context.TODO(), ds); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("Update daemon set %q under the namespace %q", ds.Name, ds.Namespace)
+	return r.client.Update(context.TODO(), ds)
+}
+
+func (r *ReconcilePerformanceProfile) deleteDaemonSet(name, namespace string) error {
+	ds, err := r.getDaemonSet(name, namespace)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return r.client.Delete(context.TODO(), ds)
+}
+
+func (r *ReconcilePerformanceProfile) getContainerRuntimeConfig(name string) (*mcov1.ContainerRuntimeConfig, error) {
+	crc := &mcov1.ContainerRuntimeConfig{}
+	key := types.NamespacedName{
+		Name:      name,
+		Namespace: metav1.NamespaceNone,
+	}
+	if err := r.client.Get(context.TODO(), key, crc); err != nil {
+		return nil, err
+	}
+	return crc, nil
+}
+
+func (r *ReconcilePerformanceProfile) getMutatedContainerRuntimeConfig(crc *mcov1.ContainerRuntimeConfig) (*mcov1.ContainerRuntimeConfig, error) {
+	existing, err := r.getContainerRuntimeConfig(crc.Name)
+	if errors.IsNotFound(err) {
+		return crc, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	mutated := existing.DeepCopy()
+	mergeMaps(crc.Annotations, mutated.Annotations)
+	mergeMaps(crc.Labels, mutated.Labels)
+	mutated.Spec = crc.Spec
+
+	// we do not need to update if it no change between mutated and existing object
+	if apiequality.Semantic.DeepEqual(existing.Spec, mutated.Spec) &&
+		apiequality.Semantic.DeepEqual(existing.Labels, mutated.Labels) &&
+		apiequality.Semantic.DeepEqual(existing.Annotations, mutated.Annotations) {
+		return nil, nil
+	}
+
+	return mutated, nil
+}
+
+func (r *ReconcilePerformanceProfile) createOrUpdateContainerRuntimeConfig(crc *mcov1.ContainerRuntimeConfig) error {
+	_, err := r.getContainerRuntimeConfig(crc.Name)
+	if errors.IsNotFound(err) {
+		klog.Infof("Create container-runtime-config %q", crc.Name)
+		if err := r.client.Create(context.TODO(), crc); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("Update container-runtime-config %q", crc.Name)
+	return r.client.Update(context.TODO(), crc)
+}
+
+func (r *ReconcilePerformanceProfile) deleteContainerRuntimeConfig(name string) error {
+	crc, err := r.getContainerRuntimeConfig(name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return r.client.Delete(context.TODO(), crc)
+}
+
+func (r *ReconcilePerformanceProfile) getMachineConfigPool(name string) (*mcov1.MachineConfigPool, error) {
+	mcp := &mcov1.MachineConfigPool{}
+	key := types.NamespacedName{
+		Name:      name,
+		Namespace: metav1.NamespaceNone,
+	}
+	if err := r.client.Get(context.TODO(), key, mcp); err != nil {
+		return nil, err
+	}
+	return mcp, nil
+}
+
+// getMutatedMachineConfigPool returns the machine config pool that should be created or updated,
+// the pool is managed by the operator only when the cluster does not have other pool for the performance profile
+func (r *ReconcilePerformanceProfile) getMutatedMachineConfigPool(profile *performancev1.PerformanceProfile) (*mcov1.MachineConfigPool, error) {
+	if !r.mcpCreation {
+		return nil, nil
+	}
+
+	mcpList := &mcov1.MachineConfigPoolList{}
+	if err := r.client.List(context.TODO(), mcpList, client.MatchingLabels(profileutil.GetMachineConfigPoolSelector(profile))); err != nil {
+		return nil, err
+	}
+
+	var existing *mcov1.MachineConfigPool
+	for i := range mcpList.Items {
+		if !metav1.IsControlledBy(&mcpList.Items[i], profile) {
+			// the machine config pool created by a user already targets the performance profile
+			return nil, nil
+		}
+		existing = &mcpList.Items[i]
+	}
+
+	mcp, err := machineconfigpool.New(profile)
+	if err != nil {
+		klog.Warningf("the machine config pool for the performance profile %q will not be created: %v", profile.Name, err)
+		return nil, nil
+	}
+	if err := controllerutil.SetControllerReference(profile, mcp, r.scheme); err != nil {
+		return nil, err
+	}
+
+	if existing == nil {
+		return mcp, nil
+	}
+
+	mutated := existing.DeepCopy()
+	mergeMaps(mcp.Annotations, mutated.Annotations)
+	mergeMaps(mcp.Labels, mutated.Labels)
+	mutated.Spec.MachineConfigSelector = mcp.Spec.MachineConfigSelector
+	mutated.Spec.NodeSelector = mcp.Spec.NodeSelector
+
+	// we do not need to update if it no change between mutated and existing object
+	if apiequality.Semantic.DeepEqual(existing.Spec, mutated.Spec) &&
+		apiequality.Semantic.DeepEqual(existing.Labels, mutated.Labels) &&
+		apiequality.Semantic.DeepEqual(existing.Annotations, mutated.Annotations) {
+		return nil, nil
+	}
+
+	return mutated, nil
+}
+
+func (r *ReconcilePerformanceProfile) createOrUpdateMachineConfigPool(mcp *mcov1.MachineConfigPool) error {
+	_, err := r.getMachineConfigPool(mcp.Name)
+	if errors.IsNotFound(err) {
+		klog.Infof("Create machine-config-pool %q", mcp.Name)
+		if err := r.client.Create(context.TODO(), mcp); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	klog.Infof("Update machine-config-pool %q", mcp.Name)
+	return r.client.Update(context.TODO(), mcp)
+}
+
+// deleteMachineConfigPool deletes the machine config pool only when it was created by the operator
+func (r *ReconcilePerformanceProfile) deleteMachineConfigPool(name string, profile *performancev1.PerformanceProfile) error {
+	mcp, err := r.getMachineConfigPool(name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !metav1.IsControlledBy(mcp, profile) {
+		return nil
+	}
+	return r.client.Delete(context.TODO(), mcp)
+}
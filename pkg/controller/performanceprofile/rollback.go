@@ -0,0 +1,166 @@
+package performanceprofile
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+)
+
+const (
+	// backupConfigSuffix is the name suffix of the machine config that keeps the spec applied before the last update,
+	// the backup machine config has no labels, so no machine config pool renders it
+	backupConfigSuffix = "-backup"
+	// configUpdatedAnnotation keeps the time the last machine config update was applied
+	configUpdatedAnnotation = "performance.openshift.io/config-updated"
+	// rolledBackGenerationAnnotation keeps the performance profile generation that was rolled back
+	rolledBackGenerationAnnotation = "performance.openshift.io/rolled-back-generation"
+)
+
+// shouldRememberPreviousConfig returns true when the previous machine config spec is needed
+// either for the manual or for the automatic rollback
+func (r *ReconcilePerformanceProfile) shouldRememberPreviousConfig() bool {
+	return r.machineConfigBackup || r.rollbackTimeout > 0
+}
+
+func getBackupMachineConfigName(name string) string {
+	return name + backupConfigSuffix
+}
+
+// backupMachineConfig stores the spec of the existing machine config under the backup machine config together
+// with the time of the update, so it can be restored when the machine config pool fails to apply the update
+// to the desired machine config. The spec with scripts does not fit into the annotation size limit,
+// so it is kept in the separate object owned by the same profile.
+func (r *ReconcilePerformanceProfile) backupMachineConfig(existing *mcov1.MachineConfig, mc *mcov1.MachineConfig, now time.Time) error {
+	backup := &mcov1.MachineConfig{
+		TypeMeta: existing.TypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            getBackupMachineConfigName(existing.Name),
+			Annotations:     map[string]string{configUpdatedAnnotation: now.UTC().Format(time.RFC3339)},
+			OwnerReferences: mc.OwnerReferences,
+		},
+		Spec: *existing.Spec.DeepCopy(),
+	}
+
+	current, err := r.getMachineConfig(backup.Name)
+	if errors.IsNotFound(err) {
+		klog.Infof("Create machine-config %q", backup.Name)
+		return r.client.Create(context.TODO(), backup)
+	}
+	if err != nil {
+		return err
+	}
+
+	current.Annotations = backup.Annotations
+	current.Spec = backup.Spec
+	klog.Infof("Update machine-config %q", backup.Name)
+	return r.client.Update(context.TODO(), current)
+}
+
+// rollbackMachineConfig restores the previous machine config spec when one of the profile machine config pools
+// stays degraded longer than the rollback timeout after the machine config update, it returns a non empty message
+// when the machine config was rolled back for the current profile generation and the time left until the rollback
+// while one of the pools is degraded, so the reconcile loop can check the pools again once the timeout expires
+func (r *ReconcilePerformanceProfile) rollbackMachineConfig(profile *performancev1.PerformanceProfile) (string, time.Duration, error) {
+	if r.rollbackTimeout == 0 {
+		return "", 0, nil
+	}
+
+	name := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+	mc, err := r.getMachineConfig(name)
+	if errors.IsNotFound(err) {
+		return "", 0, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	generation := strconv.FormatInt(profile.Generation, 10)
+	message := fmt.Sprintf("Machine config %q was rolled back to the previous configuration, because the machine config pool stayed degraded longer than %s", mc.Name, r.rollbackTimeout)
+	if mc.Annotations[rolledBackGenerationAnnotation] == generation {
+		return message, 0, nil
+	}
+
+	backup, err := r.getMachineConfig(getBackupMachineConfigName(name))
+	if errors.IsNotFound(err) {
+		return "", 0, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	// without the update time we can not know whether the pool degraded because of the update
+	updated, err := time.Parse(time.RFC3339, backup.Annotations[configUpdatedAnnotation])
+	if err != nil {
+		klog.Warningf("The machine config %q has invalid update time %q, the rollback is skipped", backup.Name, backup.Annotations[configUpdatedAnnotation])
+		return "", 0, nil
+	}
+
+	degraded, timeLeft, err := r.getMCPDegradedTimeLeft(profile, updated, r.rollbackTimeout)
+	if err != nil || !degraded {
+		return "", 0, err
+	}
+	if timeLeft > 0 {
+		return "", timeLeft, nil
+	}
+
+	mc.Spec = backup.Spec
+	if mc.Annotations == nil {
+		mc.Annotations = map[string]string{}
+	}
+	mc.Annotations[rolledBackGenerationAnnotation] = generation
+
+	klog.Infof("Roll back machine-config %q", mc.Name)
+	if err := r.client.Update(context.TODO(), mc); err != nil {
+		return "", 0, err
+	}
+
+	if err := r.deleteMachineConfig(backup.Name); err != nil {
+		return "", 0, err
+	}
+
+	r.recorder.Eventf(profile, corev1.EventTypeWarning, "Rollback succeeded", message)
+	return message, 0, nil
+}
+
+// getMCPDegradedTimeLeft returns true when one of the profile machine config pools is degraded together with
+// the shortest time left until the pool stays degraded longer than the timeout, the degradation is counted
+// from the machine config update at the earliest, so the pool degraded before the update does not trigger
+// the immediate rollback
+func (r *ReconcilePerformanceProfile) getMCPDegradedTimeLeft(profile *performancev1.PerformanceProfile, updated time.Time, timeout time.Duration) (bool, time.Duration, error) {
+	mcps, err := r.getMachineConfigPoolsByProfile(profile)
+	if err != nil {
+		return false, 0, err
+	}
+
+	degraded := false
+	var timeLeft time.Duration
+	for _, mcp := range mcps {
+		for _, condition := range mcp.Status.Conditions {
+			if !isMCPDegradedCondition(condition) {
+				continue
+			}
+
+			degradedSince := condition.LastTransitionTime.Time
+			if degradedSince.Before(updated) {
+				degradedSince = updated
+			}
+
+			left := timeout - time.Since(degradedSince)
+			if !degraded || left < timeLeft {
+				timeLeft = left
+			}
+			degraded = true
+		}
+	}
+	return degraded, timeLeft, nil
+}
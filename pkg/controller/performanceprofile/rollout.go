@@ -0,0 +1,128 @@
+package performanceprofile
+
+import (
+	"context"
+	"time"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+)
+
+const (
+	// rolloutPausedAnnotation marks the machine config pool paused by the operator,
+	// so the operator never unpauses the pool paused by a user
+	rolloutPausedAnnotation = "performance.openshift.io/coordinated-rollout-paused"
+	// rolloutLastChangeAnnotation keeps the time of the last performance profile change applied to the paused pool
+	rolloutLastChangeAnnotation = "performance.openshift.io/coordinated-rollout-last-change"
+)
+
+// rolloutSettleDuration is the duration without performance profile changes after which the operator
+// unpauses the machine config pool
+var rolloutSettleDuration = time.Minute
+
+func isCoordinatedRollout(mcp *mcov1.MachineConfigPool) bool {
+	return mcp.Annotations[performancev1.MachineConfigPoolCoordinatedRolloutAnnotation] == "true"
+}
+
+// pauseMachineConfigPools pauses machine config pools with the coordinated rollout annotation that target the profile,
+// it should be called before the operator updates profile objects, so the pool applies all changes at once
+func (r *ReconcilePerformanceProfile) pauseMachineConfigPools(profile *performancev1.PerformanceProfile, now time.Time) error {
+	mcps, err := r.getMachineConfigPoolsByProfile(profile)
+	if err != nil {
+		return err
+	}
+
+	for i := range mcps {
+		mcp := &mcps[i]
+		if !isCoordinatedRollout(mcp) {
+			continue
+		}
+
+		if !mcp.Spec.Paused {
+			klog.Infof("Pause machine-config-pool %q to apply the performance profile %s", mcp.Name, profile.Name)
+			mcp.Spec.Paused = true
+			mcp.Annotations[rolloutPausedAnnotation] = "true"
+			r.recorder.Eventf(profile, corev1.EventTypeNormal, "RolloutPaused", "Paused the machine config pool %q until performance profile changes settle", mcp.Name)
+		} else if _, ok := mcp.Annotations[rolloutPausedAnnotation]; !ok {
+			// the pool was paused by a user
+			continue
+		}
+
+		mcp.Annotations[rolloutLastChangeAnnotation] = now.UTC().Format(time.RFC3339)
+		if err := r.client.Update(context.TODO(), mcp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unpauseSettledMachineConfigPools unpauses machine config pools paused by the operator once the settle duration
+// passed since the last performance profile change, it returns the duration after which the next pool can be unpaused
+func (r *ReconcilePerformanceProfile) unpauseSettledMachineConfigPools(profile *performancev1.PerformanceProfile, now time.Time) (time.Duration, error) {
+	mcps, err := r.getMachineConfigPoolsByProfile(profile)
+	if err != nil {
+		return 0, err
+	}
+
+	var requeueAfter time.Duration
+	for i := range mcps {
+		mcp := &mcps[i]
+		if _, ok := mcp.Annotations[rolloutPausedAnnotation]; !ok {
+			continue
+		}
+
+		// the pool without the valid change time is unpaused right away, because we can not know
+		// when the last change was applied
+		lastChange, err := time.Parse(time.RFC3339, mcp.Annotations[rolloutLastChangeAnnotation])
+		if err == nil {
+			if remaining := lastChange.Add(rolloutSettleDuration).Sub(now); remaining > 0 {
+				if requeueAfter == 0 || remaining < requeueAfter {
+					requeueAfter = remaining
+				}
+				continue
+			}
+		}
+
+		klog.Infof("Unpause machine-config-pool %q, performance profile changes settled", mcp.Name)
+		if err := r.unpauseMachineConfigPool(profile, mcp); err != nil {
+			return 0, err
+		}
+	}
+	return requeueAfter, nil
+}
+
+// unpauseMachineConfigPools unpauses machine config pools paused by the operator without waiting for the settle
+// duration, it should be called once the profile is deleted, otherwise nothing unpauses the pool anymore
+func (r *ReconcilePerformanceProfile) unpauseMachineConfigPools(profile *performancev1.PerformanceProfile) error {
+	mcps, err := r.getMachineConfigPoolsByProfile(profile)
+	if err != nil {
+		return err
+	}
+
+	for i := range mcps {
+		mcp := &mcps[i]
+		if _, ok := mcp.Annotations[rolloutPausedAnnotation]; !ok {
+			continue
+		}
+
+		klog.Infof("Unpause machine-config-pool %q, performance profile %s is deleted", mcp.Name, profile.Name)
+		if err := r.unpauseMachineConfigPool(profile, mcp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ReconcilePerformanceProfile) unpauseMachineConfigPool(profile *performancev1.PerformanceProfile, mcp *mcov1.MachineConfigPool) error {
+	mcp.Spec.Paused = false
+	delete(mcp.Annotations, rolloutPausedAnnotation)
+	delete(mcp.Annotations, rolloutLastChangeAnnotation)
+	if err := r.client.Update(context.TODO(), mcp); err != nil {
+		return err
+	}
+	r.recorder.Eventf(profile, corev1.EventTypeNormal, "RolloutResumed", "Unpaused the machine config pool %q to roll out performance profile changes", mcp.Name)
+	return nil
+}
@@ -3,6 +3,7 @@ package performanceprofile
 import (
 	"bytes"
 	"context"
+	"strings"
 	"time"
 
 	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
@@ -16,16 +17,25 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog"
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpuset"
 )
 
 const (
-	conditionReasonValidationFailed         = "ValidationFailed"
-	conditionReasonComponentsCreationFailed = "ComponentCreationFailed"
-	conditionReasonMCPDegraded              = "MCPDegraded"
-	conditionFailedGettingMCPStatus         = "GettingMCPStatusFailed"
+	conditionReasonValidationFailed            = "ValidationFailed"
+	conditionReasonComponentsCreationFailed    = "ComponentCreationFailed"
+	conditionReasonMCPDegraded                 = "MCPDegraded"
+	conditionFailedGettingMCPStatus            = "GettingMCPStatusFailed"
+	conditionReasonMachineConfigRolledBack     = "MachineConfigRolledBack"
+	conditionReasonComponentsDrifted           = "ComponentsDrifted"
+	conditionReasonKernelTypeTransitionPending = "KernelTypeTransitionPending"
 )
 
-func (r *ReconcilePerformanceProfile) updateStatus(profile *performancev1.PerformanceProfile, conditions []conditionsv1.Condition) error {
+// conditionTypeDrifted indicates that performance profile objects differ from the desired state,
+// it is reported only in the drift detection mode
+const conditionTypeDrifted conditionsv1.ConditionType = "Drifted"
+
+// updateStatus updates the performance profile status, nil rebootRequired keeps the current value
+func (r *ReconcilePerformanceProfile) updateStatus(profile *performancev1.PerformanceProfile, conditions []conditionsv1.Condition, rebootRequired *bool) error {
 	profileCopy := profile.DeepCopy()
 
 	if conditions != nil {
@@ -35,7 +45,11 @@ func (r *ReconcilePerformanceProfile) updateStatus(profile *performancev1.Perfor
 	// check if we need to update the status
 	modified := false
 
-	// since we always set the same four conditions, we don't need to check if we need to remove old conditions
+	// the drift detection adds the fifth condition, so we should check if conditions were removed
+	if len(profileCopy.Status.Conditions) != len(profile.Status.Conditions) {
+		modified = true
+	}
+
 	for _, newCondition := range profileCopy.Status.Conditions {
 		oldCondition := conditionsv1.FindStatusCondition(profile.Status.Conditions, newCondition.Type)
 		if oldCondition == nil {
@@ -69,6 +83,36 @@ func (r *ReconcilePerformanceProfile) updateStatus(profile *performancev1.Perfor
 		modified = true
 	}
 
+	isolatedCount, err := profileutil.IsolatedCount(profile, r.topologyProvider)
+	if err != nil {
+		klog.Errorf("failed to calculate the isolated CPUs count for the performance profile %q: %v", profile.Name, err)
+	} else if profileCopy.Status.IsolatedCPUCount == nil || *profileCopy.Status.IsolatedCPUCount != int32(isolatedCount) {
+		isolatedCPUCount := int32(isolatedCount)
+		profileCopy.Status.IsolatedCPUCount = &isolatedCPUCount
+		modified = true
+	}
+
+	housekeeping, err := profileutil.HousekeepingCPUs(profile, r.topologyProvider)
+	if err != nil {
+		klog.Errorf("failed to calculate housekeeping CPUs for the performance profile %q: %v", profile.Name, err)
+	} else if len(housekeeping) == 0 {
+		if profileCopy.Status.HousekeepingCPUs != nil {
+			profileCopy.Status.HousekeepingCPUs = nil
+			modified = true
+		}
+	} else {
+		housekeepingCPUs := performancev1.CPUSet(cpuset.NewCPUSet(housekeeping...).String())
+		if profileCopy.Status.HousekeepingCPUs == nil || *profileCopy.Status.HousekeepingCPUs != housekeepingCPUs {
+			profileCopy.Status.HousekeepingCPUs = &housekeepingCPUs
+			modified = true
+		}
+	}
+
+	if rebootRequired != nil && profileCopy.Status.RebootRequired != *rebootRequired {
+		profileCopy.Status.RebootRequired = *rebootRequired
+		modified = true
+	}
+
 	if !modified {
 		return nil
 	}
@@ -168,8 +212,46 @@ func (r *ReconcilePerformanceProfile) getProgressingConditions(reason string, me
 	}
 }
 
-func (r *ReconcilePerformanceProfile) getMCPConditionsByProfile(profile *performancev1.PerformanceProfile) ([]conditionsv1.Condition, error) {
+func (r *ReconcilePerformanceProfile) getDriftConditions(drifts []string) []conditionsv1.Condition {
+	now := time.Now()
+	drifted := conditionsv1.Condition{
+		Type:               conditionTypeDrifted,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Time{Time: now},
+		LastHeartbeatTime:  metav1.Time{Time: now},
+	}
+	if len(drifts) > 0 {
+		drifted.Status = corev1.ConditionTrue
+		drifted.Reason = conditionReasonComponentsDrifted
+		drifted.Message = strings.Join(drifts, "; ")
+	}
+	return append(r.getAvailableConditions(), drifted)
+}
+
+func (r *ReconcilePerformanceProfile) getMCPConditions(mcps []mcov1.MachineConfigPool) []conditionsv1.Condition {
+	message := bytes.Buffer{}
+	for _, mcp := range mcps {
+		for _, condition := range mcp.Status.Conditions {
+			if isMCPDegradedCondition(condition) {
+				if len(condition.Reason) > 0 {
+					message.WriteString("Machine config pool " + mcp.GetName() + " Degraded Reason: " + condition.Reason + ".\n")
+				}
+				if len(condition.Message) > 0 {
+					message.WriteString("Machine config pool " + mcp.GetName() + " Degraded Message: " + condition.Message + ".\n")
+				}
+			}
+		}
+	}
+
+	messageString := message.String()
+	if len(messageString) == 0 {
+		return nil
+	}
+
+	return r.getDegradedConditions(conditionReasonMCPDegraded, messageString)
+}
 
+func (r *ReconcilePerformanceProfile) getMachineConfigPoolsByProfile(profile *performancev1.PerformanceProfile) ([]mcov1.MachineConfigPool, error) {
 	mcpList := &mcov1.MachineConfigPoolList{}
 
 	if err := r.client.List(context.TODO(), mcpList); err != nil {
@@ -179,8 +261,8 @@ func (r *ReconcilePerformanceProfile) getMCPConditionsByProfile(profile *perform
 
 	mcpItems := removeMCPDuplicateEntries(mcpList.Items)
 	performanceProfileLabels := labels.Set(profileutil.GetMachineConfigPoolSelector(profile))
-	message := bytes.Buffer{}
 
+	var mcps []mcov1.MachineConfigPool
 	for _, mcp := range mcpItems {
 		selector, err := metav1.LabelSelectorAsSelector(mcp.Spec.MachineConfigSelector)
 		if err != nil {
@@ -188,25 +270,38 @@ func (r *ReconcilePerformanceProfile) getMCPConditionsByProfile(profile *perform
 			return nil, err
 		}
 		if selector.Matches(performanceProfileLabels) {
-			for _, condition := range mcp.Status.Conditions {
-				if (condition.Type == mcov1.MachineConfigPoolNodeDegraded || condition.Type == mcov1.MachineConfigPoolRenderDegraded) && condition.Status == corev1.ConditionTrue {
-					if len(condition.Reason) > 0 {
-						message.WriteString("Machine config pool " + mcp.GetName() + " Degraded Reason: " + condition.Reason + ".\n")
-					}
-					if len(condition.Message) > 0 {
-						message.WriteString("Machine config pool " + mcp.GetName() + " Degraded Message: " + condition.Message + ".\n")
-					}
-				}
-			}
+			mcps = append(mcps, mcp)
 		}
 	}
+	return mcps, nil
+}
 
-	messageString := message.String()
-	if len(messageString) == 0 {
-		return nil, nil
+// isRebootRequired returns true when nodes of the machine config pools did not apply the profile machine config yet,
+// the machine config daemon reboots nodes to apply the new configuration
+func isRebootRequired(profile *performancev1.PerformanceProfile, mcps []mcov1.MachineConfigPool) bool {
+	mcName := components.GetComponentName(profile.Name, components.ComponentNamePrefix)
+	for _, mcp := range mcps {
+		if !hasMachineConfigSource(mcp.Status.Configuration.Source, mcName) ||
+			mcp.Spec.Configuration.Name != mcp.Status.Configuration.Name ||
+			mcp.Status.UpdatedMachineCount < mcp.Status.MachineCount {
+			return true
+		}
+	}
+	return false
+}
+
+func hasMachineConfigSource(sources []corev1.ObjectReference, name string) bool {
+	for _, source := range sources {
+		if source.Name == name {
+			return true
+		}
 	}
+	return false
+}
 
-	return r.getDegradedConditions(conditionReasonMCPDegraded, messageString), nil
+func isMCPDegradedCondition(condition mcov1.MachineConfigPoolCondition) bool {
+	return (condition.Type == mcov1.MachineConfigPoolNodeDegraded || condition.Type == mcov1.MachineConfigPoolRenderDegraded) &&
+		condition.Status == corev1.ConditionTrue
 }
 
 func removeMCPDuplicateEntries(mcps []mcov1.MachineConfigPool) []mcov1.MachineConfigPool {
@@ -0,0 +1,104 @@
+package performanceprofile
+
+import (
+	"context"
+	"reflect"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	profileutil "github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/profile"
+	conditionsv1 "github.com/openshift/custom-resource-status/conditions/v1"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog"
+
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// blank assignment to verify that statusSyncReconciler implements reconcile.Reconciler
+var _ reconcile.Reconciler = &statusSyncReconciler{}
+
+// statusSyncReconciler refreshes the performance profile status from the live state of its machine config pools,
+// it never renders or updates objects of the profile, so the progress of machine config pools is reflected
+// in the status without running the full reconcile loop
+type statusSyncReconciler struct {
+	r *ReconcilePerformanceProfile
+}
+
+// Reconcile updates the reboot required status and, when the profile status reflects machine config pools,
+// the profile conditions
+func (s *statusSyncReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	profile := &performancev1.PerformanceProfile{}
+	if err := s.r.client.Get(context.TODO(), request.NamespacedName, profile); err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	// the full reconcile loop did not create objects of the profile yet or already deletes them
+	if profile.DeletionTimestamp != nil || !hasFinalizer(profile, finalizer) {
+		return reconcile.Result{}, nil
+	}
+
+	mcps, err := s.r.getMachineConfigPoolsByProfile(profile)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	s.r.applyTimeTracker.observe(profile, mcps)
+
+	// other conditions report the state the full reconcile loop found, like the validation failure,
+	// they should stay until the next full reconcile loop
+	var conditions []conditionsv1.Condition
+	if isMCPStatus(profile) {
+		conditions = s.r.getMCPConditions(mcps)
+		if conditions == nil {
+			conditions = s.r.getAvailableConditions()
+		}
+	}
+
+	rebootRequired := isRebootRequired(profile, mcps)
+	if err := s.r.updateStatus(profile, conditions, &rebootRequired); err != nil {
+		klog.Errorf("failed to sync performance profile %q status: %v", profile.Name, err)
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// isMCPStatus returns true when profile conditions were derived from machine config pools,
+// i.e. the profile is available or degraded because of its machine config pools
+func isMCPStatus(profile *performancev1.PerformanceProfile) bool {
+	if profileutil.IsDriftDetectionOnly(profile) {
+		return false
+	}
+
+	progressing := conditionsv1.FindStatusCondition(profile.Status.Conditions, conditionsv1.ConditionProgressing)
+	if progressing != nil && progressing.Status == corev1.ConditionTrue {
+		return false
+	}
+
+	degraded := conditionsv1.FindStatusCondition(profile.Status.Conditions, conditionsv1.ConditionDegraded)
+	if degraded == nil {
+		return false
+	}
+	return degraded.Status == corev1.ConditionFalse || degraded.Reason == conditionReasonMCPDegraded
+}
+
+// isMCPStatusChanged returns true when the machine config pool status changed, including the machine counts
+// and the rendered configuration that do not change machine config pool conditions
+func isMCPStatusChanged(e event.UpdateEvent) bool {
+	mcpOld, ok := e.ObjectOld.(*mcov1.MachineConfigPool)
+	if !ok {
+		klog.Error("Update event has no old machine config pool")
+		return false
+	}
+	mcpNew, ok := e.ObjectNew.(*mcov1.MachineConfigPool)
+	if !ok {
+		klog.Error("Update event has no new machine config pool")
+		return false
+	}
+	return !reflect.DeepEqual(mcpOld.Status, mcpNew.Status)
+}
@@ -0,0 +1,159 @@
+package performanceprofile
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog"
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpuset"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// nodeTopologyProvider provides the topology of the profile nodes from the node status and the topology annotations,
+// nodes without topology annotations are omitted, so the topology is unknown when no profile node is annotated
+type nodeTopologyProvider struct {
+	client client.Client
+}
+
+// GetNUMANodesCount returns the smallest number of NUMA nodes among the annotated profile nodes
+func (p *nodeTopologyProvider) GetNUMANodesCount(profile *performancev1.PerformanceProfile) (int, error) {
+	nodesCPUs, err := p.getNodesCPUSets(profile, performancev1.NodeNUMANodesCPUsAnnotation)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, numaNodes := range nodesCPUs {
+		if count == 0 || len(numaNodes) < count {
+			count = len(numaNodes)
+		}
+	}
+	return count, nil
+}
+
+// GetCoreSiblings returns hardware threads of each core of the annotated profile nodes,
+// the topology is unknown when the annotated profile nodes have different cores
+func (p *nodeTopologyProvider) GetCoreSiblings(profile *performancev1.PerformanceProfile) ([]cpuset.CPUSet, error) {
+	nodesCPUs, err := p.getNodesCPUSets(profile, performancev1.NodeCoreSiblingsAnnotation)
+	if err != nil {
+		return nil, err
+	}
+	return getCommonCPUSets(nodesCPUs, performancev1.NodeCoreSiblingsAnnotation), nil
+}
+
+// GetNUMANodesCPUs returns CPUs of each NUMA node of the annotated profile nodes,
+// the topology is unknown when the annotated profile nodes have different NUMA nodes
+func (p *nodeTopologyProvider) GetNUMANodesCPUs(profile *performancev1.PerformanceProfile) (map[int]cpuset.CPUSet, error) {
+	nodesCPUs, err := p.getNodesCPUSets(profile, performancev1.NodeNUMANodesCPUsAnnotation)
+	if err != nil {
+		return nil, err
+	}
+
+	numaNodesCPUs := map[int]cpuset.CPUSet{}
+	for node, cpus := range getCommonCPUSets(nodesCPUs, performancev1.NodeNUMANodesCPUsAnnotation) {
+		numaNodesCPUs[node] = cpus
+	}
+	return numaNodesCPUs, nil
+}
+
+// GetMemoryKilobytes returns the smallest memory capacity among the profile nodes in kilobytes
+func (p *nodeTopologyProvider) GetMemoryKilobytes(profile *performancev1.PerformanceProfile) (int64, error) {
+	nodes, err := p.getNodes(profile)
+	if err != nil {
+		return 0, err
+	}
+
+	var memory int64
+	for _, node := range nodes {
+		capacity := node.Status.Capacity.Memory().Value() / 1024
+		if capacity == 0 {
+			continue
+		}
+		if memory == 0 || capacity < memory {
+			memory = capacity
+		}
+	}
+	return memory, nil
+}
+
+func (p *nodeTopologyProvider) getNodes(profile *performancev1.PerformanceProfile) ([]corev1.Node, error) {
+	nodes := &corev1.NodeList{}
+	if err := p.client.List(context.TODO(), nodes, client.MatchingLabels(profile.Spec.NodeSelector)); err != nil {
+		return nil, err
+	}
+	return nodes.Items, nil
+}
+
+// getNodesCPUSets returns CPU sets of the topology annotation of each annotated profile node,
+// nodes with malformed annotations are omitted, so they do not block the profile reconciliation
+func (p *nodeTopologyProvider) getNodesCPUSets(profile *performancev1.PerformanceProfile, annotation string) ([][]cpuset.CPUSet, error) {
+	nodes, err := p.getNodes(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodesCPUs [][]cpuset.CPUSet
+	for _, node := range nodes {
+		value, ok := node.Annotations[annotation]
+		if !ok {
+			continue
+		}
+
+		cpuSets, err := parseCPUSets(value)
+		if err != nil {
+			klog.Warningf("failed to parse the node %q annotation %s value %q, the node topology is unknown: %v", node.Name, annotation, value, err)
+			continue
+		}
+		nodesCPUs = append(nodesCPUs, cpuSets)
+	}
+	return nodesCPUs, nil
+}
+
+// parseCPUSets parses the list of CPU sets separated by the semicolon
+func parseCPUSets(value string) ([]cpuset.CPUSet, error) {
+	var cpuSets []cpuset.CPUSet
+	for _, cpus := range strings.Split(value, ";") {
+		cpuSet, err := components.ParseCPUList(cpus)
+		if err != nil {
+			return nil, err
+		}
+		if cpuSet.IsEmpty() {
+			return nil, fmt.Errorf("the CPU set %q is empty", cpus)
+		}
+		cpuSets = append(cpuSets, cpuSet)
+	}
+	return cpuSets, nil
+}
+
+// getCommonCPUSets returns CPU sets all nodes share, it returns nil when nodes have different CPU sets
+func getCommonCPUSets(nodesCPUs [][]cpuset.CPUSet, annotation string) []cpuset.CPUSet {
+	if len(nodesCPUs) == 0 {
+		return nil
+	}
+
+	for _, cpuSets := range nodesCPUs[1:] {
+		if !equalCPUSets(cpuSets, nodesCPUs[0]) {
+			klog.Warningf("the profile nodes have different %s annotation values, the nodes topology is unknown", annotation)
+			return nil
+		}
+	}
+	return nodesCPUs[0]
+}
+
+func equalCPUSets(a []cpuset.CPUSet, b []cpuset.CPUSet) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equals(b[i]) {
+			return false
+		}
+	}
+	return true
+}
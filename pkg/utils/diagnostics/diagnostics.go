@@ -0,0 +1,112 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/kubeletconfig"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/machineconfig"
+	profileutil "github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/profile"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/runtimeclass"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components/tuned"
+	tunedv1 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/tuned/v1"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	nodev1beta1 "k8s.io/api/node/v1beta1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// bundle is the support bundle document, it has the profile, the configuration the operator generates for it
+// and the state of the cluster objects that apply the configuration
+type bundle struct {
+	Profile            *performancev1.PerformanceProfile `json:"profile"`
+	KernelArguments    kernelArguments                   `json:"kernelArguments"`
+	Generated          generatedObjects                  `json:"generated"`
+	Nodes              []nodeState                       `json:"nodes"`
+	MachineConfigPools []mcov1.MachineConfigPool         `json:"machineConfigPools"`
+}
+
+// kernelArguments contains kernel arguments the machine config sets directly and ones the tuned sets
+// via its cmdline options, mapped by the option name
+type kernelArguments struct {
+	MachineConfig []string          `json:"machineConfig"`
+	Tuned         map[string]string `json:"tuned"`
+}
+
+type generatedObjects struct {
+	MachineConfig *mcov1.MachineConfig      `json:"machineConfig"`
+	KubeletConfig *mcov1.KubeletConfig      `json:"kubeletConfig"`
+	Tuned         *tunedv1.Tuned            `json:"tuned"`
+	RuntimeClass  *nodev1beta1.RuntimeClass `json:"runtimeClass"`
+}
+
+// nodeState keeps only the node details relevant for the tuning, the full node object is mostly
+// the kubelet status that does not help to debug the profile
+type nodeState struct {
+	Name        string                 `json:"name"`
+	Labels      map[string]string      `json:"labels,omitempty"`
+	Annotations map[string]string      `json:"annotations,omitempty"`
+	NodeInfo    corev1.NodeSystemInfo  `json:"nodeInfo"`
+	Conditions  []corev1.NodeCondition `json:"conditions,omitempty"`
+}
+
+// SupportBundle returns the JSON document with the effective configuration of the performance profile:
+// the resolved kernel arguments, objects the operator generates for the profile and the state of the profile
+// nodes and machine config pools, the document is meant to be attached to support cases
+func SupportBundle(c client.Reader, assetsDir string, profile *performancev1.PerformanceProfile) ([]byte, error) {
+	mc, err := machineconfig.New(assetsDir, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	kc, err := kubeletconfig.New(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	performanceTuned, err := tuned.NewNodePerformance(assetsDir, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &bundle{
+		Profile: profile,
+		KernelArguments: kernelArguments{
+			MachineConfig: mc.Spec.KernelArguments,
+			Tuned:         tuned.GetKernelCmdlineOptions(performanceTuned),
+		},
+		Generated: generatedObjects{
+			MachineConfig: mc,
+			KubeletConfig: kc,
+			Tuned:         performanceTuned,
+			RuntimeClass:  runtimeclass.New(profile, machineconfig.HighPerformanceRuntime),
+		},
+		Nodes:              []nodeState{},
+		MachineConfigPools: []mcov1.MachineConfigPool{},
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := c.List(context.TODO(), nodes, client.MatchingLabels(profile.Spec.NodeSelector)); err != nil {
+		return nil, err
+	}
+	for _, node := range nodes.Items {
+		b.Nodes = append(b.Nodes, nodeState{
+			Name:        node.Name,
+			Labels:      node.Labels,
+			Annotations: node.Annotations,
+			NodeInfo:    node.Status.NodeInfo,
+			Conditions:  node.Status.Conditions,
+		})
+	}
+
+	mcps := &mcov1.MachineConfigPoolList{}
+	if err := c.List(context.TODO(), mcps, client.MatchingLabels(profileutil.GetMachineConfigPoolSelector(profile))); err != nil {
+		return nil, err
+	}
+	b.MachineConfigPools = append(b.MachineConfigPools, mcps.Items...)
+
+	return json.MarshalIndent(b, "", "  ")
+}
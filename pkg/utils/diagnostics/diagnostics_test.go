@@ -0,0 +1,105 @@
+package diagnostics
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	testutils "github.com/openshift-kni/performance-addon-operators/pkg/utils/testing"
+	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testAssetsDir = "../../../build/assets"
+
+var _ = Describe("Support bundle", func() {
+	var profile *performancev1.PerformanceProfile
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		profile = testutils.NewPerformanceProfile("test")
+		profile.Spec.HugePages.Pages[0].Node = pointer.Int32Ptr(0)
+		scheme = runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(mcov1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	It("should contain the profile, generated objects and the state of the profile nodes and pools", func() {
+		profileNode := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "profile-node",
+				Labels: profile.Spec.NodeSelector,
+			},
+			Status: corev1.NodeStatus{
+				NodeInfo: corev1.NodeSystemInfo{KernelVersion: "4.18.0-193.rt13.60.el8_2.x86_64"},
+			},
+		}
+		otherNode := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-node"},
+		}
+		mcp := &mcov1.MachineConfigPool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "performance",
+				Labels: profile.Spec.MachineConfigPoolSelector,
+			},
+			Status: mcov1.MachineConfigPoolStatus{MachineCount: 1},
+		}
+		c := fake.NewFakeClientWithScheme(scheme, profileNode, otherNode, mcp)
+
+		data, err := SupportBundle(c, testAssetsDir, profile)
+		Expect(err).ToNot(HaveOccurred())
+
+		sections := map[string]json.RawMessage{}
+		Expect(json.Unmarshal(data, &sections)).To(Succeed())
+		Expect(sections).To(HaveKey("profile"))
+		Expect(sections).To(HaveKey("kernelArguments"))
+		Expect(sections).To(HaveKey("generated"))
+		Expect(sections).To(HaveKey("nodes"))
+		Expect(sections).To(HaveKey("machineConfigPools"))
+
+		b := &bundle{}
+		Expect(json.Unmarshal(data, b)).To(Succeed())
+		Expect(b.Profile.Name).To(Equal(profile.Name))
+
+		Expect(b.KernelArguments.Tuned).To(HaveKeyWithValue("isolated_cores", "4-7"))
+		Expect(b.Generated.MachineConfig.Name).To(Equal("performance-test"))
+		Expect(b.Generated.MachineConfig.Spec.KernelType).To(Equal("realtime"))
+		Expect(b.Generated.KubeletConfig.Name).To(Equal("performance-test"))
+		Expect(b.Generated.Tuned.Name).To(Equal("openshift-node-performance-test"))
+		Expect(b.Generated.RuntimeClass.Name).To(Equal("performance-test"))
+
+		Expect(b.Nodes).To(HaveLen(1))
+		Expect(b.Nodes[0].Name).To(Equal(profileNode.Name))
+		Expect(b.Nodes[0].NodeInfo.KernelVersion).To(Equal(profileNode.Status.NodeInfo.KernelVersion))
+
+		Expect(b.MachineConfigPools).To(HaveLen(1))
+		Expect(b.MachineConfigPools[0].Name).To(Equal(mcp.Name))
+		Expect(b.MachineConfigPools[0].Status.MachineCount).To(Equal(int32(1)))
+	})
+
+	It("should contain empty sections when the cluster does not have profile nodes and pools", func() {
+		c := fake.NewFakeClientWithScheme(scheme)
+
+		data, err := SupportBundle(c, testAssetsDir, profile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring(`"nodes": []`))
+		Expect(string(data)).To(ContainSubstring(`"machineConfigPools": []`))
+	})
+
+	It("should fail when the profile can not be rendered", func() {
+		isolated := performancev1.CPUSet("a-b")
+		profile.Spec.CPU.Isolated = &isolated
+		c := fake.NewFakeClientWithScheme(scheme)
+
+		_, err := SupportBundle(c, testAssetsDir, profile)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,175 @@
+package importer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+	"github.com/openshift-kni/performance-addon-operators/pkg/controller/performanceprofile/components"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/kubelet/cm/cpuset"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	argBootImage          = "BOOT_IMAGE"
+	argIsolCPUs           = "isolcpus"
+	argCPUAffinity        = "systemd.cpu_affinity"
+	argDefaultHugepagesSz = "default_hugepagesz"
+	argHugepagesSz        = "hugepagesz"
+	argHugepages          = "hugepages"
+
+	isolCPUsFlagDomain = "domain"
+	// realTimeKernelMarker is a part of the real time kernel image name, e.g. vmlinuz-4.18.0-193.rt13.60.el8.x86_64
+	realTimeKernelMarker = ".rt"
+)
+
+// isolCPUsFlags contains flags that can prefix the CPUs list under the isolcpus kernel argument
+var isolCPUsFlags = map[string]bool{
+	isolCPUsFlagDomain: true,
+	"managed_irq":      true,
+	"nohz":             true,
+}
+
+// FromCmdline builds a draft performance profile from the node kernel command line (/proc/cmdline),
+// it extracts isolated and reserved CPUs, huge pages and the kernel type, unrelated arguments are ignored.
+// The returned profile does not have a name and node selector, so a user should complete it before the creation.
+func FromCmdline(cmdline string) (*performancev1.PerformanceProfile, error) {
+	profile := &performancev1.PerformanceProfile{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: performancev1.SchemeGroupVersion.String(),
+			Kind:       "PerformanceProfile",
+		},
+		Spec: performancev1.PerformanceProfileSpec{
+			CPU: &performancev1.CPU{},
+			RealTimeKernel: &performancev1.RealTimeKernel{
+				Enabled: pointer.BoolPtr(false),
+			},
+		},
+	}
+
+	var hugepages *performancev1.HugePages
+	var pageSize *performancev1.HugePageSize
+	for _, arg := range strings.Fields(cmdline) {
+		key, value := splitArg(arg)
+		switch key {
+		case argBootImage:
+			if strings.Contains(value, realTimeKernelMarker) {
+				profile.Spec.RealTimeKernel.Enabled = pointer.BoolPtr(true)
+			}
+
+		case argIsolCPUs:
+			isolated, balanced, err := parseIsolCPUs(value)
+			if err != nil {
+				return nil, err
+			}
+			profile.Spec.CPU.Isolated = &isolated
+			if !balanced {
+				profile.Spec.CPU.BalanceIsolated = pointer.BoolPtr(false)
+			}
+
+		case argCPUAffinity:
+			reserved, err := parseCPUSet(value)
+			if err != nil {
+				return nil, err
+			}
+			profile.Spec.CPU.Reserved = &reserved
+
+		case argDefaultHugepagesSz:
+			size, err := parseHugePageSize(value)
+			if err != nil {
+				return nil, err
+			}
+			if hugepages == nil {
+				hugepages = &performancev1.HugePages{}
+			}
+			hugepages.DefaultHugePagesSize = &size
+
+		case argHugepagesSz:
+			size, err := parseHugePageSize(value)
+			if err != nil {
+				return nil, err
+			}
+			pageSize = &size
+
+		case argHugepages:
+			// the huge pages count without the preceding size relates to the default huge pages size
+			size := pageSize
+			if size == nil && hugepages != nil {
+				size = hugepages.DefaultHugePagesSize
+			}
+			if size == nil {
+				return nil, fmt.Errorf("failed to find the page size for the huge pages count %q", value)
+			}
+
+			count, err := strconv.ParseInt(value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse the huge pages count %q: %v", value, err)
+			}
+
+			// we do not need to import dummy huge pages arguments
+			if count == 0 {
+				pageSize = nil
+				continue
+			}
+
+			if hugepages == nil {
+				hugepages = &performancev1.HugePages{}
+			}
+			hugepages.Pages = append(hugepages.Pages, performancev1.HugePage{
+				Size:  *size,
+				Count: int32(count),
+			})
+			pageSize = nil
+		}
+	}
+
+	if profile.Spec.CPU.Isolated == nil {
+		return nil, fmt.Errorf("failed to find isolated CPUs under the kernel command line")
+	}
+
+	profile.Spec.HugePages = hugepages
+	return profile, nil
+}
+
+func splitArg(arg string) (string, string) {
+	parts := strings.SplitN(arg, "=", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+// parseIsolCPUs parses the isolcpus kernel argument value, that has format [flag,...]cpu_list,
+// it returns the isolated CPUs and whether or not the isolated CPUs are load balanced
+func parseIsolCPUs(value string) (performancev1.CPUSet, bool, error) {
+	balanced := true
+	parts := strings.Split(value, ",")
+	for len(parts) > 0 && isolCPUsFlags[parts[0]] {
+		if parts[0] == isolCPUsFlagDomain {
+			balanced = false
+		}
+		parts = parts[1:]
+	}
+
+	isolated, err := parseCPUSet(strings.Join(parts, ","))
+	return isolated, balanced, err
+}
+
+func parseCPUSet(value string) (performancev1.CPUSet, error) {
+	cpus, err := cpuset.Parse(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the CPUs list %q: %v", value, err)
+	}
+	return performancev1.CPUSet(cpus.String()), nil
+}
+
+func parseHugePageSize(value string) (performancev1.HugePageSize, error) {
+	size := performancev1.HugePageSize(value)
+	if size != components.HugepagesSize1G && size != components.HugepagesSize2M {
+		return "", fmt.Errorf("the huge page size %q is not supported, it should be equal to %q or %q", value, components.HugepagesSize1G, components.HugepagesSize2M)
+	}
+	return size, nil
+}
@@ -0,0 +1,73 @@
+package importer
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	performancev1 "github.com/openshift-kni/performance-addon-operators/pkg/apis/performance/v1"
+)
+
+const (
+	cmdlineRealTime = "BOOT_IMAGE=(hd0,gpt3)/ostree/rhcos-1234/vmlinuz-4.18.0-193.rt13.60.el8_2.x86_64 rhcos.root=crypt_rootfs " +
+		"console=tty0 ignition.platform.id=metal skew_tick=1 nohz=on rcu_nocbs=2-7 tuned.non_isolcpus=00000003 " +
+		"intel_pstate=disable nosoftlockup tsc=nowatchdog intel_iommu=on iommu=pt isolcpus=managed_irq,2-5,7 " +
+		"systemd.cpu_affinity=0,1,6 default_hugepagesz=1G hugepagesz=1G hugepages=4 hugepagesz=2M hugepages=0"
+	cmdlineDefault = "BOOT_IMAGE=(hd0,gpt3)/ostree/rhcos-1234/vmlinuz-4.18.0-193.el8.x86_64 root=/dev/sda4 " +
+		"isolcpus=domain,managed_irq,4-7 systemd.cpu_affinity=0-3 hugepagesz=2M hugepages=128 quiet"
+)
+
+var _ = Describe("Importer", func() {
+	It("should import the real time kernel command line", func() {
+		profile, err := FromCmdline(cmdlineRealTime)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(*profile.Spec.RealTimeKernel.Enabled).To(BeTrue())
+		Expect(*profile.Spec.CPU.Isolated).To(Equal(performancev1.CPUSet("2-5,7")))
+		Expect(*profile.Spec.CPU.Reserved).To(Equal(performancev1.CPUSet("0-1,6")))
+		Expect(profile.Spec.CPU.BalanceIsolated).To(BeNil())
+
+		Expect(*profile.Spec.HugePages.DefaultHugePagesSize).To(Equal(performancev1.HugePageSize("1G")))
+		Expect(profile.Spec.HugePages.Pages).To(Equal([]performancev1.HugePage{{Size: "1G", Count: 4}}))
+	})
+
+	It("should import the default kernel command line", func() {
+		profile, err := FromCmdline(cmdlineDefault)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(*profile.Spec.RealTimeKernel.Enabled).To(BeFalse())
+		Expect(*profile.Spec.CPU.Isolated).To(Equal(performancev1.CPUSet("4-7")))
+		Expect(*profile.Spec.CPU.Reserved).To(Equal(performancev1.CPUSet("0-3")))
+		Expect(*profile.Spec.CPU.BalanceIsolated).To(BeFalse())
+
+		Expect(profile.Spec.HugePages.DefaultHugePagesSize).To(BeNil())
+		Expect(profile.Spec.HugePages.Pages).To(Equal([]performancev1.HugePage{{Size: "2M", Count: 128}}))
+	})
+
+	It("should use the default huge pages size when the page size is not specified", func() {
+		profile, err := FromCmdline("isolcpus=1-3 default_hugepagesz=2M hugepages=64")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(profile.Spec.HugePages.Pages).To(Equal([]performancev1.HugePage{{Size: "2M", Count: 64}}))
+	})
+
+	It("should ignore unrelated arguments", func() {
+		profile, err := FromCmdline("quiet isolcpus=1-3 mitigations=off hugepages_unrelated=1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*profile.Spec.CPU.Isolated).To(Equal(performancev1.CPUSet("1-3")))
+		Expect(profile.Spec.CPU.Reserved).To(BeNil())
+		Expect(profile.Spec.HugePages).To(BeNil())
+	})
+
+	It("should fail on invalid command line", func() {
+		_, err := FromCmdline("quiet")
+		Expect(err).To(HaveOccurred(), "should fail without isolated CPUs")
+
+		_, err = FromCmdline("isolcpus=1-a")
+		Expect(err).To(HaveOccurred(), "should fail with invalid CPUs list")
+
+		_, err = FromCmdline("isolcpus=1-3 hugepagesz=16M hugepages=1")
+		Expect(err).To(HaveOccurred(), "should fail with unsupported huge page size")
+
+		_, err = FromCmdline("isolcpus=1-3 hugepages=1")
+		Expect(err).To(HaveOccurred(), "should fail without the huge page size")
+	})
+})